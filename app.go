@@ -2,13 +2,16 @@ package main
 
 import (
 	"bufio"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gotk3/gotk3/gdk"
@@ -18,80 +21,411 @@ import (
 )
 
 const (
-	icon                     = `iVBORw0KGgoAAAANSUhEUgAAAMgAAADICAYAAACtWK6eAAAgAElEQVR4Xu1dB3hUVfb/3ZlMS5skM5OeAAmQ0IIiRZBeVLAuCrsKYZUEK9bVta3/xYq9F0pAJVhRFwuoSJVO6BBI6KSRMmmTOply/98dDBKSmXlv5r0pgft9+XB3zj33nHPf771bTiG41ES1wLSFlfESKY2jVsQQK40mUomOAlpYqQag4ZCQaFCoAKhAoAQFBUgTQJsp0ESAElBUg5BKSqgelJZLrCg1E5yRt9DiT++PLBVVgYucObnI9Xdb/TnraMDRU9W9pGaaQgntAaA7gG4AxrjNnAcDCqwiFCcgIccorEcB5C/N0OXzYHGJtAMLXAIIz8fitvkGrUxiGk0lZCShdAQFvYwnC0+SmwDsBLARVLJBZW1ev+Du2EZPCuDvY10CCIcZnL6wYjRAJoDQKQSEfSX8uJF9hNBvLBb8/vld2hw/VsQjol8CSAdmnjK/Sq0IsFxPKJkE4HaPzITXBqELCSUrmtSan5dNJRavieGjA18CyJ8TM+Mzg4aajZMJldxEQa/z0fkSWSz6NYDl+saa//3yYA+jyIP5BfuLGiAT3zuq0AaGTwUwBcANfjFjnhKS4HNCpMuWzAz/wVND+uI4FyVAZizWj7daMZ0A0wFIfXFifEUmlbKyJjl+zZGG+ug3nr9+8jJfkctTclxUAJm+UH8vIeRugPb3lIH9fZyk+LXoFr8WZosS+pqeJYaGxI9ennT9S/6uF1f5Oz1A2IZbKbU+AGA2gCiuhrlEd9YCV6a9h6DA8jbmqKjqbTDUxy15YdItzK6dunVagNz+UU24VG55FKCPAAjq1LMoknK68ENIS/nCLvcqQ/cmQ13sN3Ouuf0OkUTwOttOB5CzG++IJwD670vAcO/56pW0HLGR7J7RcTM0xBvrG6K/e3ps5jRntP72e6cCyIwF+geoBE8DiPa3ifA1eZWKGgzotRgqZRVn0WrqExuqa7u+/9w1tz/FuZOPE3YKgMxYqL+JEvwXwOU+bm+fF4+AQhOeD21YHuKinH89OlKorLJvUXV9ysOvXDfhO59X2ImAfg2Q6Vnl3QmkLwL07/4+Ed6WXyGvhTYsH9rwPGjDj7gtjrElBBVVfTbtO37j9cvujqh1m6GXGPgtQNKz9GyPMReAxEu26xTDhgYXQReWj0jNQQSqKgTXqaY+saWmtutHc665nR2W+F3zO4DMyNIPpqBvAmS431nbRwQmxGL7WkRpD9iAwZZVYjd9depJo0WX8czoaevEHktI/n4FkPTF+mdgxYtCGuBi4qVSViNScwBRmgMICTzjcdVN5kBaWd3z8yfH3JPu8cFdHNAvAHLbfH1qQAB5H5SOd1HPi7qbJuyoDRTsTyJhISLebRXVffMLzgwb8f7UAW3WdOOvvXW0lFrzf/vte8+j145JfB4g6Yv0d4DSjwGi9O60+tfoCnmdDRCREQehDinwOeFr6xKbK2pTH3vx2skfjp10638J6O0A6XlWUPLTmpXLbvQFoX0aIDOy9O/Tsy4ilxpHC4SHnjy3jJIFNHHs5R2yFlMQVi1oLKg+XZHYXgJ6as3K71joslebTwLkn/PLki3SgMUEdKRXreMngwdIm22giNYcQLj6hJ9IDRTntWDtYoNdeQnFG6t/+fZxbyrkcwCZsahqIqWWzwCi86Zh/GFsdXCh7RSKnUYpZPYfNF/VZcs3dTi+02Fc1i9rVn7Lojq91nwKIDOy9PdR4EOvWcNPBtaojyA+eoftUs9fW32VBT+9VQ1ziyMN6KdrVn53pzd19BmApGfpXwbQaXx4xJhUtr+Ii96BqIgDYrD3KM9DGxqxa4XjBCsUmLN25bfPeVSwCwbzCYCkZ+kXAZjpTUP48tihwYVIiNqBaN0eXxaTl2wr36tBZZHZbh+JVGIxtdDU9b8tO8aLscDEXgXInDlzJMfjZzOHtpsF1qtTsJPLGtA1bj0Sord2Cn1alTixpxmbv6x3qFP3IUp0Gz1015Oj7h/oTeW9BpApH5YHK5XS/126/Ot4+qN1+9Atdr0o/lHefODY2L8vrEXpUccXllffo0ZUkgwV1an5j494ONVbMnsFIH9G+/0I0Ev+VBfMPHMYZMBgAOmMrehQC9Z96vjELb6XHGPuDD2nfk1tt4KHr3qiizfs4XGAnAWHeQWAod5Q2JfHZEsptqRiS6vO2tYvMaDwoMOjK4ycHoIuaYo2JqitSyh5aOgzcZ62i0cBMuWb8mClQbLqEjjaT3NK158QH73d0/Pv0fHKTpiwap7j0BBtFxkm3q/uUK76xtjS2YP/L8aTQnsMIFO++UaqMoz9jQLjPKmgr48llzegd9J30IS5H6Tk67puWVaP4znNDsUcMjkYPa+073ZXW5d4+qGhT3f1lK4eA0h6ln45gJs8pZg/jBMSdAaD+10c96LVZ8xY8U4NqIPQk1CtFNc9HI4AuePZK6m4YuvTY2YN88QcewQg6Vn6TwB02tQwrkxUQvQ29Oz6sytd/bJPzo8NyNvk2HnysqsD0W98ICf98k9d8+Xc6/8memJxQQFic1smLM8tCQfFLoAuiJs8bzgFnuCk9UVC1CV2I7on/naRaAswt5IV79SipdlqV2e5UoLrH1EjKJx7Jti8E5NffeXGq58U05CCAWT8pFu/oMBtFwoblDQKoWlTQCQBYurhN7zjo3YgpduPfiOvEILu+70R+3937FaSepUKg27il9/PSqXYf+S2We/cMjxLCDk74iEIQMZNuvV6AD/ZE1Ku6wF1vymQhXXg9i+WZj7IN0q7F327f+uDkoknUkO1BSvfr0Vzvf2vBxt90kNh0MTxf4ka6hIseadvHfrRP1JEKQYkEECmvAPQhxyZWaoIRki/KQhMHCLebPgwZ+aBm5ayFBKJ4wfFh1VwSbTdKxuQu97x3qPr5QqMuC3EJf6sk766V/X2nMlxyx5NEDxCTBiATLx1HQhGc9EwpOe1COl7cbleBatKkZa6FCpFDRcTdRqa2nIzfnmvFqYWx1lTxmaEIi7FydGVE6uU6fseeWL07BShjScIQMZPuuURCvIWV+GUMWlQ97sV0uBIrl38mq5P8rJO6zriaGJyfqhH3mbH9x7xveUYc8dfbiXuTHRpxeUrnhxzN1vuC9aEAcjEWy6nhGwCwO2MjlWtCdJCnTYFypjOXaqDZSnsn7JEsAnzF0ZVxRaseK8azlJujctQIzZFJohaZoscBcWjHnx+0i3vC8KQpY8QitH1ma/e2VSSs5gvv9DeNyE4dSLfbn5D37fHN4jS7PcbeYUSdOuyOhzLcVzmkPlbMb8rIVt1bVLT4YOZMQsESncqGEDSF+nXw2oZVfH7CzDVl/LSWZUwCOq0qZAohDUWLyFEIA4NKsagfh+LwNm3WZafNOO3j53vtybcpUZ0d2G+HudbpLhi8N5nxswUJJG5IABJz6p4DiD/1yqk4cC3qD+6mtcsytRxCE2bCoVO8H0WLzmEJGYJFfr1+EpIln7Ba9MXdTi51/HXo9sABYb/Q5wXIjsSOFZw9RsvTZrsdkYUtwGSvqhqOKh144UzZ6zIR+XGt3lNKJFIEdrvVgQlj+HVz1eJE6I3o2fXX3xVPFHkKjnSgjVZzjOsXHtfGHRd+d97cBW6tq6LJTd/WsqCOxKPc+3TEZ0AANFvA0WHlxvWljpUbnwXptoiXjIGdRtx9vZd6t7RH69BRSBmSRYG9Gbh9hdPW/+ZAYW5juM9kgcpMWxKsOhGOaMfkPfU6Lt6uTOQWwBJz9L/B8ALzgSoy/0Bdfn83qRyTXeo026FLNxjns3O1HDpdwYQBpSLoTFgMIA4a5MeUEOTIPzeo6NxTxePePu/10x71JlM9n53GSDTFlf2llhpLteBm0v2o2rbR1zJbXQSeRBC025FYKL/Bh+yHLlxkTmIjdrZLrmbyaxCszEcTc0RaDSGw2QKBkvHaTIHQioxQ6XUI1BViUCl3vbfCh+PNGRLK7bEctRYrAeL+fBUq2uMNtXWdev2n3H/LHZlTJcBkp6l/x/fbCTmBj2qcxbBVMXvjRrcYzxC+tzs9w6P6pDTsJhVMFlUYOCwWvmtwQOkRqhUlQj6EzCByioEqcrA4kq83dimnG3OHTWJFJj0YBjCY/jp7a5upfp+O58cff8gV/i4BJDpC8qnEonka1cGZH3qDi5H3ZFfeXWXa5JtIFFoe/DqdzEQSyUmMPCFhRTYMrmHhZz2eJkDdqzLjncdNVc8doWYP4tFjlNFo6a9cN0t9mta2xnIJYCkL6rMBaW93RG+sWA7anayOCruzXbK1edmBPWYwL3TxUhJKNRBhWAJ59QhRTbgsBqEYrVjOc3YusxxniuZgti+HqE67vEeQspbXtm76N+jHkzgy5M3QKZnVT5GQF/nO1BH9C01BajbvwxG/VFe7FRxA2xAudCXK7JRj6j6MkQ0VoMQihqFGuVBOpSEeDTOn5cuniIODjoDdXAxWMLr0ODTCFLpBRnaYqL49aNaVBU7/nr0HqnCFdfzi/cQRMDzmJw6M+65OROmzOHDlxdApsyvUiul1lMAwvgM4oiWWkwwHFiGhhN/8GIpVUUgpO9NSNT2wOhTf6Bv+WGENXf8lmySqXA8vCvWdxmBYxFJvMbprMRsKRYeesJ2whYRegIgrtUp5BIMJQ8kuO7BMARHeOfr0TqHVTXdDY8Of6zjlClCLLHSF+rngkCUEMeG4+tQu4/ftmaoVIaMQDVCrY7fXufrvr7rCCxPua6zPvcu6aVU1NiAwkq1sRrpARLHt+DnHrhis+3rwb4ijlq/sYG47FrOfqwu6cC1U2HZ8IXPjpt+F1d6zl+QfyyqiJVRSQFARXsNtJTnofbAMphqnZ/IXROgQIZCxVXPNnTsK/LBIM42cmkMf+0kl9VDZ6uVnm8DC4H9AK+Nn9fh1D7HYAoMleDaB8IQpPaNat119XHND1z5LOcHhzNA0hdVvgFK/yX2xFtb6lGXuxwNJ5n3fMdtikyJKXL3Shb+mjwev3a/VBPU0XyqlFWI1e1El5jNIBJLG1IGDAYQZ23ApCD0Gc35eXTGTpDfS/WXffPk6Hv+zoUZJ4Dc8Ul5tMUiYa91j70GGEAYUBhgzm9JEileUQnj5PZNn8nYEj+Yi50uapqQwBIkxmw+F/TFdWOu6yLDNfepQTg9ZZ4zcVNzmClUXhs1Le3jamejchLdW8VtmA8XA0lz6cFzetylCMR4Z5nFnGn95+9FoXF4Y+gDHKkvkbG4FhbfwmVjzqw1ekYIEvq2zbHrK1asrk358ZGrHnGayNApQO6aXxLYJJWzAA9hXtsuWKju8M9gfzESCd5VCROe2SrGV31vxbY4r5agcMEi3uuiCfgZh/63wunGPHmgAsOmeu2RcWqgxkZt832DX3S69nMKkPSFFf8CIW84HVFkAvYVGXZgGWZYuZ2wcBXniKYHPhqYwZX8oqeryVmAxsLdDu0gVxFce38Y1JGinecIMg+FpYPnPjt+5tOOmDkHSJbtFq+7IBK5yeShbR+gG0/XeS5DPjbhJZiZo9Cl5tACTUU7Ub3DeY62AROD0GeM05ez161dWZNS/K/hj8S7DJDpWfqbCcCcEn2izdkw1+5loDsCvjjy39CrItxhwakvS/8TGlKE4KBShASWIjiwBAHSs96vRlMwGhpjUNcYhfqGaNQ3Rdv+9ZVGLS3Qb3gdpppChyKx8gXX+uDGvCOhWZKHoqLhE+dcN9WuY6DDL0h6lp6lAbzFVybpnd9EuaO03YmIdcMeoT5mK9fMyhsw71u+7XywMPAwEDEwebq17gOdjTt6RigS+vpPoFtJ+RWrnx47y65zn12AzPigSEOVSmEcdpxZlePv/vIFYXcG7O4gRrfTJVA4M0dTcziqDMmoMXSDoT4Bjc3ifv3YV6Pyj9dgNTuuK5h0hQJX/d13N+Yd2bW2IdH40JCn7V6q2QXI9IX6ewkBvwgnZzPr5u8Pb/8YXWtOu8mlfXch9yCstiC7M2B3B55q9Y1RqK1PQHVtMsqre4Nahd1PNe19E9UnHDuUypTEducRHu3ZWA8hbFxcOvDpZ8Znzu2Il12ApGdV7gWoT2V1G3l6CybnCZsZ/YDFjNdCYhHS63rItT1dtjfzZUqI2Wxz0/BmazKGo6Kqt+2vps69upcsGjKw/gvk/uy8PjvztWI+V/7YKmtSc/81/OG+nAFyxyfVXS0WC7+wPw9YRtdYiWc2CuJpf07aecZGrDWf3SgHp05CSOokXpGLEmJFcuJvtq+GrzVDQwIqq3ugtLIfGpt0nMWTy+oQqTmEOM1GbFh8yqkruyYhwHasK/GYnwVnVTgRGlvUkCrru2b2/rDd8qTDL0h6VuUjAOWca5eTFAIRTc39HsOKdgjC7YTVgieb2voTySOSENLrOiii+jgdg8VWJCeu8oukDGwJVlHdC83GMBiNoWhuCbP9tyygCQHSZgQENIH5XkVFHATL58Ua1xvzkdND0SXNfzbmHU1slaHnvEeHPXrvhb91CJDpWZUbCOhIp0+IFwjiDcV4bKswqVcXGBux+s+vx4WqBPeYcPZrIuv4PJ8tpfp0XwapVNiLSy+YtMMhWQAUF1f2pMuVuOo2z5+qCWWnMn0aSiquQHVtUtaSTN0spwCZtrAyXkKo48NuoaRzkc/NeSsw+nS7XHW8uK0yGZHV4richCwsASGp10EZe1kb3mEhp3BFH+cXZrwE8jFiLq7sLIzWtjH3cBIGIUxVWdMTJwrHwdDwV+n17Extuw9Gu/8jfVHFPaDE5xPKZuzNRr8yzlmH2tjUQCkyG7nHaAclj7YBheUOZgkRruizUIg58lkeXF3Z+RTd9CVli8sGIe9kB36KFOOyZ2nXni9re4Bk6dkx0Q2+pJA9WQYX78SNR35BcEsDZ3FXy4OwoNp5QNaFDANCoqFNG44JN63nPJY/ErJCm6vmGVBd4jhKk5VLu+a+MEg9k/9NMFOeLBqLE0VjO+ZHyJvZGZrH7AJk4ntHFdrAcMcVTwQTVRhG2qYqW0z6wOI9UFrs7wcORvY6F5POEmvX5a0ANfGv2NVjsNJWqjgozE+PbJyYfedPDTi80bldRkwLRdf+/rUxLyobjPyTNzq0wIXLrDZfkOmLKm4klPwgzKPrWS4KsxF99XlIrC08m9UEVtQo1SgJisGhyFTbf5/f2O0wA0lzyV7eggaHS2wg6T7IvahG3gOL3KHwoBHrlziPEux2mQLDb/evG/NTxaNxvNB5BCklNHVphi6/1dRtADIjS/8+BWaLPA8+xZ4li6g7vBIs0Tbfxk5w+o1XeS3XE195HdFzXVpJZQQT71cjPNZ/bsyNplBs3fsQLBbnwVvEan1wyV2R545J2wAkPUvPAqOihDS8P/Ay1ZWg/vBKMHduvk0VKkHaOBV6DvV9925HunFdWqVNCET/Cf51Y15UOgT5pzhvq3/IztSeqzJ7DiC3L65JklrNbtVS4Ptw+Rp9w8mNtmWXtcl5daQLZU/sJ0fa+EC/PPLkurSKTpZjwt3CRXQWlw9ES0tbfmGhp21pVCWEeyonZ8/R9gOz+YQOWLMzteec2c4BZEZW5UwKenEVs+jAspb6ctQdXoHGwu3O7N7ud4WKoO9YFXqP8p83LNelFUu8cPU9akR2c//Yqq4hFscKrkVVrf0kfqHBxbYj9fDQ44hQn3A513Bh6ZU4copn4VtiHZGdEWlLq3MOINMX6rMJwXTeT0Un7dB0egsajyyHsc55vYsLTRCVJEOfUSrE9fL9Ux6uS6v+1wQibZwwwN+691HeLvrq4AIbUMLVxxEWXNAuDVFHj2GTMQK7D820udTwaYTgmSUZ2pfbACQ9q/IEQLvxYdTZaZMjP0PJrj04nuPayTerhdF3jApB4cK6nwtld65Lq5geMoyfxStjp10RWQAYW/K421g4gTqkEAw4KlUVZH/6kzG/MvaFKiq7EuVVvXmXmDgrF/k1O1NjK71s+4LcufiMrmv82vJjBde4K3en6a9SVmNo2ju2N9XJPUbsX90IQ0Xb5GlclA0Mk6DvaBVShvnWJp7r0oqF6rOlFctxJURjocTb97kPECFkscuDoDw7Q2s7rLIB5Nnfvp2ZHLd6Eds05Z04t4EXVQZfZx4buRO9kpafE7PJYLWB5Mg2174msSky9B0diKhkYR40d+3HdWkldAIGVj3rj50OE4m4q5ow/eWKqOwZIeU2gMxdu+CzmMjdM9h/V1T1wf4jtwkziB9z6Z38HWJ07QOFTu834sDqJlSXunbK0nuUypaKUxnkvZt4rksrBupxGcIsrc5/FPYdSYe+yrfLfVMqGbl0VsRGG0De2vzm7gj10XOF16sN3bD70MWdK2rEFa+AJXLuqBkbrdj/exPyNjt3yeioPysiw0DijZt4rksrdiHIllbaBOEvBPXVqdiX79vnQQQkY0mmZrENIB/smFMRHFiqPX8yL2aQsCPGQX2dOzSzqq4H1zdCf9q1rwnL/tF3TKAoD6G9j3fOD/XI2+x8mciK3bCiN2K1A0f/gfLKDqNcxRqSL9+52Znap20Amb/7cYtCXtfum19QOgxHT03iy9jv6ZPi16JbfBuvZ7s6Wa0UueuacHBdI+zEXjm0B9sE9xkTaNvIB8id5vFzy7bssGHTl85dauJ7yTHmTuEuBO0J7dsgIV9nZ2r+YZuRTw/eY7cCyp7Dd6KqNtmtifG3zn26f4No7X5eYrMIvIPrmsD2KK60iLgA9B6hQrcBzv2FXOFv0FuweqEBDdWOT+JYdpKr7w5DRJxnjqZ9FiQE27MztFeSdza+PiQs/Pg2R0bfvOcx3pctrkyir/QZ3O8jhAS5lrbn+K5m5K5tQq0LR8JM/9hUuQ0o7O6Ba1PVUYSeAQKrAZmRwiIFjCEEhijAEHX2q/TH0jpO4B14QxB6jRBvadWRTieLR+Nk8RjB0xVxtZ89Oub6Tt7a9NojEWEnHCZo8Iuza3etcV7/0YNecCvWvLmBInd9Iw5tcG0Tz0TpPkiBXiMCERZt/03ebTtF/AErwortl0BrCiPYq7Zg8eE61FLHpdLYnohlRvRGq62Px6niMdBX+87pVnOoRkHe3vLKovDQUzOdGaXgzFU4etp2udipG8sFNXzAq4LoWHrMZNubnDnqOCOhvcFYGZRew1VIHREIZdBf+5PYXIreqy1Q8fCpZImNvmhpxErT2RRHFzbmlTzhrlCoI4U/teJjzDMVl6O8qg/YSZfXGw1IIu9sfXVlWMhJTk8+cxlmrsOdubEEcAN6C+uzeXhTM3LXNaKpzn69P0c2DdVKkTpcabuNT11jRY9NrvFhY6w3t+AjY2O74Yb9PRjJV/hOAFh9YwzKWAK8yr5o4JHTS8hnk0rIVeSdra9tDQs5cSVXxnvzZoBlhOisTQyAMFvV6S04sLYJx3c6P2K1Z9t7dUEY08h9b2KPz1aLCW83/xXHnzJUicF/893UPQ1NWjQ168D+ZWlWPZb5nuIW8vaWVw+Fh57sxfWBbzRqsOfQHWg2hnPt4ld0YgGk1QiFB1twYF0TKgv5LbuulykwQy7c5vlnkxFLWpqgiQ/AhLvUYKdXnaWxTIknisahpHyAeyoRei95Z9vLBWHBBQl8OJVW9Efu8Sl8uvgNrdgAYYawmqkNJHmbmtDS5HjjzOi7SKR4XaDCpedPxNzmBkTcqUJsT993y3flAVqz7UVXup3f51ny3rYX9KHBxRq+nA6fuAkl5YP4dvN5ek8ApNUIzDuY3Wrnb3F82vWIIhBDBSpcev4EFIZasfeRzgkOpqfbAKH0HfL+9ucbQ4JKeH+7W0xB2H04Aw2NkT7/0PMR0JMAaZWr/JQJeZuaO7yniCFSvBsoXgaRzXdIUdWl8yyvzp9rtwFCyKfko53/aQlU6l3a+XVGz18WgDOw7wI+mBKMlvl2sWVX6fG/9ic3yhSYLuDe40JhTwyTIHeC9zyLBTPeBYxYou79+dPcZb+czNv1b7NSYXDZr4DdjbA7ks7SlIoaXHW5d4v6Ht3RbANKTakFjykCMViE5VXrfFXHEWzKdHn6fXbaDx2bjDN69zbpFOQPkrX3EWtAQJPL31iLRWZbahnqHRYL9VlDXigYIVaMHfJ/XpfXYqI4vLEZ/9woRSzEe8ObFcAvT3r3clBoY5stKmze/SjYv241ghyy+MB9lBWBcadV1vTA3rx/usPCp/o6igXxtKBXv2aGwnWPFU7irnw6ABaXFtmc2HucqKRiIA4fFyQy9iBZvP9+KpHwj7Vut5YtHA/mdNYZ2qB+HyI06IxPqHLN6xbIG50fBbsjbGcDCAv2Y/FMArSDZOGeR6wymetLrFYhKAj2HJoplGAC6OY6i9SkHxEXKUwVK9elONtz9EcWhFSIB5DOtsTSV/fEvnxb9Lj7jS2x5u16yqxUVAuyS2NFI/cczoDVKt6a2X2tnXPQhuWjf2q2c0IPUAz82oKYPPEAUqCwYsVNAAuS6gzt4NGpKKtME0QV2yb9g5w5xmBVqWDWOVUyCscL7NZlF0RwsZkESFsw4oqXIZG4FkorpHzJW63ovcq9PaIjeVpdTlhaUVbnnP2xLIr+2Fi1qJwD7coMuqPKcvLetpcaQoMLhUmZ96co+/LSoa/xHb9+VyyUlvIFdOGHXOkqaJ/gSmDMB+IB9b/N9Ths+Ys/iz9JGqhE8gAFlMH+tRI4fPxvtnqDArZPWTxIWXjoKUGvww0Nsbb9iNniO+7TfI0WF5WD1G6+USrlimUWxB4SfpmVZzHj/5o7ztyiUkttIGFfFHWkICtwvlPAi766Ngm7DzsNa+LFEyBvk7c2vX4iIuy4IFv+80fnmXKep+Dik7OMikP6fYAgVYX4gzkZIbSMYtQ8908aLxyGOSvusTj2KmZJJVicCAOKEImrxTLm/vzbUVHdW2j2z5I3N7+1V6M+0l9ozozfoeOTcabCvdtMMeTiyjMxZhN6dPmVK7modElbKfqsEg4krXsPPkKzkNykyxVI7J1qX6YAABn4SURBVCdOYgk+spxPW1HVG/uP3O5qd/v9rPRe8vrGt9frwvNHCc8dMLYEYW/eTFuQiz82WUADhqR9BIWce0VcsfQ8c7QFcUtMuC7A/YfzwoApvjKzDCysDBurURgY5v3lF1tasSWW4I0FTL224f2vIjW5fxec+Z8MK6tTsDc/XSz2ovONjdyNXknfiz6OowEaaixYu9hg8826Xa7CzTLXQbIz1IzXznS87+CrJAuyYkDpcpkC0UneuYpnm3K2ORejUSu5iryy/uOXorX7RM0m7LD0rhiaCcyTTyI5gYe2sVv/mQHM07e1DQuQYZpcBR3hfspkDSA4NJ7g5BAJKk6bcWJXM47tNNqCt4RoLOiqS385ul2m9FhpaEol2HnwHrBDIVEaNSeRF1cvmBYfvXupKAOcx5TdbrJbTn9t3gKJoyzs1wYoMFImR3e2k7bTWNqfon5ngWEMaktUW26xxcgf32lEc70wdy0swUSX/grbl0UdJe7yq7B0GI6ImPmzObRMQV7eMj81NnTPYbEfXLYP2Xt4JoymC2ZJ7IEF5B+t3Yc+3ZcJyNExK5YcO+eHv5Ir2KOODgzAzdcEI0oiRYCdxHGORmqqteDYLqMNKCy5hBCNXTaypRcDihi39KyMQs7Be9DUHCGEuB3ysCWOY798cuA+yty8xW7sRIudbPlzCws5hf4pXyAgoH3qHCH1KjrUgnWfciv/JlTKHnMLxfE/gcI3qYQj3bVdWjf1wl0+emDZvj07U3ulDSDzdj9pVsprxP0e/mnB/FPXo6iUc5YhIZ85wXiFBJYiOXEVNGFHBON5PqOaMjPWLq5zmkeX9WFFeS6fJKgjhE2Uk7uNNrCw0zOhmjyQILaHHLEpcsT2lIElq3OlNTZrkHPwbpjNwuv9lzz06+xM3dnk1R2VP3BFcC59WDH33YfugKGBVyIVLqw9ThOlOYD46G22aqxCNRYotWaRAWUnnKcFYsesI6aJmyq0OK/FFivP/lzJXm/PLqz+SFxPGWJsYJEjOII7WFjVWla9VuT2V/mDd7fOzVWHnBb8GtKeAsxXf8+hO0FFjJQT2Xht2LNybRr1MYQGF0Cp4LYssifflmX1nIqGssI2Y2aq26QkFVPn+ioLCg4YcWp/CyoLhfUNYwt9lrSbJexmXxe1zv5ixlCfiJyDd4mpqo13mwI6b2167ZeIsBPXij7qeQOcPjMcx057dEiPqBesKrcBJSiwAgqZAQqFAQq5AUqZwWnp4gOrG7F3lfO9DXMiHDsz1Jb0zRtNrK9Kqy7R3WW2rwoDS3hMW7DkHpuKUr0w7uyObNemBNurGz58JkpzwO0sW3wn6+CRv6Osqh/fbp2SvqlwB6pzFnPSbeT0EHRJc/2ykNMgHIjE/Kq0Dh/ZVWb7ssT0lIOE9sE+T106y81R2TOizxbxnLN6ce+u0TtyOdhEUJImY7itFiLfQu+CCuEDzFqqjqNq84ewmpx/PQZMCrLVN/S1JvZXhekrCwpFQHhPyDVJkGt6QBYm0j72wjLQbPCPdz1rVCkqBAuc4jqBxWUDkXdSkAB7rkP6FJ21uRaVWz6AqabQqVyBSSMRP2wsIiMOcS4R55SpwASe+Kq0iiwN0kIRkQy5rgfkuhQEBOkE0ob8mp2psVU8OBc79sYf7+ZrIw575arbZ8twCWRuR2yqts1Hc0n7ctMX9lFE9UHEsNkgf4b7sSPmlK4/Q6Ws8oCUrg3R+lUpOmQCqwwsdgsIjoRc2xMKXSoUUamQyF3LWE8InlmSoX25DUBeXrcoK1aX45Xaz/UN0dh9OBMms/8GWLky+TV7lqLx5CanXWXB0Yi4ajbYG/PC5i0XGKdCn0dgbKIoyW85+5dnQnOD+GCxLcnU8QhQxyIgUAdpcCQCgnW2r4xE4SSVK7GOyM6ItE3MuS/I3A2fXh2j2fYbH8WFpO2sp1r2bGTI/R/q852bm0gCoBk2G/JI+xWXrui9EGGhwt3FCDmvF/IyGc+CpTjfZPu3yeAZsJwvB5Epwb42Z4FzFjTS4CgEBGkhVYVZszO1547O2oTnz9v5lFmpFCbDiStG3nP4n6iq7eFKV7/qw4DBAMKlhQ1IR2BXx6ldVYpqDLv8TS7sfIqGXYoyoBTnt+BMfgsaajwPlgsNIlEEN9OW+nlmmfm59cuX17QByLtb5x5Rh5z22hNaU9cVu3IzfWoShRaGLanY0opLC0mZiJA+N3EhRXzUVqR0W8GJ1heJrBbYgFKSd3YpVl/tdbDslcoCbmwDkDc3vrVAE35kljcNmHvsVpTqL/OmCKKN3VSyB9Xb5nPir0oYhPBB3LeEUokZQ/q/C/Y18ffGivEykJzOlaLgsBImg3eyXBJCP2i7xNr2VG9lcLXH70POn1C2xGJLrc7WTLVFqNzwBqxm5zUK2bFlxND7IQngd2iRnPA7usZt6DSmY/nVWJ41s+EMWqpO2P5MVSdhMrhWw94Fw+S3SxH23tYXy0JDigRNA8RXsM62F6EWE8p/nwNLY6VTU7DTloih90EWEu2U9kKC4MBSDEn7gHc/X+2wff9s1De2t4O5QY8W/RG0VB4XHTDtAPLqho+/jtLsm+pNo7ElFltqdZZWseZFsC+Is0akckQMux8KnetJ9/r1+AqRmoPOhvL5342mUGza9W9OcrLLVmPFUbRUHoVRfxRmgb4wBFjWDiCvrFs0PFqXs5GTZCISbT8wG+x+xN9b5ca3YazI56RG2KA7EZjgXh36+OjtSOn6E6fxfJlIX52KffnTXRLR2tKAlqqTMNeVwdxQBgv7t74cliZ++zNixZgOs7C+s/XlyrCQAvFiGTmo3RnuRaq3L0BT8W4O2gKhff6G4JRrONE6IvJGjUW3he6AgRgRg9TcAnN92TngmOtKzwKnruzCveEuELJgzYplCzoEyNz1C76P0e4WJ5cKR2saTcHYvu9BsNhjf2yGg9+j/sgqTqIHJY+Fur8wq1qZrAEjr5jLaVxfJtqfPx0V1fYvR4WWvbn08IzqTe8WUqm1YM2K70+08u8QIC+vWjIqNnbLeqGF4MtPhGTEfEVwib7x1CbUcEwUo4obgPAhwgYADe3/DgJVepdk95VOm3Y/DmOL2mPisAQNHQ1mN9H921terw0PPS5uPKcT9UVLKSmi2VsqjkC/8S1OI8gjkqAdzW0jyonhn0RpPT+HLkL0RDV8ROJFy2eDzouxHWIKMn9ppuYeXgB5cfXiT+Ojd3j1QoIlBlu3Yw7Yv/7QLE01KPvlSU6iSgM1iLr2JU60fImSEtagW9w6vt18ht6dDborSkiA8Z9latfwAsjTv/4Rk6D9tcTb7tR782agssYrXvi8bV3yfYcvofZ8iBRRE+dCqhTnA63THERaj694y+8rHU4UjcPJojGeEqcwO1ObaG8wh7WEXl6/4HCsdrfndkodSJl/8gYUlbl39OmupWPrziDxz3uMAnU8SkJi2rE8s3w2qJVbMgPd6CcgixC84sQ5mdhLbdhl3JZ57tpGjP57D89AZa3HXoovZWdq/+MSQJ5Z+fPDPRJ/flsMI3DlWXDmKhw9bQvu8liLaijHkKKdiKsvRZea01CajW3Gbg5Q4HRYFxQHR2NrwhAcWPMSp3BZxiRicCaU8QNF12XMkDmQEG6AFV0YngP8sfMpmMyeycBplZA+n8/U2C0l5rQa3Zub3qrThB1xLTSLp2E6ImdFUVhxFE+1CSfWYczJPxBo5lacvAYU/zM245cLQNSRvKF9b0FwT8/Ub7xqwOtQ+kDZBr7zVt8Uje37ZvPt5hI9Bf1laaZukqPOTgHy/Kql2Ymxm1y70nRJ7LadWA4tlthB7CazmPBgznwkcHAJ6UgWVqmJVWyy14KSRkF92W1iq3GO/+B+HyEkyGNOfYLp5cn0tBJCb/osQ/ejWwCZ8mFu8OghX9YGKfVeOUryBEBUpkbMXfu8IJM8taGmHR9FdF9bVKAn22WpS0RLjSqmHh7KmghQ7M6epXVa8dPpF4QZ47UNH/0Rqdk/QkzD2OPtCYA8sGMekqtPCaLejyYjlrb8tTyTRyRDO/pxQXjzYdI7+XvE6Li5ufDhKzZtzsF7YaiPE3sYUEpmLp2l+cTZQJwA8sLv3/fSRuQc8kYwjtgAuaJ0H9L3fenMTrx+f6m5HvssZlveJt3YZ3j1FYq4e+Jv6BLrdZ9TXupU1vTA3jyPXL0dyc7UcnKZ5gQQpuXL6xavi9XtGM1LYwGIxSySEt5cg39teR/BJuc1OPioUmK14sWAQMgnzOHTTVDaxJjN6NHlF0F5is3s8PGbUVIh/gkfCL03O0M3j4s+nAFy/3e7u/RK+OlkcOAZzn24COCM5tCxyTijF6dS7k35KzDmlDhv2XVdR+CHlOucqSfa79HavejT/VvR+AvNmLmXnHVO5RdF6YIc+dmZWs53e7we9udXZ69MjN7s0UuJ7QfuR31D+4s5FwzTrsvsnAXoXnXOcVMIlud4HItIwgeDhHVC5COgJuwYLkv9lE8Xr9IWnhmKI6c98EKh1szsWZGLuCrLCyBT5h9XX5X2lT4spNAjacXrGqOxY794pz+vrvkvFBzuL7ga83w6Y4ACT4x7zpWugvQJDirFkH7+E36769As1Bi6CKK7PSYUdNfSTB2vNRwvgLCBn1/1RXZi7B8euRdhN+jsJl2MFm8oxmNb3xeD9Tmebwx9AEWh4p/IdKSEQlaP4Ve8Iqp+QjGvNiRh96GZQrGzy4cQTF6SoeWWkOxPLrwBwvq9s+XVurDQk6LerjcbQ7HjwGzRAqaGFe3A1Fxx659/02cytsQPFn3i7Q0w7kq7LkZek6mjgcXcZ5433k/Zmdob+SruEkDm/Prl813jNzzLdzA+9McLx+NUsXiHZlcW5eAfud/xEYk37Vd9bsG2+EG8+wnVgX1B2JfEl5unbs4tlA78YpZuF19buAQQNsgr6z86Gq3d353vgFzoG5p02HngHpit4hWJYR66/97yLhdxXKZ5bdhDHXr+usyQZ0e2B2F7EV9tzcZw7MqdieaWcHFFJOTN7AzNY64M4jJAHv9h7VXdYtZtCgyscGVch33yT96IojLxlyavrPlvO09doZRhHr9PenGTzvS4vNdniFAfFUolwfl4aGlVIJXWpXx6ZzfnGfs60NBlgDBez/2W/W2XuM23CGk5MbJZ2JPv3l2LkaIXp5RzvrYnPr5C/I2nI9uzexB2H+KLjWVMZJkTxW4SieT2z2ZGuOwq4RZAmHJvbHpLrw07ohFC0bLKfjh49O9CsOLE48b8lRh76g9OtHyJ1nYdiR9THHpS82XJm757l1/RJcZ5/RHejN3s4Kk7D0LIl0syNG7FSrgNkGdXfHNH1/hNn0il7hWcN9THI+cgx5BVNyeotXt0fSke2vIuVCxbsoDNoAjG+4PvRUWgIO8NlyWLi8pBarcfXO4vRsfa+kTs9EAZZwDVJkL7fpWhc8vn322AMCO+um7e6ijd3nGuGpTFnLPYc0+3inVzMbmhDLfIhHVv+D71RvzRZZin1Wk3njqkAAP7LPC6HOcLsGabZ4opE+COJZnaz9xVXhCAMCHe3fpSlTqkkPdxRHHZIOSd5FYDw11lz+9f8fscmOrOnvC8GxiCGGK/eD2fcQ/rUjB/wJ18uohGy77qowcJE+cihJBrtz8HSoWxsxN5Ps3O1AoyCYIB5IXVX9wcpdnzvVxWx4mnxSLH0YKJYADxdCtd+QRYwuPWpiESfBwoTIaRh6/xrdtrlrzB25lpmJ035DwLs0W8Y/vWuaTAUSI3X549I1oQF21ODzPXB/iFNZ8sSIja7rQAT21dIo4WXAv2r6fbmR8eArW0TcLAZGDvtQeVQRgqlbkk0tpuo/BjT4/6cXKSM63nF9BF2M1JwImHu0Qbdz2JFpOojhfnRLQC4z+3k+PKFT0EBQgT4JUNH++K1uyz65/Ovhjsy8G+IJ5s1GLGmR+cOz4OCZDjDrkS7KvCpZUH6fBd6g1gx7q+2LxdBXfLnn+hych75e2qKZ/IztS+5mrnjvoJDhA2yNtbXq0MDz3ZJjt8Y5MOp4pH4oz+ciHl58TLaqxH6QruF6kMHFNSrkZiQzkSDMXtPH6Zp25haJztb123UTC4WI+bk/BuEunCDyEt5Qs3ubjWfdv+B9HQ6KFaTBSfZ8/SCu5EKwpAHvniaJ+eKVn7VYpaCShBUflgnC4ejeYWJ/WpXZsHh71YNaLy3/g57cXc8A5YqeDWxjx/Ew3Ftv9ZEBrnNQ9dV8zjrSRynoott9mEYnezumzosql93Ltr6MDAogCEjfPUyv89rFMfe7u4bDDKq/q4Mrdu92FVnVh1Jz4t5m8fggh0osVnXDFpRw18GQEBjWIO0Yb3rtwM1NSJlzmyrSKkFiDDszMjRCmrJRpAmBJ3fXHs8abGMEHXhFxnmZXiqvyDe+1wIg1AzE3+E2DE1Q6Mrk/3ZYjW7uPTxWXag8duRZlHqxTTG7MzdaKV1BIVIMzK6Yv0c0HBLeW5y9PStmNTwQ5U71zMmZtEFojoG/w3l60zRWN1O9ErebkzMrd/P144DqeKPZZ0GpTivqWztB+7LbgDBqIDhI09Y6F+MSUQ5OLGmTHq836B4RB39wqJUo3oSa86Y+vXvweryjGk/3ui6lBcPhB5J24WdYzzmVNC5izN0Ige0+wRgNi+JFl6FuooqgVZVSdW3YlrkwZpEXUNvz0KV96+Rtc/ZSm04XmiiFVlSMaeQx55/52Vn+C97AztQ6IocwFTjwFkzhwqORZfuYoALvtsOTJI5ab3YSzP5WwzWWgsdOP/jzO9vxNqI/LRv2e24GqU6tOQe0yY+opchCMEnyzJ0HosjsBjAGHKT/mwPFiplPwGCkE9+cpXzYG5nnvknCy8C3RjnuIyH52KRuivyOnikThWeLXHbEQp/XLpLJ1b7ut8hfUoQJhwd3xSHWaxWleAUrdBwgrWlP70CKjFxFlvuaY7tKO4XxpyZuwHhEJ9RVhyt2MFE1FS7jT3s4BWIV9nZ2r+ISBDTqw8DpBzILFYfwSoywmxWb3r8lX/5aRkK5EqfhDCB4tfSoGXUB4mdtf1pKq2O1iJtNq6BI9JLkTgk6vCegUgTNj0JaVBMAYsB8F4vsIbyw+jchO/hAshvW9ASKoHMvfxVcYL9JGag+jHs4YhK8l8umQECkuv9LTEgrmuuyK41wBiE5ZSMmNx5XeU4m9chedTg7yVp6fKnnHVwRfo2HIrTpfj9GSLZdcvr+yLsqp+MJkCPSs6wfvZGdoHPTto29G8C5A/ZUnP0rNcqU5PJgyHfkR93kpe9tKNeRKy8K68+lxMxOyOJDS4AEqF4ZzaRlMIGpu0aGzWwNgiTJwMf5vS57MzdfzW0PwHcdrDJwBiW3Jl6V9mLlz2JK7Z+QkaC7Y7VaiVQBKghG7cf8DuOi41P7MAxezsWdoPfUFqnwGIDSQL9feDoJ1DFPOpYr5VXBs7xtWMeAQMJJeaX1mgnlBMXzJLy90VQmT1fAogZ0FSNQmEfgZQ26u/7NdnYGms5GyGwMQhCBvowVtdzpJdInRsAbqPEus/l2ZEecarkuN0+BxAmNy3zyvvIQ0gi0u+v3c4Rz1sZOyUip1WXWr+ZgHyVbOhceayRxO41d72oHo+CRCm//hJt35DgSlcbaHuPxVByWO5kl+i8xELUIJnlmZo2f7TJ5tPAmTs9VPiiJUWcbVY+KAMqBI8nx2Fq3yX6DqyADkJCe7Nnqn5zZft45MAGT9pSj8Kut+Z4STyIIQNyoAyqrcz0ku/+5IFKP1cGlA/+9M7u7UvKu9Lctoch32wjR8/RU3l1KHxZOp4hA2YAVm451MH+aDJ/EWkegryyNJMTZa/COyTAGHGGzfxlvkgpMMqmLKIbrURQ2appao2iVP8xeYXq5zfSU2mxz69N+aUPxnAZwEyduLNyQQBr4Nc4IZCsDhQYnwg/OZPHqYUL/mTsS9SWUthtT6VfVek/5TcPW+ifBYgrTJOuG7KjRYrTZJIYLFakb/2l29Xtf6WvlDfCwQsJHDyRfrw+bTaBPjAoiTPfD5d85cfi09L3F44nwcIF3tOW1h+q4RImN9OXy70l2hEt8Dv1ErmLL1Ls0X0kUQeoFMApNVG0xdUPEok5GkA3i3MIfKk+Sx7ilwK+tLSWTqXKzr5mm6dCiDMuOlLaBBaKp8E6OMAET+duK/NqHfkKQDBa9kZvuFgKKQJOh1AWo1z5+IzOjOVPQqKhwFc8loU8qn5i1chQN7OztS8LQ5773PttABpNe2UrMIIBVE+QCi5H4DO+ybvBBJQsPxBH/iKS7qYFu30AGk1Hks7dCK+8h4KejdA0sQ0aqflTbABVDIvOzPiq06r4wWKXTQAOV/vGYv1461WTCcAS5fvkZpgfvxAVVEgWwJ8sSRTu8OP9XBJ9IsSIK2WmvjeUYU2MJxlPWNew5f85M9/hAg+J0S6bMnMcJ8JXnLpCXez00UNkPNtd9v8Em1AgOxvhEpuoqAXafoT+jWA5c2h2u+XTSWC19pw81n1SvdLAOnA7FPmV6kVAZbrCSWTWPyWV2bGY4PShYSSFU1qzc/LphKLx4b1k4EuAYTDRE1fWDEaIBNA6BQC0oNDFx8mIfsIod9YLPj987u0OT4sqE+IdgkgPKfhtvkGrUxiGk0lZCShdAQFvYwnC0+Ss5ysOwFsBJVsUFmb1y+4O9ZzpaY8qalIY10CiJuGnbOOBhw9Ut1LKqEplNAeICQZlCYB8FwlmbOBPasoxQlIyDEKK0sBk780Q5fvpnoXffdLABH5EZi2sDJeYqVxVIoYCSRRIDSSsowtVmgIIWGU0BhQqACoQKAEBQVIE0CbKdBEgBJQVIOQSkqoHpSUSySklFgsZ0gALf70zkjuae1F1rUzsv9/osihyZiuqMAAAAAASUVORK5CYII=`
-	IT_END            string = "end"
-	IT_APP_NAME       string = "Network Assistant"
-	IT_SETTING        string = "Settings"
-	IT_TYPE           string = "Type"
-	IT_TCP_CLIENT     string = "TCP Client"
-	IT_TCP_SERVER     string = "TCP Server"
-	IT_UDP_CLIENT     string = "UDP Client"
-	IT_UDP_SERVER     string = "UDP Server"
-	IT_PORT           string = "Port"
-	IT_CONNECT        string = "Connect"
-	IT_RECV_SETTINGS  string = "Recv Settings"
-	IT_SEND_SETTINGS  string = "Send Settings"
-	IT_SAVE_TO_FILE   string = "Save to file"
-	IT_SHOW_RECV_TIME string = "Show recv time"
-	IT_SHOW_HEX       string = "Show hex"
-	IT_PAUSE          string = "Pause"
-	IT_SAVE           string = "Save"
-	IT_CLEAR          string = "Clear"
-	IT_SEND           string = "Send"
-	IT_APPEND_RN      string = "Append \\r\\n"
-	IT_AUTO_CLEAR     string = "Auto clear"
-	IT_SEND_HEX       string = "Send HEX"
-	IT_SEND_CIRC      string = "Send circularly"
-	IT_LOAD_DATA      string = "Load data"
-	IT_DATA_RECVED    string = "Data received"
-	IT_WAIT_CONN      string = "Waiting connection"
-	IT_SEND_COUNT     string = "Send count:"
-	IT_RECEVER_COUNT  string = "Recv count:"
-	IT_RESET          string = "Reset"
-	IT_NO_CONN        string = "there's no connection"
-	IT_DISCONNECT     string = "Disconnect"
-	IT_LOCAL_IP       string = "Local ip"
-	IT_LOCAL_PORT     string = "Local port"
-	IT_STOP           string = "Stop"
+	icon                         = `iVBORw0KGgoAAAANSUhEUgAAAMgAAADICAYAAACtWK6eAAAgAElEQVR4Xu1dB3hUVfb/3ZlMS5skM5OeAAmQ0IIiRZBeVLAuCrsKYZUEK9bVta3/xYq9F0pAJVhRFwuoSJVO6BBI6KSRMmmTOply/98dDBKSmXlv5r0pgft9+XB3zj33nHPf771bTiG41ES1wLSFlfESKY2jVsQQK40mUomOAlpYqQag4ZCQaFCoAKhAoAQFBUgTQJsp0ESAElBUg5BKSqgelJZLrCg1E5yRt9DiT++PLBVVgYucObnI9Xdb/TnraMDRU9W9pGaaQgntAaA7gG4AxrjNnAcDCqwiFCcgIccorEcB5C/N0OXzYHGJtAMLXAIIz8fitvkGrUxiGk0lZCShdAQFvYwnC0+SmwDsBLARVLJBZW1ev+Du2EZPCuDvY10CCIcZnL6wYjRAJoDQKQSEfSX8uJF9hNBvLBb8/vld2hw/VsQjol8CSAdmnjK/Sq0IsFxPKJkE4HaPzITXBqELCSUrmtSan5dNJRavieGjA18CyJ8TM+Mzg4aajZMJldxEQa/z0fkSWSz6NYDl+saa//3yYA+jyIP5BfuLGiAT3zuq0AaGTwUwBcANfjFjnhKS4HNCpMuWzAz/wVND+uI4FyVAZizWj7daMZ0A0wFIfXFifEUmlbKyJjl+zZGG+ug3nr9+8jJfkctTclxUAJm+UH8vIeRugPb3lIH9fZyk+LXoFr8WZosS+pqeJYaGxI9ennT9S/6uF1f5Oz1A2IZbKbU+AGA2gCiuhrlEd9YCV6a9h6DA8jbmqKjqbTDUxy15YdItzK6dunVagNz+UU24VG55FKCPAAjq1LMoknK68ENIS/nCLvcqQ/cmQ13sN3Ouuf0OkUTwOttOB5CzG++IJwD670vAcO/56pW0HLGR7J7RcTM0xBvrG6K/e3ps5jRntP72e6cCyIwF+geoBE8DiPa3ifA1eZWKGgzotRgqZRVn0WrqExuqa7u+/9w1tz/FuZOPE3YKgMxYqL+JEvwXwOU+bm+fF4+AQhOeD21YHuKinH89OlKorLJvUXV9ysOvXDfhO59X2ImAfg2Q6Vnl3QmkLwL07/4+Ed6WXyGvhTYsH9rwPGjDj7gtjrElBBVVfTbtO37j9cvujqh1m6GXGPgtQNKz9GyPMReAxEu26xTDhgYXQReWj0jNQQSqKgTXqaY+saWmtutHc665nR2W+F3zO4DMyNIPpqBvAmS431nbRwQmxGL7WkRpD9iAwZZVYjd9depJo0WX8czoaevEHktI/n4FkPTF+mdgxYtCGuBi4qVSViNScwBRmgMICTzjcdVN5kBaWd3z8yfH3JPu8cFdHNAvAHLbfH1qQAB5H5SOd1HPi7qbJuyoDRTsTyJhISLebRXVffMLzgwb8f7UAW3WdOOvvXW0lFrzf/vte8+j145JfB4g6Yv0d4DSjwGi9O60+tfoCnmdDRCREQehDinwOeFr6xKbK2pTH3vx2skfjp10638J6O0A6XlWUPLTmpXLbvQFoX0aIDOy9O/Tsy4ilxpHC4SHnjy3jJIFNHHs5R2yFlMQVi1oLKg+XZHYXgJ6as3K71joslebTwLkn/PLki3SgMUEdKRXreMngwdIm22giNYcQLj6hJ9IDRTntWDtYoNdeQnFG6t/+fZxbyrkcwCZsahqIqWWzwCi86Zh/GFsdXCh7RSKnUYpZPYfNF/VZcs3dTi+02Fc1i9rVn7Lojq91nwKIDOy9PdR4EOvWcNPBtaojyA+eoftUs9fW32VBT+9VQ1ziyMN6KdrVn53pzd19BmApGfpXwbQaXx4xJhUtr+Ii96BqIgDYrD3KM9DGxqxa4XjBCsUmLN25bfPeVSwCwbzCYCkZ+kXAZjpTUP48tihwYVIiNqBaN0eXxaTl2wr36tBZZHZbh+JVGIxtdDU9b8tO8aLscDEXgXInDlzJMfjZzOHtpsF1qtTsJPLGtA1bj0Sord2Cn1alTixpxmbv6x3qFP3IUp0Gz1015Oj7h/oTeW9BpApH5YHK5XS/126/Ot4+qN1+9Atdr0o/lHefODY2L8vrEXpUccXllffo0ZUkgwV1an5j494ONVbMnsFIH9G+/0I0Ev+VBfMPHMYZMBgAOmMrehQC9Z96vjELb6XHGPuDD2nfk1tt4KHr3qiizfs4XGAnAWHeQWAod5Q2JfHZEsptqRiS6vO2tYvMaDwoMOjK4ycHoIuaYo2JqitSyh5aOgzcZ62i0cBMuWb8mClQbLqEjjaT3NK158QH73d0/Pv0fHKTpiwap7j0BBtFxkm3q/uUK76xtjS2YP/L8aTQnsMIFO++UaqMoz9jQLjPKmgr48llzegd9J30IS5H6Tk67puWVaP4znNDsUcMjkYPa+073ZXW5d4+qGhT3f1lK4eA0h6ln45gJs8pZg/jBMSdAaD+10c96LVZ8xY8U4NqIPQk1CtFNc9HI4AuePZK6m4YuvTY2YN88QcewQg6Vn6TwB02tQwrkxUQvQ29Oz6sytd/bJPzo8NyNvk2HnysqsD0W98ICf98k9d8+Xc6/8memJxQQFic1smLM8tCQfFLoAuiJs8bzgFnuCk9UVC1CV2I7on/naRaAswt5IV79SipdlqV2e5UoLrH1EjKJx7Jti8E5NffeXGq58U05CCAWT8pFu/oMBtFwoblDQKoWlTQCQBYurhN7zjo3YgpduPfiOvEILu+70R+3937FaSepUKg27il9/PSqXYf+S2We/cMjxLCDk74iEIQMZNuvV6AD/ZE1Ku6wF1vymQhXXg9i+WZj7IN0q7F327f+uDkoknUkO1BSvfr0Vzvf2vBxt90kNh0MTxf4ka6hIseadvHfrRP1JEKQYkEECmvAPQhxyZWaoIRki/KQhMHCLebPgwZ+aBm5ayFBKJ4wfFh1VwSbTdKxuQu97x3qPr5QqMuC3EJf6sk766V/X2nMlxyx5NEDxCTBiATLx1HQhGc9EwpOe1COl7cbleBatKkZa6FCpFDRcTdRqa2nIzfnmvFqYWx1lTxmaEIi7FydGVE6uU6fseeWL07BShjScIQMZPuuURCvIWV+GUMWlQ97sV0uBIrl38mq5P8rJO6zriaGJyfqhH3mbH9x7xveUYc8dfbiXuTHRpxeUrnhxzN1vuC9aEAcjEWy6nhGwCwO2MjlWtCdJCnTYFypjOXaqDZSnsn7JEsAnzF0ZVxRaseK8azlJujctQIzZFJohaZoscBcWjHnx+0i3vC8KQpY8QitH1ma/e2VSSs5gvv9DeNyE4dSLfbn5D37fHN4jS7PcbeYUSdOuyOhzLcVzmkPlbMb8rIVt1bVLT4YOZMQsESncqGEDSF+nXw2oZVfH7CzDVl/LSWZUwCOq0qZAohDUWLyFEIA4NKsagfh+LwNm3WZafNOO3j53vtybcpUZ0d2G+HudbpLhi8N5nxswUJJG5IABJz6p4DiD/1yqk4cC3qD+6mtcsytRxCE2bCoVO8H0WLzmEJGYJFfr1+EpIln7Ba9MXdTi51/HXo9sABYb/Q5wXIjsSOFZw9RsvTZrsdkYUtwGSvqhqOKh144UzZ6zIR+XGt3lNKJFIEdrvVgQlj+HVz1eJE6I3o2fXX3xVPFHkKjnSgjVZzjOsXHtfGHRd+d97cBW6tq6LJTd/WsqCOxKPc+3TEZ0AANFvA0WHlxvWljpUbnwXptoiXjIGdRtx9vZd6t7RH69BRSBmSRYG9Gbh9hdPW/+ZAYW5juM9kgcpMWxKsOhGOaMfkPfU6Lt6uTOQWwBJz9L/B8ALzgSoy/0Bdfn83qRyTXeo026FLNxjns3O1HDpdwYQBpSLoTFgMIA4a5MeUEOTIPzeo6NxTxePePu/10x71JlM9n53GSDTFlf2llhpLteBm0v2o2rbR1zJbXQSeRBC025FYKL/Bh+yHLlxkTmIjdrZLrmbyaxCszEcTc0RaDSGw2QKBkvHaTIHQioxQ6XUI1BViUCl3vbfCh+PNGRLK7bEctRYrAeL+fBUq2uMNtXWdev2n3H/LHZlTJcBkp6l/x/fbCTmBj2qcxbBVMXvjRrcYzxC+tzs9w6P6pDTsJhVMFlUYOCwWvmtwQOkRqhUlQj6EzCByioEqcrA4kq83dimnG3OHTWJFJj0YBjCY/jp7a5upfp+O58cff8gV/i4BJDpC8qnEonka1cGZH3qDi5H3ZFfeXWXa5JtIFFoe/DqdzEQSyUmMPCFhRTYMrmHhZz2eJkDdqzLjncdNVc8doWYP4tFjlNFo6a9cN0t9mta2xnIJYCkL6rMBaW93RG+sWA7anayOCruzXbK1edmBPWYwL3TxUhJKNRBhWAJ59QhRTbgsBqEYrVjOc3YusxxniuZgti+HqE67vEeQspbXtm76N+jHkzgy5M3QKZnVT5GQF/nO1BH9C01BajbvwxG/VFe7FRxA2xAudCXK7JRj6j6MkQ0VoMQihqFGuVBOpSEeDTOn5cuniIODjoDdXAxWMLr0ODTCFLpBRnaYqL49aNaVBU7/nr0HqnCFdfzi/cQRMDzmJw6M+65OROmzOHDlxdApsyvUiul1lMAwvgM4oiWWkwwHFiGhhN/8GIpVUUgpO9NSNT2wOhTf6Bv+WGENXf8lmySqXA8vCvWdxmBYxFJvMbprMRsKRYeesJ2whYRegIgrtUp5BIMJQ8kuO7BMARHeOfr0TqHVTXdDY8Of6zjlClCLLHSF+rngkCUEMeG4+tQu4/ftmaoVIaMQDVCrY7fXufrvr7rCCxPua6zPvcu6aVU1NiAwkq1sRrpARLHt+DnHrhis+3rwb4ijlq/sYG47FrOfqwu6cC1U2HZ8IXPjpt+F1d6zl+QfyyqiJVRSQFARXsNtJTnofbAMphqnZ/IXROgQIZCxVXPNnTsK/LBIM42cmkMf+0kl9VDZ6uVnm8DC4H9AK+Nn9fh1D7HYAoMleDaB8IQpPaNat119XHND1z5LOcHhzNA0hdVvgFK/yX2xFtb6lGXuxwNJ5n3fMdtikyJKXL3Shb+mjwev3a/VBPU0XyqlFWI1e1El5jNIBJLG1IGDAYQZ23ApCD0Gc35eXTGTpDfS/WXffPk6Hv+zoUZJ4Dc8Ul5tMUiYa91j70GGEAYUBhgzm9JEileUQnj5PZNn8nYEj+Yi50uapqQwBIkxmw+F/TFdWOu6yLDNfepQTg9ZZ4zcVNzmClUXhs1Le3jamejchLdW8VtmA8XA0lz6cFzetylCMR4Z5nFnGn95+9FoXF4Y+gDHKkvkbG4FhbfwmVjzqw1ekYIEvq2zbHrK1asrk358ZGrHnGayNApQO6aXxLYJJWzAA9hXtsuWKju8M9gfzESCd5VCROe2SrGV31vxbY4r5agcMEi3uuiCfgZh/63wunGPHmgAsOmeu2RcWqgxkZt832DX3S69nMKkPSFFf8CIW84HVFkAvYVGXZgGWZYuZ2wcBXniKYHPhqYwZX8oqeryVmAxsLdDu0gVxFce38Y1JGinecIMg+FpYPnPjt+5tOOmDkHSJbtFq+7IBK5yeShbR+gG0/XeS5DPjbhJZiZo9Cl5tACTUU7Ub3DeY62AROD0GeM05ez161dWZNS/K/hj8S7DJDpWfqbCcCcEn2izdkw1+5loDsCvjjy39CrItxhwakvS/8TGlKE4KBShASWIjiwBAHSs96vRlMwGhpjUNcYhfqGaNQ3Rdv+9ZVGLS3Qb3gdpppChyKx8gXX+uDGvCOhWZKHoqLhE+dcN9WuY6DDL0h6lp6lAbzFVybpnd9EuaO03YmIdcMeoT5mK9fMyhsw71u+7XywMPAwEDEwebq17gOdjTt6RigS+vpPoFtJ+RWrnx47y65zn12AzPigSEOVSmEcdpxZlePv/vIFYXcG7O4gRrfTJVA4M0dTcziqDMmoMXSDoT4Bjc3ifv3YV6Pyj9dgNTuuK5h0hQJX/d13N+Yd2bW2IdH40JCn7V6q2QXI9IX6ewkBvwgnZzPr5u8Pb/8YXWtOu8mlfXch9yCstiC7M2B3B55q9Y1RqK1PQHVtMsqre4Nahd1PNe19E9UnHDuUypTEducRHu3ZWA8hbFxcOvDpZ8Znzu2Il12ApGdV7gWoT2V1G3l6CybnCZsZ/YDFjNdCYhHS63rItT1dtjfzZUqI2Wxz0/BmazKGo6Kqt+2vps69upcsGjKw/gvk/uy8PjvztWI+V/7YKmtSc/81/OG+nAFyxyfVXS0WC7+wPw9YRtdYiWc2CuJpf07aecZGrDWf3SgHp05CSOokXpGLEmJFcuJvtq+GrzVDQwIqq3ugtLIfGpt0nMWTy+oQqTmEOM1GbFh8yqkruyYhwHasK/GYnwVnVTgRGlvUkCrru2b2/rDd8qTDL0h6VuUjAOWca5eTFAIRTc39HsOKdgjC7YTVgieb2voTySOSENLrOiii+jgdg8VWJCeu8oukDGwJVlHdC83GMBiNoWhuCbP9tyygCQHSZgQENIH5XkVFHATL58Ua1xvzkdND0SXNfzbmHU1slaHnvEeHPXrvhb91CJDpWZUbCOhIp0+IFwjiDcV4bKswqVcXGBux+s+vx4WqBPeYcPZrIuv4PJ8tpfp0XwapVNiLSy+YtMMhWQAUF1f2pMuVuOo2z5+qCWWnMn0aSiquQHVtUtaSTN0spwCZtrAyXkKo48NuoaRzkc/NeSsw+nS7XHW8uK0yGZHV4richCwsASGp10EZe1kb3mEhp3BFH+cXZrwE8jFiLq7sLIzWtjH3cBIGIUxVWdMTJwrHwdDwV+n17Extuw9Gu/8jfVHFPaDE5xPKZuzNRr8yzlmH2tjUQCkyG7nHaAclj7YBheUOZgkRruizUIg58lkeXF3Z+RTd9CVli8sGIe9kB36KFOOyZ2nXni9re4Bk6dkx0Q2+pJA9WQYX78SNR35BcEsDZ3FXy4OwoNp5QNaFDANCoqFNG44JN63nPJY/ErJCm6vmGVBd4jhKk5VLu+a+MEg9k/9NMFOeLBqLE0VjO+ZHyJvZGZrH7AJk4ntHFdrAcMcVTwQTVRhG2qYqW0z6wOI9UFrs7wcORvY6F5POEmvX5a0ANfGv2NVjsNJWqjgozE+PbJyYfedPDTi80bldRkwLRdf+/rUxLyobjPyTNzq0wIXLrDZfkOmLKm4klPwgzKPrWS4KsxF99XlIrC08m9UEVtQo1SgJisGhyFTbf5/f2O0wA0lzyV7eggaHS2wg6T7IvahG3gOL3KHwoBHrlziPEux2mQLDb/evG/NTxaNxvNB5BCklNHVphi6/1dRtADIjS/8+BWaLPA8+xZ4li6g7vBIs0Tbfxk5w+o1XeS3XE195HdFzXVpJZQQT71cjPNZ/bsyNplBs3fsQLBbnwVvEan1wyV2R545J2wAkPUvPAqOihDS8P/Ay1ZWg/vBKMHduvk0VKkHaOBV6DvV9925HunFdWqVNCET/Cf51Y15UOgT5pzhvq3/IztSeqzJ7DiC3L65JklrNbtVS4Ptw+Rp9w8mNtmWXtcl5daQLZU/sJ0fa+EC/PPLkurSKTpZjwt3CRXQWlw9ES0tbfmGhp21pVCWEeyonZ8/R9gOz+YQOWLMzteec2c4BZEZW5UwKenEVs+jAspb6ctQdXoHGwu3O7N7ud4WKoO9YFXqP8p83LNelFUu8cPU9akR2c//Yqq4hFscKrkVVrf0kfqHBxbYj9fDQ44hQn3A513Bh6ZU4copn4VtiHZGdEWlLq3MOINMX6rMJwXTeT0Un7dB0egsajyyHsc55vYsLTRCVJEOfUSrE9fL9Ux6uS6v+1wQibZwwwN+691HeLvrq4AIbUMLVxxEWXNAuDVFHj2GTMQK7D820udTwaYTgmSUZ2pfbACQ9q/IEQLvxYdTZaZMjP0PJrj04nuPayTerhdF3jApB4cK6nwtld65Lq5geMoyfxStjp10RWQAYW/K421g4gTqkEAw4KlUVZH/6kzG/MvaFKiq7EuVVvXmXmDgrF/k1O1NjK71s+4LcufiMrmv82vJjBde4K3en6a9SVmNo2ju2N9XJPUbsX90IQ0Xb5GlclA0Mk6DvaBVShvnWJp7r0oqF6rOlFctxJURjocTb97kPECFkscuDoDw7Q2s7rLIB5Nnfvp2ZHLd6Eds05Z04t4EXVQZfZx4buRO9kpafE7PJYLWB5Mg2174msSky9B0diKhkYR40d+3HdWkldAIGVj3rj50OE4m4q5ow/eWKqOwZIeU2gMxdu+CzmMjdM9h/V1T1wf4jtwkziB9z6Z38HWJ07QOFTu834sDqJlSXunbK0nuUypaKUxnkvZt4rksrBupxGcIsrc5/FPYdSYe+yrfLfVMqGbl0VsRGG0De2vzm7gj10XOF16sN3bD70MWdK2rEFa+AJXLuqBkbrdj/exPyNjt3yeioPysiw0DijZt4rksrdiHIllbaBOEvBPXVqdiX79vnQQQkY0mmZrENIB/smFMRHFiqPX8yL2aQsCPGQX2dOzSzqq4H1zdCf9q1rwnL/tF3TKAoD6G9j3fOD/XI2+x8mciK3bCiN2K1A0f/gfLKDqNcxRqSL9+52Znap20Amb/7cYtCXtfum19QOgxHT03iy9jv6ZPi16JbfBuvZ7s6Wa0UueuacHBdI+zEXjm0B9sE9xkTaNvIB8id5vFzy7bssGHTl85dauJ7yTHmTuEuBO0J7dsgIV9nZ2r+YZuRTw/eY7cCyp7Dd6KqNtmtifG3zn26f4No7X5eYrMIvIPrmsD2KK60iLgA9B6hQrcBzv2FXOFv0FuweqEBDdWOT+JYdpKr7w5DRJxnjqZ9FiQE27MztFeSdza+PiQs/Pg2R0bfvOcx3pctrkyir/QZ3O8jhAS5lrbn+K5m5K5tQq0LR8JM/9hUuQ0o7O6Ba1PVUYSeAQKrAZmRwiIFjCEEhijAEHX2q/TH0jpO4B14QxB6jRBvadWRTieLR+Nk8RjB0xVxtZ89Oub6Tt7a9NojEWEnHCZo8Iuza3etcV7/0YNecCvWvLmBInd9Iw5tcG0Tz0TpPkiBXiMCERZt/03ebTtF/AErwortl0BrCiPYq7Zg8eE61FLHpdLYnohlRvRGq62Px6niMdBX+87pVnOoRkHe3vLKovDQUzOdGaXgzFU4etp2udipG8sFNXzAq4LoWHrMZNubnDnqOCOhvcFYGZRew1VIHREIZdBf+5PYXIreqy1Q8fCpZImNvmhpxErT2RRHFzbmlTzhrlCoI4U/teJjzDMVl6O8qg/YSZfXGw1IIu9sfXVlWMhJTk8+cxlmrsOdubEEcAN6C+uzeXhTM3LXNaKpzn69P0c2DdVKkTpcabuNT11jRY9NrvFhY6w3t+AjY2O74Yb9PRjJV/hOAFh9YwzKWAK8yr5o4JHTS8hnk0rIVeSdra9tDQs5cSVXxnvzZoBlhOisTQyAMFvV6S04sLYJx3c6P2K1Z9t7dUEY08h9b2KPz1aLCW83/xXHnzJUicF/893UPQ1NWjQ168D+ZWlWPZb5nuIW8vaWVw+Fh57sxfWBbzRqsOfQHWg2hnPt4ld0YgGk1QiFB1twYF0TKgv5LbuulykwQy7c5vlnkxFLWpqgiQ/AhLvUYKdXnaWxTIknisahpHyAeyoRei95Z9vLBWHBBQl8OJVW9Efu8Sl8uvgNrdgAYYawmqkNJHmbmtDS5HjjzOi7SKR4XaDCpedPxNzmBkTcqUJsT993y3flAVqz7UVXup3f51ny3rYX9KHBxRq+nA6fuAkl5YP4dvN5ek8ApNUIzDuY3Wrnb3F82vWIIhBDBSpcev4EFIZasfeRzgkOpqfbAKH0HfL+9ucbQ4JKeH+7W0xB2H04Aw2NkT7/0PMR0JMAaZWr/JQJeZuaO7yniCFSvBsoXgaRzXdIUdWl8yyvzp9rtwFCyKfko53/aQlU6l3a+XVGz18WgDOw7wI+mBKMlvl2sWVX6fG/9ic3yhSYLuDe40JhTwyTIHeC9zyLBTPeBYxYou79+dPcZb+czNv1b7NSYXDZr4DdjbA7ks7SlIoaXHW5d4v6Ht3RbANKTakFjykCMViE5VXrfFXHEWzKdHn6fXbaDx2bjDN69zbpFOQPkrX3EWtAQJPL31iLRWZbahnqHRYL9VlDXigYIVaMHfJ/XpfXYqI4vLEZ/9woRSzEe8ObFcAvT3r3clBoY5stKmze/SjYv241ghyy+MB9lBWBcadV1vTA3rx/usPCp/o6igXxtKBXv2aGwnWPFU7irnw6ABaXFtmc2HucqKRiIA4fFyQy9iBZvP9+KpHwj7Vut5YtHA/mdNYZ2qB+HyI06IxPqHLN6xbIG50fBbsjbGcDCAv2Y/FMArSDZOGeR6wymetLrFYhKAj2HJoplGAC6OY6i9SkHxEXKUwVK9elONtz9EcWhFSIB5DOtsTSV/fEvnxb9Lj7jS2x5u16yqxUVAuyS2NFI/cczoDVKt6a2X2tnXPQhuWjf2q2c0IPUAz82oKYPPEAUqCwYsVNAAuS6gzt4NGpKKtME0QV2yb9g5w5xmBVqWDWOVUyCscL7NZlF0RwsZkESFsw4oqXIZG4FkorpHzJW63ovcq9PaIjeVpdTlhaUVbnnP2xLIr+2Fi1qJwD7coMuqPKcvLetpcaQoMLhUmZ96co+/LSoa/xHb9+VyyUlvIFdOGHXOkqaJ/gSmDMB+IB9b/N9Ths+Ys/iz9JGqhE8gAFlMH+tRI4fPxvtnqDArZPWTxIWXjoKUGvww0Nsbb9iNniO+7TfI0WF5WD1G6+USrlimUWxB4SfpmVZzHj/5o7ztyiUkttIGFfFHWkICtwvlPAi766Ngm7DzsNa+LFEyBvk7c2vX4iIuy4IFv+80fnmXKep+Dik7OMikP6fYAgVYX4gzkZIbSMYtQ8908aLxyGOSvusTj2KmZJJVicCAOKEImrxTLm/vzbUVHdW2j2z5I3N7+1V6M+0l9ozozfoeOTcabCvdtMMeTiyjMxZhN6dPmVK7modElbKfqsEg4krXsPPkKzkNykyxVI7J1qX6YAABn4SURBVCdOYgk+spxPW1HVG/uP3O5qd/v9rPRe8vrGt9frwvNHCc8dMLYEYW/eTFuQiz82WUADhqR9BIWce0VcsfQ8c7QFcUtMuC7A/YfzwoApvjKzDCysDBurURgY5v3lF1tasSWW4I0FTL224f2vIjW5fxec+Z8MK6tTsDc/XSz2ovONjdyNXknfiz6OowEaaixYu9hg8826Xa7CzTLXQbIz1IzXznS87+CrJAuyYkDpcpkC0UneuYpnm3K2ORejUSu5iryy/uOXorX7RM0m7LD0rhiaCcyTTyI5gYe2sVv/mQHM07e1DQuQYZpcBR3hfspkDSA4NJ7g5BAJKk6bcWJXM47tNNqCt4RoLOiqS385ul2m9FhpaEol2HnwHrBDIVEaNSeRF1cvmBYfvXupKAOcx5TdbrJbTn9t3gKJoyzs1wYoMFImR3e2k7bTWNqfon5ngWEMaktUW26xxcgf32lEc70wdy0swUSX/grbl0UdJe7yq7B0GI6ImPmzObRMQV7eMj81NnTPYbEfXLYP2Xt4JoymC2ZJ7IEF5B+t3Yc+3ZcJyNExK5YcO+eHv5Ir2KOODgzAzdcEI0oiRYCdxHGORmqqteDYLqMNKCy5hBCNXTaypRcDihi39KyMQs7Be9DUHCGEuB3ysCWOY798cuA+yty8xW7sRIudbPlzCws5hf4pXyAgoH3qHCH1KjrUgnWfciv/JlTKHnMLxfE/gcI3qYQj3bVdWjf1wl0+emDZvj07U3ulDSDzdj9pVsprxP0e/mnB/FPXo6iUc5YhIZ85wXiFBJYiOXEVNGFHBON5PqOaMjPWLq5zmkeX9WFFeS6fJKgjhE2Uk7uNNrCw0zOhmjyQILaHHLEpcsT2lIElq3OlNTZrkHPwbpjNwuv9lzz06+xM3dnk1R2VP3BFcC59WDH33YfugKGBVyIVLqw9ThOlOYD46G22aqxCNRYotWaRAWUnnKcFYsesI6aJmyq0OK/FFivP/lzJXm/PLqz+SFxPGWJsYJEjOII7WFjVWla9VuT2V/mDd7fOzVWHnBb8GtKeAsxXf8+hO0FFjJQT2Xht2LNybRr1MYQGF0Cp4LYssifflmX1nIqGssI2Y2aq26QkFVPn+ioLCg4YcWp/CyoLhfUNYwt9lrSbJexmXxe1zv5ixlCfiJyDd4mpqo13mwI6b2167ZeIsBPXij7qeQOcPjMcx057dEiPqBesKrcBJSiwAgqZAQqFAQq5AUqZwWnp4gOrG7F3lfO9DXMiHDsz1Jb0zRtNrK9Kqy7R3WW2rwoDS3hMW7DkHpuKUr0w7uyObNemBNurGz58JkpzwO0sW3wn6+CRv6Osqh/fbp2SvqlwB6pzFnPSbeT0EHRJc/2ykNMgHIjE/Kq0Dh/ZVWb7ssT0lIOE9sE+T106y81R2TOizxbxnLN6ce+u0TtyOdhEUJImY7itFiLfQu+CCuEDzFqqjqNq84ewmpx/PQZMCrLVN/S1JvZXhekrCwpFQHhPyDVJkGt6QBYm0j72wjLQbPCPdz1rVCkqBAuc4jqBxWUDkXdSkAB7rkP6FJ21uRaVWz6AqabQqVyBSSMRP2wsIiMOcS4R55SpwASe+Kq0iiwN0kIRkQy5rgfkuhQEBOkE0ob8mp2psVU8OBc79sYf7+ZrIw575arbZ8twCWRuR2yqts1Hc0n7ctMX9lFE9UHEsNkgf4b7sSPmlK4/Q6Ws8oCUrg3R+lUpOmQCqwwsdgsIjoRc2xMKXSoUUamQyF3LWE8InlmSoX25DUBeXrcoK1aX45Xaz/UN0dh9OBMms/8GWLky+TV7lqLx5CanXWXB0Yi4ajbYG/PC5i0XGKdCn0dgbKIoyW85+5dnQnOD+GCxLcnU8QhQxyIgUAdpcCQCgnW2r4xE4SSVK7GOyM6ItE3MuS/I3A2fXh2j2fYbH8WFpO2sp1r2bGTI/R/q852bm0gCoBk2G/JI+xWXrui9EGGhwt3FCDmvF/IyGc+CpTjfZPu3yeAZsJwvB5Epwb42Z4FzFjTS4CgEBGkhVYVZszO1547O2oTnz9v5lFmpFCbDiStG3nP4n6iq7eFKV7/qw4DBAMKlhQ1IR2BXx6ldVYpqDLv8TS7sfIqGXYoyoBTnt+BMfgsaajwPlgsNIlEEN9OW+nlmmfm59cuX17QByLtb5x5Rh5z22hNaU9cVu3IzfWoShRaGLanY0opLC0mZiJA+N3EhRXzUVqR0W8GJ1heJrBbYgFKSd3YpVl/tdbDslcoCbmwDkDc3vrVAE35kljcNmHvsVpTqL/OmCKKN3VSyB9Xb5nPir0oYhPBB3LeEUokZQ/q/C/Y18ffGivEykJzOlaLgsBImg3eyXBJCP2i7xNr2VG9lcLXH70POn1C2xGJLrc7WTLVFqNzwBqxm5zUK2bFlxND7IQngd2iRnPA7usZt6DSmY/nVWJ41s+EMWqpO2P5MVSdhMrhWw94Fw+S3SxH23tYXy0JDigRNA8RXsM62F6EWE8p/nwNLY6VTU7DTloih90EWEu2U9kKC4MBSDEn7gHc/X+2wff9s1De2t4O5QY8W/RG0VB4XHTDtAPLqho+/jtLsm+pNo7ElFltqdZZWseZFsC+Is0akckQMux8KnetJ9/r1+AqRmoPOhvL5342mUGza9W9OcrLLVmPFUbRUHoVRfxRmgb4wBFjWDiCvrFs0PFqXs5GTZCISbT8wG+x+xN9b5ca3YazI56RG2KA7EZjgXh36+OjtSOn6E6fxfJlIX52KffnTXRLR2tKAlqqTMNeVwdxQBgv7t74cliZ++zNixZgOs7C+s/XlyrCQAvFiGTmo3RnuRaq3L0BT8W4O2gKhff6G4JRrONE6IvJGjUW3he6AgRgRg9TcAnN92TngmOtKzwKnruzCveEuELJgzYplCzoEyNz1C76P0e4WJ5cKR2saTcHYvu9BsNhjf2yGg9+j/sgqTqIHJY+Fur8wq1qZrAEjr5jLaVxfJtqfPx0V1fYvR4WWvbn08IzqTe8WUqm1YM2K70+08u8QIC+vWjIqNnbLeqGF4MtPhGTEfEVwib7x1CbUcEwUo4obgPAhwgYADe3/DgJVepdk95VOm3Y/DmOL2mPisAQNHQ1mN9H921terw0PPS5uPKcT9UVLKSmi2VsqjkC/8S1OI8gjkqAdzW0jyonhn0RpPT+HLkL0RDV8ROJFy2eDzouxHWIKMn9ppuYeXgB5cfXiT+Ojd3j1QoIlBlu3Yw7Yv/7QLE01KPvlSU6iSgM1iLr2JU60fImSEtagW9w6vt18ht6dDborSkiA8Z9latfwAsjTv/4Rk6D9tcTb7tR782agssYrXvi8bV3yfYcvofZ8iBRRE+dCqhTnA63THERaj694y+8rHU4UjcPJojGeEqcwO1ObaG8wh7WEXl6/4HCsdrfndkodSJl/8gYUlbl39OmupWPrziDxz3uMAnU8SkJi2rE8s3w2qJVbMgPd6CcgixC84sQ5mdhLbdhl3JZ57tpGjP57D89AZa3HXoovZWdq/+MSQJ5Z+fPDPRJ/flsMI3DlWXDmKhw9bQvu8liLaijHkKKdiKsvRZea01CajW3Gbg5Q4HRYFxQHR2NrwhAcWPMSp3BZxiRicCaU8QNF12XMkDmQEG6AFV0YngP8sfMpmMyeycBplZA+n8/U2C0l5rQa3Zub3qrThB1xLTSLp2E6ImdFUVhxFE+1CSfWYczJPxBo5lacvAYU/zM245cLQNSRvKF9b0FwT8/Ub7xqwOtQ+kDZBr7zVt8Uje37ZvPt5hI9Bf1laaZukqPOTgHy/Kql2Ymxm1y70nRJ7LadWA4tlthB7CazmPBgznwkcHAJ6UgWVqmJVWyy14KSRkF92W1iq3GO/+B+HyEkyGNOfYLp5cn0tBJCb/osQ/ejWwCZ8mFu8OghX9YGKfVeOUryBEBUpkbMXfu8IJM8taGmHR9FdF9bVKAn22WpS0RLjSqmHh7KmghQ7M6epXVa8dPpF4QZ47UNH/0Rqdk/QkzD2OPtCYA8sGMekqtPCaLejyYjlrb8tTyTRyRDO/pxQXjzYdI7+XvE6Li5ufDhKzZtzsF7YaiPE3sYUEpmLp2l+cTZQJwA8sLv3/fSRuQc8kYwjtgAuaJ0H9L3fenMTrx+f6m5HvssZlveJt3YZ3j1FYq4e+Jv6BLrdZ9TXupU1vTA3jyPXL0dyc7UcnKZ5gQQpuXL6xavi9XtGM1LYwGIxSySEt5cg39teR/BJuc1OPioUmK14sWAQMgnzOHTTVDaxJjN6NHlF0F5is3s8PGbUVIh/gkfCL03O0M3j4s+nAFy/3e7u/RK+OlkcOAZzn24COCM5tCxyTijF6dS7k35KzDmlDhv2XVdR+CHlOucqSfa79HavejT/VvR+AvNmLmXnHVO5RdF6YIc+dmZWs53e7we9udXZ69MjN7s0UuJ7QfuR31D+4s5FwzTrsvsnAXoXnXOcVMIlud4HItIwgeDhHVC5COgJuwYLkv9lE8Xr9IWnhmKI6c98EKh1szsWZGLuCrLCyBT5h9XX5X2lT4spNAjacXrGqOxY794pz+vrvkvFBzuL7ga83w6Y4ACT4x7zpWugvQJDirFkH7+E36769As1Bi6CKK7PSYUdNfSTB2vNRwvgLCBn1/1RXZi7B8euRdhN+jsJl2MFm8oxmNb3xeD9Tmebwx9AEWh4p/IdKSEQlaP4Ve8Iqp+QjGvNiRh96GZQrGzy4cQTF6SoeWWkOxPLrwBwvq9s+XVurDQk6LerjcbQ7HjwGzRAqaGFe3A1Fxx659/02cytsQPFn3i7Q0w7kq7LkZek6mjgcXcZ5433k/Zmdob+SruEkDm/Prl813jNzzLdzA+9McLx+NUsXiHZlcW5eAfud/xEYk37Vd9bsG2+EG8+wnVgX1B2JfEl5unbs4tlA78YpZuF19buAQQNsgr6z86Gq3d353vgFzoG5p02HngHpit4hWJYR66/97yLhdxXKZ5bdhDHXr+usyQZ0e2B2F7EV9tzcZw7MqdieaWcHFFJOTN7AzNY64M4jJAHv9h7VXdYtZtCgyscGVch33yT96IojLxlyavrPlvO09doZRhHr9PenGTzvS4vNdniFAfFUolwfl4aGlVIJXWpXx6ZzfnGfs60NBlgDBez/2W/W2XuM23CGk5MbJZ2JPv3l2LkaIXp5RzvrYnPr5C/I2nI9uzexB2H+KLjWVMZJkTxW4SieT2z2ZGuOwq4RZAmHJvbHpLrw07ohFC0bLKfjh49O9CsOLE48b8lRh76g9OtHyJ1nYdiR9THHpS82XJm757l1/RJcZ5/RHejN3s4Kk7D0LIl0syNG7FSrgNkGdXfHNH1/hNn0il7hWcN9THI+cgx5BVNyeotXt0fSke2vIuVCxbsoDNoAjG+4PvRUWgIO8NlyWLi8pBarcfXO4vRsfa+kTs9EAZZwDVJkL7fpWhc8vn322AMCO+um7e6ijd3nGuGpTFnLPYc0+3inVzMbmhDLfIhHVv+D71RvzRZZin1Wk3njqkAAP7LPC6HOcLsGabZ4opE+COJZnaz9xVXhCAMCHe3fpSlTqkkPdxRHHZIOSd5FYDw11lz+9f8fscmOrOnvC8GxiCGGK/eD2fcQ/rUjB/wJ18uohGy77qowcJE+cihJBrtz8HSoWxsxN5Ps3O1AoyCYIB5IXVX9wcpdnzvVxWx4mnxSLH0YKJYADxdCtd+QRYwuPWpiESfBwoTIaRh6/xrdtrlrzB25lpmJ035DwLs0W8Y/vWuaTAUSI3X549I1oQF21ODzPXB/iFNZ8sSIja7rQAT21dIo4WXAv2r6fbmR8eArW0TcLAZGDvtQeVQRgqlbkk0tpuo/BjT4/6cXKSM63nF9BF2M1JwImHu0Qbdz2JFpOojhfnRLQC4z+3k+PKFT0EBQgT4JUNH++K1uyz65/Ovhjsy8G+IJ5s1GLGmR+cOz4OCZDjDrkS7KvCpZUH6fBd6g1gx7q+2LxdBXfLnn+hych75e2qKZ/IztS+5mrnjvoJDhA2yNtbXq0MDz3ZJjt8Y5MOp4pH4oz+ciHl58TLaqxH6QruF6kMHFNSrkZiQzkSDMXtPH6Zp25haJztb123UTC4WI+bk/BuEunCDyEt5Qs3ubjWfdv+B9HQ6KFaTBSfZ8/SCu5EKwpAHvniaJ+eKVn7VYpaCShBUflgnC4ejeYWJ/WpXZsHh71YNaLy3/g57cXc8A5YqeDWxjx/Ew3Ftv9ZEBrnNQ9dV8zjrSRynoott9mEYnezumzosql93Ltr6MDAogCEjfPUyv89rFMfe7u4bDDKq/q4Mrdu92FVnVh1Jz4t5m8fggh0osVnXDFpRw18GQEBjWIO0Yb3rtwM1NSJlzmyrSKkFiDDszMjRCmrJRpAmBJ3fXHs8abGMEHXhFxnmZXiqvyDe+1wIg1AzE3+E2DE1Q6Mrk/3ZYjW7uPTxWXag8duRZlHqxTTG7MzdaKV1BIVIMzK6Yv0c0HBLeW5y9PStmNTwQ5U71zMmZtEFojoG/w3l60zRWN1O9ErebkzMrd/P144DqeKPZZ0GpTivqWztB+7LbgDBqIDhI09Y6F+MSUQ5OLGmTHq836B4RB39wqJUo3oSa86Y+vXvweryjGk/3ui6lBcPhB5J24WdYzzmVNC5izN0Ige0+wRgNi+JFl6FuooqgVZVSdW3YlrkwZpEXUNvz0KV96+Rtc/ZSm04XmiiFVlSMaeQx55/52Vn+C97AztQ6IocwFTjwFkzhwqORZfuYoALvtsOTJI5ab3YSzP5WwzWWgsdOP/jzO9vxNqI/LRv2e24GqU6tOQe0yY+opchCMEnyzJ0HosjsBjAGHKT/mwPFiplPwGCkE9+cpXzYG5nnvknCy8C3RjnuIyH52KRuivyOnikThWeLXHbEQp/XLpLJ1b7ut8hfUoQJhwd3xSHWaxWleAUrdBwgrWlP70CKjFxFlvuaY7tKO4XxpyZuwHhEJ9RVhyt2MFE1FS7jT3s4BWIV9nZ2r+ISBDTqw8DpBzILFYfwSoywmxWb3r8lX/5aRkK5EqfhDCB4tfSoGXUB4mdtf1pKq2O1iJtNq6BI9JLkTgk6vCegUgTNj0JaVBMAYsB8F4vsIbyw+jchO/hAshvW9ASKoHMvfxVcYL9JGag+jHs4YhK8l8umQECkuv9LTEgrmuuyK41wBiE5ZSMmNx5XeU4m9chedTg7yVp6fKnnHVwRfo2HIrTpfj9GSLZdcvr+yLsqp+MJkCPSs6wfvZGdoHPTto29G8C5A/ZUnP0rNcqU5PJgyHfkR93kpe9tKNeRKy8K68+lxMxOyOJDS4AEqF4ZzaRlMIGpu0aGzWwNgiTJwMf5vS57MzdfzW0PwHcdrDJwBiW3Jl6V9mLlz2JK7Z+QkaC7Y7VaiVQBKghG7cf8DuOi41P7MAxezsWdoPfUFqnwGIDSQL9feDoJ1DFPOpYr5VXBs7xtWMeAQMJJeaX1mgnlBMXzJLy90VQmT1fAogZ0FSNQmEfgZQ26u/7NdnYGms5GyGwMQhCBvowVtdzpJdInRsAbqPEus/l2ZEecarkuN0+BxAmNy3zyvvIQ0gi0u+v3c4Rz1sZOyUip1WXWr+ZgHyVbOhceayRxO41d72oHo+CRCm//hJt35DgSlcbaHuPxVByWO5kl+i8xELUIJnlmZo2f7TJ5tPAmTs9VPiiJUWcbVY+KAMqBI8nx2Fq3yX6DqyADkJCe7Nnqn5zZft45MAGT9pSj8Kut+Z4STyIIQNyoAyqrcz0ku/+5IFKP1cGlA/+9M7u7UvKu9Lctoch32wjR8/RU3l1KHxZOp4hA2YAVm451MH+aDJ/EWkegryyNJMTZa/COyTAGHGGzfxlvkgpMMqmLKIbrURQ2appao2iVP8xeYXq5zfSU2mxz69N+aUPxnAZwEyduLNyQQBr4Nc4IZCsDhQYnwg/OZPHqYUL/mTsS9SWUthtT6VfVek/5TcPW+ifBYgrTJOuG7KjRYrTZJIYLFakb/2l29Xtf6WvlDfCwQsJHDyRfrw+bTaBPjAoiTPfD5d85cfi09L3F44nwcIF3tOW1h+q4RImN9OXy70l2hEt8Dv1ErmLL1Ls0X0kUQeoFMApNVG0xdUPEok5GkA3i3MIfKk+Sx7ilwK+tLSWTqXKzr5mm6dCiDMuOlLaBBaKp8E6OMAET+duK/NqHfkKQDBa9kZvuFgKKQJOh1AWo1z5+IzOjOVPQqKhwFc8loU8qn5i1chQN7OztS8LQ5773PttABpNe2UrMIIBVE+QCi5H4DO+ybvBBJQsPxBH/iKS7qYFu30AGk1Hks7dCK+8h4KejdA0sQ0aqflTbABVDIvOzPiq06r4wWKXTQAOV/vGYv1461WTCcAS5fvkZpgfvxAVVEgWwJ8sSRTu8OP9XBJ9IsSIK2WmvjeUYU2MJxlPWNew5f85M9/hAg+J0S6bMnMcJ8JXnLpCXez00UNkPNtd9v8Em1AgOxvhEpuoqAXafoT+jWA5c2h2u+XTSWC19pw81n1SvdLAOnA7FPmV6kVAZbrCSWTWPyWV2bGY4PShYSSFU1qzc/LphKLx4b1k4EuAYTDRE1fWDEaIBNA6BQC0oNDFx8mIfsIod9YLPj987u0OT4sqE+IdgkgPKfhtvkGrUxiGk0lZCShdAQFvYwnC0+Ss5ysOwFsBJVsUFmb1y+4O9ZzpaY8qalIY10CiJuGnbOOBhw9Ut1LKqEplNAeICQZlCYB8FwlmbOBPasoxQlIyDEKK0sBk780Q5fvpnoXffdLABH5EZi2sDJeYqVxVIoYCSRRIDSSsowtVmgIIWGU0BhQqACoQKAEBQVIE0CbKdBEgBJQVIOQSkqoHpSUSySklFgsZ0gALf70zkjuae1F1rUzsv9/osihyZiuqMAAAAAASUVORK5CYII=`
+	IT_END                string = "end"
+	IT_APP_NAME           string = "Network Assistant"
+	IT_SETTING            string = "Settings"
+	IT_TYPE               string = "Type"
+	IT_TCP_CLIENT         string = "TCP Client"
+	IT_TCP_SERVER         string = "TCP Server"
+	IT_UDP_CLIENT         string = "UDP Client"
+	IT_UDP_SERVER         string = "UDP Server"
+	IT_PORT               string = "Port"
+	IT_CONNECT            string = "Connect"
+	IT_RECV_SETTINGS      string = "Recv Settings"
+	IT_SEND_SETTINGS      string = "Send Settings"
+	IT_SAVE_TO_FILE       string = "Save to file"
+	IT_SHOW_RECV_TIME     string = "Show recv time"
+	IT_SHOW_HEX           string = "Show hex"
+	IT_PAUSE              string = "Pause"
+	IT_SAVE               string = "Save"
+	IT_SAVE_RAW           string = "Save the received bytes as they arrived"
+	IT_CLEAR              string = "Clear"
+	IT_SEND               string = "Send"
+	IT_APPEND_RN          string = "Append \\r\\n"
+	IT_AUTO_CLEAR         string = "Auto clear"
+	IT_SEND_HEX           string = "Send HEX"
+	IT_SEND_CIRC          string = "Send circularly"
+	IT_LOAD_DATA          string = "Load data"
+	IT_DATA_RECVED        string = "Data received"
+	IT_WAIT_CONN          string = "Waiting connection"
+	IT_SEND_COUNT         string = "Send count:"
+	IT_RECEVER_COUNT      string = "Recv count:"
+	IT_RESET              string = "Reset"
+	IT_NO_CONN            string = "there's no connection"
+	IT_DISCONNECT         string = "Disconnect"
+	IT_LOCAL_IP           string = "Local ip"
+	IT_LOCAL_PORT         string = "Local port"
+	IT_STOP               string = "Stop"
+	IT_SEQUENCE           string = "Sequence"
+	IT_HEX_PAYLOAD        string = "Hex payload"
+	IT_LOOP               string = "Loop"
+	IT_RUN                string = "Run"
+	IT_RESUME             string = "Resume"
+	IT_INTERVAL_MS        string = "ms interval"
+	IT_MSG_PER_SEC        string = "msg/s"
+	IT_BYTES_PER_SEC      string = "bytes/s"
+	IT_MAX_COUNT          string = "max count, empty for unlimited"
+	IT_MAX_DURATION       string = "max duration(s), empty for unlimited"
+	IT_BENCHMARK          string = "Benchmark"
+	IT_BENCH_CLIENT       string = "Client generator"
+	IT_BENCH_ECHO         string = "Server echo"
+	IT_BENCH_SINK         string = "Server sink"
+	IT_PATTERN_FIXED      string = "Fixed pattern"
+	IT_PATTERN_RANDOM     string = "Random pattern"
+	IT_PATTERN_INCREMENT  string = "Incrementing pattern"
+	IT_PACKET_SIZE        string = "packet size, default 1024"
+	IT_RATE_MAX           string = "rate, empty for max"
+	IT_BENCH_DURATION     string = "duration(s), default 10"
+	IT_START              string = "Start"
+	IT_EXPORT             string = "Export"
+	IT_SERVER_BEHAVIOUR   string = "Server behaviour"
+	IT_BEHAVIOUR_NONE     string = "Receive only"
+	IT_BEHAVIOUR_ECHO     string = "Echo"
+	IT_BEHAVIOUR_DISCARD  string = "Discard"
+	IT_BEHAVIOUR_CHARGEN  string = "Chargen"
+	IT_BEHAVIOUR_DAYTIME  string = "Daytime"
+	IT_BEHAVIOUR_TIME     string = "Time"
+	IT_BEHAVIOUR_UPPER    string = "Echo uppercase"
+	IT_BEHAVIOUR_REVERSE  string = "Echo reversed"
+	IT_BEHAVIOUR_HEX      string = "Echo hex-encoded"
+	IT_SHOW_RTT           string = "Show response time"
+	IT_HISTOGRAM          string = "Histogram"
+	IT_HEX_DUMP           string = "Hex dump"
+	IT_BYTES_PER_ROW      string = "Bytes per row"
+	IT_TOGGLE_VIEW        string = "Toggle view"
+	IT_SHOW_PEER          string = "Show source address"
+	IT_ENCODING           string = "Encoding"
+	IT_MAX_LINES          string = "max lines, default 10000"
+	IT_MAX_BYTES          string = "max bytes, default 4194304"
+	IT_SEARCH             string = "Search"
+	IT_SEARCH_TEXT        string = "Text"
+	IT_SEARCH_HEX         string = "Hex"
+	IT_SEARCH_REGEX       string = "Regex"
+	IT_FILTER             string = "Filter"
+	IT_HIGHLIGHT_RULES    string = "Highlight rules"
+	IT_CAPTURE_RAW        string = "Raw data"
+	IT_CAPTURE_HEX        string = "Hex text"
+	IT_CAPTURE_LOG        string = "Timestamped log"
+	IT_CAPTURE            string = "Capture"
+	IT_ROTATE_SIZE        string = "Rotate at size (MB)"
+	IT_ROTATE_AGE         string = "Rotate after (minutes)"
+	IT_ROTATE_KEEP        string = "Rotated files to keep"
+	IT_ROTATE_DATED       string = "Date-stamped file names"
+	IT_ROTATE_GZIP        string = "Compress rotated files"
+	IT_EXPORT_PCAP        string = "Export pcapng"
+	IT_SESSION_EMPTY      string = "No packets in this session"
+	IT_REPLAY             string = "Replay"
+	IT_OPEN               string = "Open"
+	IT_TIMING_ORIGINAL    string = "Original timing"
+	IT_TIMING_SCALED      string = "Scaled timing"
+	IT_TIMING_FAST        string = "As fast as possible"
+	IT_SPEED              string = "Speed (x)"
+	IT_STEP               string = "Step"
+	IT_DECODER            string = "Decoder"
+	IT_DECODER_NONE       string = "None"
+	IT_DECODER_JSON       string = "JSON"
+	IT_DECODER_MSGPACK    string = "MessagePack"
+	IT_DECODER_CBOR       string = "CBOR"
+	IT_DECODER_PROTOBUF   string = "Protobuf (raw)"
+	IT_DECODER_PROTO_DESC string = "Protobuf (descriptor)"
+	IT_DESCRIPTOR_SET     string = "Descriptor set"
+	IT_MESSAGE_TYPE       string = "Message type"
+	IT_NO_DESCRIPTOR_SET  string = "No descriptor set loaded"
+	IT_DECODER_TEMPLATE   string = "Template"
+	IT_TEMPLATE           string = "Structure template"
+	IT_TEMPLATE_FORM      string = "Template form"
+	IT_NO_TEMPLATE        string = "No structure template defined"
+	IT_BEHAVIOUR_MODBUS   string = "Modbus slave"
+	IT_MODBUS             string = "Modbus"
+	IT_MB_MASTER          string = "Master"
+	IT_MB_SLAVE           string = "Slave register map"
+	IT_MB_SLAVE_HINT      string = "Served in TCP/UDP server mode with the Modbus slave behaviour"
+	IT_MB_READ_COILS      string = "01 Read coils"
+	IT_MB_READ_DISCRETE   string = "02 Read discrete inputs"
+	IT_MB_READ_HOLDING    string = "03 Read holding registers"
+	IT_MB_READ_INPUT      string = "04 Read input registers"
+	IT_MB_WRITE_COIL      string = "05 Write single coil"
+	IT_MB_WRITE_REGISTER  string = "06 Write single register"
+	IT_MB_WRITE_COILS     string = "15 Write multiple coils"
+	IT_MB_WRITE_REGISTERS string = "16 Write multiple registers"
+	IT_MB_UNIT            string = "Unit ID (default 1)"
+	IT_MB_ADDRESS         string = "Start address (default 0)"
+	IT_MB_QUANTITY        string = "Quantity (default 1)"
+	IT_MB_VALUES          string = "Values to write: 1, 0x10, -1"
+	IT_MB_FRAMING_TCP     string = "Modbus TCP (MBAP)"
+	IT_MB_FRAMING_RTU     string = "Modbus RTU over TCP/UDP"
+	IT_MB_SILENCE         string = "Inter-frame silence (default 20 ms)"
+	IT_MQTT_CLIENT        string = "MQTT Client"
+	IT_MQTT               string = "MQTT"
+	IT_MQTT_TCP           string = "TCP"
+	IT_MQTT_TLS           string = "TLS"
+	IT_MQTT_WS            string = "WebSocket"
+	IT_MQTT_WSS           string = "WebSocket over TLS"
+	IT_TLS_INSECURE       string = "Skip certificate verification"
+	IT_MQTT_WS_PATH       string = "WebSocket path (default /mqtt)"
+	IT_MQTT_CLIENT_ID     string = "Client ID (generated if empty)"
+	IT_USERNAME           string = "User name"
+	IT_PASSWORD           string = "Password"
+	IT_MQTT_KEEPALIVE     string = "Keepalive seconds (default 60)"
+	IT_MQTT_CLEAN         string = "Clean session"
+	IT_MQTT_WILL          string = "Will message"
+	IT_MQTT_TOPIC         string = "Topic"
+	IT_MQTT_PAYLOAD       string = "Payload"
+	IT_MQTT_RETAIN        string = "Retain"
+	IT_MQTT_FILTER        string = "Topic filter, e.g. sensors/#"
+	IT_MQTT_SUBSCRIBE     string = "Subscribe"
+	IT_MQTT_UNSUBSCRIBE   string = "Unsubscribe"
+	IT_MQTT_PUBLISH       string = "Publish from the send box"
+	IT_APPLY              string = "Apply"
+	IT_REFRESH            string = "Refresh"
+	IT_WEBSOCKET          string = "WebSocket Frames"
+	IT_WS_CLIENT          string = "WebSocket Client"
+	IT_WS_SERVER          string = "WebSocket Server"
+	IT_WS_PATH            string = "Request path (default /)"
+	IT_WS_TLS             string = "Use TLS (wss://)"
+	IT_WS_PROTOCOLS       string = "Subprotocols, comma separated"
+	IT_WS_HEADERS         string = "Handshake headers, one Name: value per line"
+	IT_WS_TEXT            string = "Send text frames"
+	IT_WS_BINARY          string = "Send binary frames"
+	IT_WS_AUTO_PONG       string = "Answer ping with pong"
+	IT_WS_SEND_PING       string = "Send ping"
+	IT_WS_CLOSE           string = "Close"
+	IT_WS_CLOSE_CODE      string = "Close code (default 1000)"
+	IT_WS_CLOSE_REASON    string = "Close reason"
+	IT_WS_SEND_CLOSE      string = "Send close frame"
+	IT_HTTP               string = "HTTP"
+	IT_TCP_TLS            string = "TCP client over TLS"
+	IT_HTTP_REQUEST       string = "Request"
+	IT_HTTP_HEADERS       string = "Headers, one Name: value per line"
+	IT_HTTP_BODY          string = "Body"
+	IT_HTTP_AUTO_HOST     string = "Add Host header"
+	IT_HTTP_AUTO_LENGTH   string = "Add Content-Length"
+	IT_HTTP_SEND          string = "Send request"
+	IT_HTTP_RESPONSE      string = "Response"
+	IT_HTTP_PARSE         string = "Parse responses"
+	IT_BEHAVIOUR_HTTP     string = "HTTP mock server"
+	IT_HTTP_MOCK          string = "Mock server routes"
+	IT_HTTP_MOCK_HINT     string = "Served in TCP server mode with the HTTP mock server behaviour"
+	IT_TELNET             string = "Telnet"
+	IT_TELNET_MODE        string = "Telnet mode for the TCP client"
+	IT_TELNET_WILL        string = "Options we enable when asked with DO"
+	IT_TELNET_DO          string = "Options the peer may enable with WILL"
+	IT_TELNET_TERM        string = "Terminal type (default VT100)"
+	IT_TELNET_WIDTH       string = "Window width (default 80)"
+	IT_TELNET_HEIGHT      string = "Window height (default 24)"
+	IT_TELNET_LOG         string = "Negotiation"
+	IT_PROXY              string = "Proxy"
+	IT_PROXY_HINT         string = "Used by the TCP client and, with SOCKS5, by the UDP client"
+	IT_PROXY_NONE         string = "No proxy"
+	IT_PROXY_SOCKS4A      string = "SOCKS4a"
+	IT_PROXY_SOCKS5       string = "SOCKS5"
+	IT_PROXY_HTTP         string = "HTTP CONNECT"
+	IT_PROXY_ADDR         string = "Proxy address, host:port"
+	IT_SOCKS_SERVER       string = "SOCKS5 Proxy Server"
 )
 
 var (
 	log         = logging.MustGetLogger("APP")
 	i18nTextMap = map[string]string{
-		IT_END:            "结束",
-		IT_APP_NAME:       "网络调试助手",
-		IT_SETTING:        "设置",
-		IT_TYPE:           "类型",
-		IT_TCP_CLIENT:     "TCP客户端",
-		IT_TCP_SERVER:     "TCP服务端",
-		IT_UDP_CLIENT:     "UDP客户端",
-		IT_UDP_SERVER:     "UDP服务端",
-		IT_PORT:           "端口",
-		IT_CONNECT:        "连接",
-		IT_RECV_SETTINGS:  "接收设置",
-		IT_SEND_SETTINGS:  "发送设置",
-		IT_SAVE_TO_FILE:   "保存到文件",
-		IT_SHOW_RECV_TIME: "显示接收时间",
-		IT_SHOW_HEX:       "16进制显示",
-		IT_PAUSE:          "暂停",
-		IT_SAVE:           "保存",
-		IT_CLEAR:          "清除",
-		IT_SEND:           "发送",
-		IT_APPEND_RN:      "追加\\r\\n",
-		IT_AUTO_CLEAR:     "自动清除",
-		IT_SEND_HEX:       "发送16进制",
-		IT_SEND_CIRC:      "循环发送",
-		IT_LOAD_DATA:      "加载数据",
-		IT_DATA_RECVED:    "已接收数据",
-		IT_WAIT_CONN:      "等待连接",
-		IT_SEND_COUNT:     "发送数:",
-		IT_RECEVER_COUNT:  "接收数:",
-		IT_RESET:          "重置",
-		IT_NO_CONN:        "没有连接",
-		IT_DISCONNECT:     "断开连接",
-		IT_LOCAL_IP:       "本地IP",
-		IT_LOCAL_PORT:     "本地端口",
-		IT_STOP:           "停止",
+		IT_END:                "结束",
+		IT_APP_NAME:           "网络调试助手",
+		IT_SETTING:            "设置",
+		IT_TYPE:               "类型",
+		IT_TCP_CLIENT:         "TCP客户端",
+		IT_TCP_SERVER:         "TCP服务端",
+		IT_UDP_CLIENT:         "UDP客户端",
+		IT_UDP_SERVER:         "UDP服务端",
+		IT_PORT:               "端口",
+		IT_CONNECT:            "连接",
+		IT_RECV_SETTINGS:      "接收设置",
+		IT_SEND_SETTINGS:      "发送设置",
+		IT_SAVE_TO_FILE:       "保存到文件",
+		IT_SHOW_RECV_TIME:     "显示接收时间",
+		IT_SHOW_HEX:           "16进制显示",
+		IT_PAUSE:              "暂停",
+		IT_SAVE:               "保存",
+		IT_SAVE_RAW:           "按原始字节保存接收数据",
+		IT_CLEAR:              "清除",
+		IT_SEND:               "发送",
+		IT_APPEND_RN:          "追加\\r\\n",
+		IT_AUTO_CLEAR:         "自动清除",
+		IT_SEND_HEX:           "发送16进制",
+		IT_SEND_CIRC:          "循环发送",
+		IT_LOAD_DATA:          "加载数据",
+		IT_DATA_RECVED:        "已接收数据",
+		IT_WAIT_CONN:          "等待连接",
+		IT_SEND_COUNT:         "发送数:",
+		IT_RECEVER_COUNT:      "接收数:",
+		IT_RESET:              "重置",
+		IT_NO_CONN:            "没有连接",
+		IT_DISCONNECT:         "断开连接",
+		IT_LOCAL_IP:           "本地IP",
+		IT_LOCAL_PORT:         "本地端口",
+		IT_STOP:               "停止",
+		IT_SEQUENCE:           "发送序列",
+		IT_HEX_PAYLOAD:        "16进制数据",
+		IT_LOOP:               "循环",
+		IT_RUN:                "运行",
+		IT_RESUME:             "继续",
+		IT_INTERVAL_MS:        "间隔(毫秒)",
+		IT_MSG_PER_SEC:        "条/秒",
+		IT_BYTES_PER_SEC:      "字节/秒",
+		IT_MAX_COUNT:          "最大次数，留空不限",
+		IT_MAX_DURATION:       "最长时间(秒)，留空不限",
+		IT_BENCHMARK:          "性能测试",
+		IT_BENCH_CLIENT:       "客户端发送",
+		IT_BENCH_ECHO:         "服务端回显",
+		IT_BENCH_SINK:         "服务端接收",
+		IT_PATTERN_FIXED:      "固定数据",
+		IT_PATTERN_RANDOM:     "随机数据",
+		IT_PATTERN_INCREMENT:  "递增数据",
+		IT_PACKET_SIZE:        "包大小，默认1024",
+		IT_RATE_MAX:           "速率，留空为最大",
+		IT_BENCH_DURATION:     "时长(秒)，默认10",
+		IT_START:              "开始",
+		IT_EXPORT:             "导出",
+		IT_SERVER_BEHAVIOUR:   "服务端行为",
+		IT_BEHAVIOUR_NONE:     "仅接收",
+		IT_BEHAVIOUR_ECHO:     "回显",
+		IT_BEHAVIOUR_DISCARD:  "丢弃",
+		IT_BEHAVIOUR_CHARGEN:  "字符发生器",
+		IT_BEHAVIOUR_DAYTIME:  "日期时间",
+		IT_BEHAVIOUR_TIME:     "时间协议",
+		IT_BEHAVIOUR_UPPER:    "回显大写",
+		IT_BEHAVIOUR_REVERSE:  "回显反转",
+		IT_BEHAVIOUR_HEX:      "回显16进制",
+		IT_SHOW_RTT:           "显示响应时间",
+		IT_HISTOGRAM:          "直方图",
+		IT_HEX_DUMP:           "十六进制转储",
+		IT_BYTES_PER_ROW:      "每行字节数",
+		IT_TOGGLE_VIEW:        "切换视图",
+		IT_SHOW_PEER:          "显示来源地址",
+		IT_ENCODING:           "字符编码",
+		IT_MAX_LINES:          "最大行数，默认10000",
+		IT_MAX_BYTES:          "最大字节数，默认4194304",
+		IT_SEARCH:             "搜索",
+		IT_SEARCH_TEXT:        "文本",
+		IT_SEARCH_HEX:         "十六进制",
+		IT_SEARCH_REGEX:       "正则",
+		IT_FILTER:             "过滤",
+		IT_HIGHLIGHT_RULES:    "高亮规则",
+		IT_CAPTURE_RAW:        "原始数据",
+		IT_CAPTURE_HEX:        "十六进制文本",
+		IT_CAPTURE_LOG:        "带时间戳的日志",
+		IT_CAPTURE:            "抓包",
+		IT_ROTATE_SIZE:        "按大小轮转(MB)",
+		IT_ROTATE_AGE:         "按时间轮转(分钟)",
+		IT_ROTATE_KEEP:        "保留文件数",
+		IT_ROTATE_DATED:       "文件名带日期",
+		IT_ROTATE_GZIP:        "压缩轮转文件",
+		IT_EXPORT_PCAP:        "导出pcapng",
+		IT_SESSION_EMPTY:      "本次会话没有数据包",
+		IT_REPLAY:             "回放",
+		IT_OPEN:               "打开",
+		IT_TIMING_ORIGINAL:    "原始间隔",
+		IT_TIMING_SCALED:      "按倍速",
+		IT_TIMING_FAST:        "尽快发送",
+		IT_SPEED:              "倍速(x)",
+		IT_STEP:               "单步",
+		IT_DECODER:            "解码器",
+		IT_DECODER_NONE:       "无",
+		IT_DECODER_PROTOBUF:   "Protobuf (无模式)",
+		IT_DECODER_PROTO_DESC: "Protobuf (描述文件)",
+		IT_DESCRIPTOR_SET:     "描述文件",
+		IT_MESSAGE_TYPE:       "消息类型",
+		IT_NO_DESCRIPTOR_SET:  "未加载描述文件",
+		IT_DECODER_TEMPLATE:   "结构模板",
+		IT_TEMPLATE:           "结构模板",
+		IT_TEMPLATE_FORM:      "模板表单",
+		IT_NO_TEMPLATE:        "未定义结构模板",
+		IT_BEHAVIOUR_MODBUS:   "Modbus从站",
+		IT_MODBUS:             "Modbus",
+		IT_MB_MASTER:          "主站",
+		IT_MB_SLAVE:           "从站寄存器表",
+		IT_MB_SLAVE_HINT:      "在TCP/UDP服务端模式下选择Modbus从站行为时生效",
+		IT_MB_READ_COILS:      "01 读线圈",
+		IT_MB_READ_DISCRETE:   "02 读离散输入",
+		IT_MB_READ_HOLDING:    "03 读保持寄存器",
+		IT_MB_READ_INPUT:      "04 读输入寄存器",
+		IT_MB_WRITE_COIL:      "05 写单个线圈",
+		IT_MB_WRITE_REGISTER:  "06 写单个寄存器",
+		IT_MB_WRITE_COILS:     "15 写多个线圈",
+		IT_MB_WRITE_REGISTERS: "16 写多个寄存器",
+		IT_MB_UNIT:            "单元ID (默认1)",
+		IT_MB_ADDRESS:         "起始地址 (默认0)",
+		IT_MB_QUANTITY:        "数量 (默认1)",
+		IT_MB_VALUES:          "写入值: 1, 0x10, -1",
+		IT_MB_FRAMING_TCP:     "Modbus TCP (MBAP)",
+		IT_MB_FRAMING_RTU:     "Modbus RTU over TCP/UDP",
+		IT_MB_SILENCE:         "帧间静默时间 (默认20 ms)",
+		IT_MQTT_CLIENT:        "MQTT 客户端",
+		IT_MQTT:               "MQTT",
+		IT_MQTT_TCP:           "TCP",
+		IT_MQTT_TLS:           "TLS",
+		IT_MQTT_WS:            "WebSocket",
+		IT_MQTT_WSS:           "WebSocket (TLS)",
+		IT_TLS_INSECURE:       "跳过证书校验",
+		IT_MQTT_WS_PATH:       "WebSocket 路径 (默认 /mqtt)",
+		IT_MQTT_CLIENT_ID:     "客户端ID (为空时自动生成)",
+		IT_USERNAME:           "用户名",
+		IT_PASSWORD:           "密码",
+		IT_MQTT_KEEPALIVE:     "心跳间隔秒数 (默认60)",
+		IT_MQTT_CLEAN:         "清除会话",
+		IT_MQTT_WILL:          "遗嘱消息",
+		IT_MQTT_TOPIC:         "主题",
+		IT_MQTT_PAYLOAD:       "内容",
+		IT_MQTT_RETAIN:        "保留",
+		IT_MQTT_FILTER:        "主题过滤器, 如 sensors/#",
+		IT_MQTT_SUBSCRIBE:     "订阅",
+		IT_MQTT_UNSUBSCRIBE:   "取消订阅",
+		IT_MQTT_PUBLISH:       "从发送区发布",
+		IT_APPLY:              "应用",
+		IT_REFRESH:            "刷新",
+		IT_WEBSOCKET:          "WebSocket 帧",
+		IT_WS_CLIENT:          "WebSocket 客户端",
+		IT_WS_SERVER:          "WebSocket 服务器",
+		IT_WS_PATH:            "请求路径(默认 /)",
+		IT_WS_TLS:             "使用 TLS (wss://)",
+		IT_WS_PROTOCOLS:       "子协议,逗号分隔",
+		IT_WS_HEADERS:         "握手头部,每行一个 Name: value",
+		IT_WS_TEXT:            "发送文本帧",
+		IT_WS_BINARY:          "发送二进制帧",
+		IT_WS_AUTO_PONG:       "自动回复 pong",
+		IT_WS_SEND_PING:       "发送 ping",
+		IT_WS_CLOSE:           "关闭",
+		IT_WS_CLOSE_CODE:      "关闭码(默认 1000)",
+		IT_WS_CLOSE_REASON:    "关闭原因",
+		IT_WS_SEND_CLOSE:      "发送关闭帧",
+		IT_HTTP:               "HTTP",
+		IT_TCP_TLS:            "TCP 客户端使用 TLS",
+		IT_HTTP_REQUEST:       "请求",
+		IT_HTTP_HEADERS:       "头部,每行一个 Name: value",
+		IT_HTTP_BODY:          "正文",
+		IT_HTTP_AUTO_HOST:     "自动添加 Host",
+		IT_HTTP_AUTO_LENGTH:   "自动添加 Content-Length",
+		IT_HTTP_SEND:          "发送请求",
+		IT_HTTP_RESPONSE:      "响应",
+		IT_HTTP_PARSE:         "解析响应",
+		IT_BEHAVIOUR_HTTP:     "HTTP模拟服务器",
+		IT_HTTP_MOCK:          "模拟服务器路由",
+		IT_HTTP_MOCK_HINT:     "在TCP服务端模式下选择HTTP模拟服务器行为时生效",
+		IT_TELNET:             "Telnet",
+		IT_TELNET_MODE:        "TCP 客户端使用 Telnet 模式",
+		IT_TELNET_WILL:        "对方请求 DO 时启用的本端选项",
+		IT_TELNET_DO:          "允许对方通过 WILL 启用的选项",
+		IT_TELNET_TERM:        "终端类型(默认 VT100)",
+		IT_TELNET_WIDTH:       "窗口宽度(默认 80)",
+		IT_TELNET_HEIGHT:      "窗口高度(默认 24)",
+		IT_TELNET_LOG:         "选项协商",
+		IT_PROXY:              "代理",
+		IT_PROXY_HINT:         "用于TCP客户端,SOCKS5 也用于UDP客户端",
+		IT_PROXY_NONE:         "不使用代理",
+		IT_PROXY_SOCKS4A:      "SOCKS4a",
+		IT_PROXY_SOCKS5:       "SOCKS5",
+		IT_PROXY_HTTP:         "HTTP CONNECT",
+		IT_PROXY_ADDR:         "代理地址, host:port",
+		IT_SOCKS_SERVER:       "SOCKS5 代理服务器",
 	}
 	systemLangIsZh = strings.HasPrefix(os.Getenv("LANG"), "zh_")
 )
@@ -108,13 +442,15 @@ func getI18nText(key string) string {
 
 // NetAssistantApp Main
 type NetAssistantApp struct {
-	receCount int
-	sendCount int
+	receCount   int64
+	sendCount   int
+	pendingSent int64
 
 	chanClose chan bool
 	listener  net.Listener
 	connList  []net.Conn
-	fileName  string
+	capture   captureWriter
+	session   sessionLog
 
 	appWindow             *gtk.ApplicationWindow
 	combProtoType         *gtk.ComboBoxText
@@ -134,35 +470,188 @@ type NetAssistantApp struct {
 	entryCurAddr          *gtk.Entry
 	entryCurPort          *gtk.Entry
 	cbHexDisplay          *gtk.CheckButton
+	cbHexDump             *gtk.CheckButton
+	combDumpWidth         *gtk.ComboBoxText
+	btnToggleDump         *gtk.Button
 	cbPauseDisplay        *gtk.CheckButton
 	cbDisplayDate         *gtk.CheckButton
+	cbShowRTT             *gtk.CheckButton
+	cbShowPeer            *gtk.CheckButton
+	combRecvEncoding      *gtk.ComboBoxText
+	combRecvDecoder       *gtk.ComboBoxText
+	entryProtoMessage     *gtk.Entry
+	payloadDecoder        payloadDecoder
+	protoSet              *descriptorSet
+	template              *structTemplate
+	templateText          string
+	templateValues        map[string]string
+	entryMaxLines         *gtk.Entry
+	entryMaxBytes         *gtk.Entry
+	combSendEncoding      *gtk.ComboBoxText
+	labelRTT              *gtk.Label
+	btnRTTHistogram       *gtk.Button
 	cbDataSourceCycleSend *gtk.CheckButton
 	cbSendByHex           *gtk.CheckButton
 	tbReceData            *gtk.TextBuffer
 	tbSendData            *gtk.TextBuffer
 	entryCycleTime        *gtk.Entry
+	combRateUnit          *gtk.ComboBoxText
+	entryCycleCount       *gtk.Entry
+	entryCycleDuration    *gtk.Entry
+	labelCycleRate        *gtk.Label
 	cbAutoCleanAfterSend  *gtk.CheckButton
 	cbReceive2File        *gtk.CheckButton
+	combCaptureFormat     *gtk.ComboBoxText
+	entryRotateSize       *gtk.Entry
+	entryRotateAge        *gtk.Entry
+	entryRotateKeep       *gtk.Entry
+	cbRotateDated         *gtk.CheckButton
+	cbRotateGzip          *gtk.CheckButton
 	btnSaveData           *gtk.Button
+	cbSaveRaw             *gtk.CheckButton
+	btnExportPcap         *gtk.Button
 	btnLoadData           *gtk.Button
 	labelLocalAddr        *gtk.Label
 	labelLocalPort        *gtk.Label
 	cbAppendNewLine       *gtk.CheckButton
+	combServerBehaviour   *gtk.ComboBoxText
+
+	isServer        bool
+	serverBehaviour int32 // set from the GUI, read by the connection goroutines through behaviour()
+	rtt             rttStats
+
+	recvQueue   recvQueue
+	records     []*recvRecord
+	recordBytes int
+
+	entrySearch    *gtk.Entry
+	combSearchMode *gtk.ComboBoxText
+	cbSearchFilter *gtk.CheckButton
+	labelSearch    *gtk.Label
+	searcher       *matcher
+	searchMatches  []searchMatch
+	searchIndex    int
+	highlightText  string
+	highlightRules []highlightRule
+	recvDecoders   map[string]*streamDecoder
+	recordSeq      int
+	selFrom        int
+	selTo          int
+
+	seqRunner     *seqRunner
+	tbSeqScript   *gtk.TextBuffer
+	cbSeqHex      *gtk.CheckButton
+	cbSeqLoop     *gtk.CheckButton
+	pbSeqProgress *gtk.ProgressBar
+	btnSeqRun     *gtk.Button
+	btnSeqPause   *gtk.Button
+	btnSeqStop    *gtk.Button
+
+	replayRunner     *replayRunner
+	replayStreams    []*replayStream
+	labelReplayFile  *gtk.Label
+	combReplayStream *gtk.ComboBoxText
+	combReplayTiming *gtk.ComboBoxText
+	entryReplaySpeed *gtk.Entry
+	pbReplayProgress *gtk.ProgressBar
+	btnReplayRun     *gtk.Button
+	btnReplayPause   *gtk.Button
+	btnReplayStop    *gtk.Button
+
+	modbusMaster    *modbusMaster
+	modbusSlave     *modbusSlave
+	combMbFraming   *gtk.ComboBoxText
+	entryMbSilence  *gtk.Entry
+	combMbFunction  *gtk.ComboBoxText
+	entryMbUnit     *gtk.Entry
+	entryMbAddress  *gtk.Entry
+	entryMbQuantity *gtk.Entry
+	entryMbValues   *gtk.Entry
+	tbMbResult      *gtk.TextBuffer
+	tbMbRegisters   *gtk.TextBuffer
+
+	mqttClient           *mqttClient
+	combMqttVersion      *gtk.ComboBoxText
+	combMqttTransport    *gtk.ComboBoxText
+	cbMqttInsecure       *gtk.CheckButton
+	entryMqttPath        *gtk.Entry
+	entryMqttClientID    *gtk.Entry
+	entryMqttUser        *gtk.Entry
+	entryMqttPassword    *gtk.Entry
+	entryMqttKeepalive   *gtk.Entry
+	cbMqttClean          *gtk.CheckButton
+	entryMqttWillTopic   *gtk.Entry
+	entryMqttWillPayload *gtk.Entry
+	combMqttWillQoS      *gtk.ComboBoxText
+	cbMqttWillRetain     *gtk.CheckButton
+	entryMqttFilter      *gtk.Entry
+	combMqttFilterQoS    *gtk.ComboBoxText
+	entryMqttTopic       *gtk.Entry
+	combMqttQoS          *gtk.ComboBoxText
+	cbMqttRetain         *gtk.CheckButton
+
+	wsAutoPong         int32 // set from the GUI, read by the connection goroutines
+	entryWsPath        *gtk.Entry
+	cbWsTLS            *gtk.CheckButton
+	cbWsInsecure       *gtk.CheckButton
+	entryWsProtocols   *gtk.Entry
+	tbWsHeaders        *gtk.TextBuffer
+	combWsFrameType    *gtk.ComboBoxText
+	entryWsPing        *gtk.Entry
+	entryWsCloseCode   *gtk.Entry
+	entryWsCloseReason *gtk.Entry
+
+	httpInspector    *httpInspector
+	cbTcpTLS         *gtk.CheckButton
+	cbTcpInsecure    *gtk.CheckButton
+	combHttpMethod   *gtk.ComboBoxText
+	entryHttpPath    *gtk.Entry
+	tbHttpHeaders    *gtk.TextBuffer
+	tbHttpBody       *gtk.TextBuffer
+	cbHttpAutoHost   *gtk.CheckButton
+	cbHttpAutoLength *gtk.CheckButton
+	tbHttpResponse   *gtk.TextBuffer
+	httpMock         *httpMock
+	tbHttpRoutes     *gtk.TextBuffer
+
+	cbTelnet          *gtk.CheckButton
+	entryTelnetWill   *gtk.Entry
+	entryTelnetDo     *gtk.Entry
+	entryTelnetTerm   *gtk.Entry
+	entryTelnetWidth  *gtk.Entry
+	entryTelnetHeight *gtk.Entry
+	tbTelnetLog       *gtk.TextBuffer
+
+	combProxyType      *gtk.ComboBoxText
+	entryProxyAddr     *gtk.Entry
+	entryProxyUser     *gtk.Entry
+	entryProxyPassword *gtk.Entry
+	socksServer        *socksServer
+
+	bench              *benchmark
+	benchResult        benchResult
+	combBenchMode      *gtk.ComboBoxText
+	combBenchPattern   *gtk.ComboBoxText
+	entryBenchSize     *gtk.Entry
+	entryBenchRate     *gtk.Entry
+	combBenchRateUnit  *gtk.ComboBoxText
+	entryBenchDuration *gtk.Entry
+	btnBenchStart      *gtk.Button
+	btnBenchExport     *gtk.Button
+	labelBenchResult   *gtk.Label
 }
 
 // NetAssistantAppNew create new instance
 func NetAssistantAppNew() *NetAssistantApp {
 	obj := &NetAssistantApp{}
 	obj.chanClose = make(chan bool)
+	obj.modbusMaster = newModbusMaster()
+	obj.modbusSlave = newModbusSlave()
+	obj.httpInspector = newHTTPInspector()
+	obj.httpMock = newHTTPMock()
 	return obj
 }
 
-func appendConntent2File(filename string, content []byte) {
-	fd, _ := os.OpenFile(filename, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
-	defer fd.Close()
-	fd.Write(content)
-}
-
 func (app *NetAssistantApp) getRecvData() string {
 	buff, err := app.tvDataReceive.GetBuffer()
 	if err != nil {
@@ -179,30 +668,9 @@ func (app *NetAssistantApp) getRecvData() string {
 	return data
 }
 
-func (app *NetAssistantApp) update(recvStr string) {
-	list := []string{}
-	if app.cbHexDisplay.GetActive() {
-		for i := 0; i < len(recvStr); i++ {
-			log.Debug(i, recvStr[i])
-			list = append(list, fmt.Sprintf("%X", recvStr[i]))
-		}
-		recvStr = strings.Join(list, " ")
-	}
-
-	if app.cbDisplayDate.GetActive() {
-		recvStr = fmt.Sprintf("[%s]%s\n", time.Now().Format(time.DateTime+".000000"), recvStr)
-	}
-
-	if app.cbReceive2File.GetActive() {
-		appendConntent2File(app.fileName, []byte(recvStr))
-	}
-
-	iter := app.tbReceData.GetEndIter()
-	app.tbReceData.Insert(iter, recvStr)
-	app.labelReceveCount.SetText(getI18nText(IT_RECEVER_COUNT) + strconv.Itoa(app.receCount))
-	app.tbReceData.CreateMark(getI18nText(IT_END), iter, false)
-	mark := app.tbReceData.GetMark(getI18nText(IT_END))
-	app.tvDataReceive.ScrollMarkOnscreen(mark)
+// appendRecvLog queues a line of tool output for the receive area
+func (app *NetAssistantApp) appendRecvLog(msg string) {
+	app.recvQueue.push(&recvRecord{dir: dirLog, data: []byte(msg), time: time.Now()})
 }
 
 func (app *NetAssistantApp) updateSendCount(count int) {
@@ -210,12 +678,31 @@ func (app *NetAssistantApp) updateSendCount(count int) {
 	app.labelSendCount.SetText(getI18nText(IT_SEND_COUNT) + strconv.Itoa(app.sendCount))
 }
 
+// countSent adds n to the send count from any goroutine, updates are merged until the gui catches up
+func (app *NetAssistantApp) countSent(n int) {
+	if n > 0 && atomic.AddInt64(&app.pendingSent, int64(n)) == int64(n) {
+		glib.IdleAdd(func() {
+			app.updateSendCount(int(atomic.SwapInt64(&app.pendingSent, 0)))
+		})
+	}
+}
+
 func (app *NetAssistantApp) handler(conn net.Conn) {
 	defer conn.Close() // close connection
+	// an unconnected UDP socket is read with ReadFromUDP to learn the peer address
+	udpConn, isUDPServer := conn.(*net.UDPConn)
+	isUDPServer = isUDPServer && udpConn.RemoteAddr() == nil
+	reader := bufio.NewReader(conn)
+	buf := make([]byte, 65536)
 	for {
-		reader := bufio.NewReader(conn)
-		var buf [2048]byte
-		n, err := reader.Read(buf[:])
+		var n int
+		var err error
+		var addr *net.UDPAddr
+		if isUDPServer {
+			n, addr, err = udpConn.ReadFromUDP(buf)
+		} else {
+			n, err = reader.Read(buf)
+		}
 		if err != nil {
 			log.Info("connection closed:", err)
 			_, ok := conn.(*net.UDPConn)
@@ -226,6 +713,9 @@ func (app *NetAssistantApp) handler(conn net.Conn) {
 					app.labelStatus.SetMarkup(tips)
 				})
 			}
+			app.modbusSlave.forget(conn)
+			app.httpInspector.closed(conn)
+			app.httpMock.forget(conn)
 			for index, connItem := range app.connList {
 				if conn.LocalAddr().String() == connItem.LocalAddr().String() {
 					app.connList = append(app.connList[:index], app.connList[index+1:]...)
@@ -233,56 +723,54 @@ func (app *NetAssistantApp) handler(conn net.Conn) {
 			}
 			return
 		}
-		app.receCount += n
-		recvStr := string(buf[:n])
-		if !app.cbPauseDisplay.GetActive() {
-			glib.IdleAdd(func() {
-				list := []string{}
-				if app.cbHexDisplay.GetActive() {
-					for i := 0; i < len(recvStr); i++ {
-						log.Debug(i, recvStr[i])
-						list = append(list, fmt.Sprintf("%02X", recvStr[i]))
-					}
-					recvStr = strings.Join(list, " ")
-				}
-
-				if app.cbDisplayDate.GetActive() {
-					recvStr = fmt.Sprintf("[%s]%s\n", time.Now().Format(time.DateTime+".000000"), recvStr)
-				}
-
-				if app.cbReceive2File.GetActive() {
-					appendConntent2File(app.fileName, []byte(recvStr))
-				}
-
-				iter := app.tbReceData.GetEndIter()
-				app.tbReceData.Insert(iter, recvStr)
-				app.labelReceveCount.SetText(getI18nText(IT_RECEVER_COUNT) + strconv.Itoa(app.receCount))
-				app.tbReceData.CreateMark(getI18nText(IT_END), iter, false)
-				mark := app.tbReceData.GetMark(getI18nText(IT_END))
-				app.tvDataReceive.ScrollMarkOnscreen(mark)
-			}) //Make sure is running on the gui thread.
+		if bench := app.bench; bench != nil {
+			atomic.AddInt64(&app.receCount, int64(n))
+			bench.onRecv(conn, buf[:n], addr)
+			continue
 		}
+		app.receive(conn, addr, buf[:n], "")
+	}
+}
+
+// receive processes data read from a connection, tag describes the message for message based protocols
+func (app *NetAssistantApp) receive(conn net.Conn, addr *net.UDPAddr, data []byte, tag string) {
+	atomic.AddInt64(&app.receCount, int64(len(data)))
+	app.captureData(dirRecv, conn, addr, data)
+	now := time.Now()
+	sinceSend, sincePrev := app.rtt.markRecv(now)
+	rec := &recvRecord{
+		dir:       dirRecv,
+		data:      append([]byte(nil), data...),
+		time:      now,
+		tag:       tag,
+		sinceSend: sinceSend,
+		sincePrev: sincePrev,
 	}
+	if addr != nil {
+		rec.peer = addr.String()
+	} else if remote := conn.RemoteAddr(); remote != nil {
+		rec.peer = remote.String()
+	}
+	if runner := app.seqRunner; runner != nil {
+		runner.feed(data)
+	}
+	app.modbusMaster.feed(data)
+	if app.isServer {
+		app.onServerData(conn, data, addr)
+	} else {
+		app.httpInspector.feed(conn, data)
+	}
+	app.recvQueue.push(rec) // shown by the periodic flushRecv on the gui thread
 }
 
 func (app *NetAssistantApp) onBtnCleanCount() {
-	app.receCount = 0
+	atomic.StoreInt64(&app.receCount, 0)
 	app.sendCount = 0
 	app.labelReceveCount.SetText(getI18nText(IT_RECEVER_COUNT))
 	app.labelSendCount.SetText(getI18nText(IT_SEND_COUNT))
 	app.labelStatus.SetText("")
-}
-
-func (app *NetAssistantApp) onCbReceive2File() {
-	if app.cbReceive2File.GetActive() {
-		dialog, _ := gtk.FileChooserNativeDialogNew("Select File", app.appWindow, gtk.FILE_CHOOSER_ACTION_OPEN, "Select", "Cancel")
-		res := dialog.Run()
-		if res == int(gtk.RESPONSE_ACCEPT) {
-			fileName := dialog.FileChooser.GetFilename()
-			app.fileName = fileName
-		}
-		dialog.Destroy()
-	}
+	app.rtt.reset()
+	app.labelRTT.SetText("")
 }
 
 func (app *NetAssistantApp) onBtnLoadData() {
@@ -303,10 +791,20 @@ func (app *NetAssistantApp) onBtnLoadData() {
 
 func (app *NetAssistantApp) onBtnSaveData() {
 	dialog, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_SAVE_TO_FILE), app.appWindow, gtk.FILE_CHOOSER_ACTION_SAVE, "Save", "Cancel")
+	raw := app.cbSaveRaw.GetActive()
+	if raw {
+		dialog.FileChooser.SetCurrentName("recv.bin")
+	} else {
+		dialog.FileChooser.SetCurrentName("recv.txt")
+	}
+	dialog.FileChooser.SetDoOverwriteConfirmation(true)
 	res := dialog.Run()
 	if res == int(gtk.RESPONSE_ACCEPT) {
 		fileName := dialog.FileChooser.GetFilename()
-		appendConntent2File(fileName, []byte(app.getRecvData()))
+		if err := app.saveRecvData(fileName, raw); err != nil {
+			log.Error(err)
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		}
 	}
 	dialog.Destroy()
 }
@@ -325,10 +823,70 @@ func (app *NetAssistantApp) updateAllStatus(msg, curIP, curPort string) {
 	app.entryCurPort.SetText(curPort)
 }
 
+// udpTargetAddr resolves the peer entered in UDP server mode, nil for other modes
+func (app *NetAssistantApp) udpTargetAddr() (*net.UDPAddr, error) {
+	if app.combProtoType.GetActive() != 3 {
+		return nil, nil
+	}
+	strIP, _ := app.entryCurAddr.GetText()
+	strPort, _ := app.entryCurPort.GetText()
+	return net.ResolveUDPAddr("udp4", strIP+":"+strPort)
+}
+
+// writeData sends data to all connections and returns the number of bytes written
+func (app *NetAssistantApp) writeData(data []byte, udpTarget *net.UDPAddr) (int, error) {
+	if len(app.connList) == 0 {
+		return 0, errors.New(getI18nText(IT_NO_CONN))
+	}
+	count := 0
+	var lastErr error
+	prevSend := app.rtt.markSend()
+	for _, conn := range app.connList {
+		var n int
+		var err error
+		if udpConnection, ok := conn.(*net.UDPConn); ok && udpTarget != nil {
+			n, err = udpConnection.WriteToUDP(data, udpTarget)
+		} else {
+			n, err = conn.Write(data)
+		}
+		if err != nil {
+			log.Error(err)
+			lastErr = err
+		}
+		if n > 0 {
+			app.captureData(dirSend, conn, udpTarget, data[:n])
+		}
+		count += n
+	}
+	if count == 0 {
+		app.rtt.cancelSend(prevSend)
+	}
+	return count, lastErr
+}
+
 func (app *NetAssistantApp) createConnect(serverType int, strIP, strPort string) error {
 	addr := strIP + ":" + strPort
+	app.isServer = serverType == 1 || serverType == 3 || serverType == 6
+	// the session of the last connection stays exportable until a new one starts
+	app.session.reset()
 	if serverType == 0 { // TCP Client
-		conn, err := net.Dial("tcp", addr)
+		conn, err := app.dialClient("tcp", addr)
+		if err == nil && app.cbTcpTLS.GetActive() {
+			tlsConn := tls.Client(conn, &tls.Config{ServerName: strIP, InsecureSkipVerify: app.cbTcpInsecure.GetActive()})
+			if err = tlsConn.Handshake(); err == nil {
+				conn = tlsConn
+			} else {
+				conn.Close()
+			}
+		}
+		if err == nil && app.cbTelnet.GetActive() {
+			var telnet net.Conn
+			if telnet, err = app.newTelnetClient(conn); err == nil {
+				conn = telnet
+			} else {
+				conn.Close()
+			}
+		}
 		if err == nil {
 			go app.handler(conn)
 			app.addConnection(conn)
@@ -357,6 +915,9 @@ func (app *NetAssistantApp) createConnect(serverType int, strIP, strPort string)
 					glib.IdleAdd(func() {
 						app.labelStatus.SetMarkup(tips)
 					})
+					if !app.onServerAccept(conn) {
+						continue
+					}
 					app.connList = append(app.connList, conn)
 					go app.handler(conn)
 				}
@@ -371,7 +932,7 @@ func (app *NetAssistantApp) createConnect(serverType int, strIP, strPort string)
 	}
 
 	if serverType == 2 { // UDP Client
-		conn, err := net.Dial("udp4", addr)
+		conn, err := app.dialClient("udp4", addr)
 		if err == nil {
 			go app.handler(conn)
 			app.addConnection(conn)
@@ -409,16 +970,77 @@ func (app *NetAssistantApp) createConnect(serverType int, strIP, strPort string)
 		}
 	}
 
+	if serverType == 4 { // MQTT Client
+		client, err := app.connectMqttClient(addr)
+		if err != nil {
+			app.updateAllStatus(err.Error(), "", "")
+			log.Error(err)
+			return err
+		}
+		app.addConnection(client)
+		app.mqttClient = client
+		localIP, localPort, _ := net.SplitHostPort(client.LocalAddr().String())
+		app.updateAllStatus("MQTT client connection succeeds", localIP, localPort)
+	}
+
+	if serverType == 5 { // WebSocket Client
+		ws, err := app.dialWebSocket(addr)
+		if err != nil {
+			app.updateAllStatus(err.Error(), "", "")
+			log.Error(err)
+			return err
+		}
+		go app.wsHandler(ws)
+		app.addConnection(ws)
+		localIP, localPort, _ := net.SplitHostPort(ws.LocalAddr().String())
+		app.updateAllStatus("WebSocket client connection succeeds", localIP, localPort)
+	}
+
+	if serverType == 6 { // WebSocket Server
+		settings, err := app.wsSettings()
+		if err != nil {
+			app.updateStatus(err.Error())
+			log.Error(err)
+			return err
+		}
+		listen, err := net.Listen("tcp", addr)
+		if err != nil {
+			app.updateStatus(err.Error())
+			log.Error(err)
+			return err
+		}
+		go app.serveWebSocket(listen, settings)
+		app.updateAllStatus("WebSocket server connection succeeds", strIP, strPort)
+		app.listener = listen
+	}
+
+	if serverType == 7 { // SOCKS5 Proxy Server
+		listen, err := net.Listen("tcp", addr)
+		if err != nil {
+			app.updateStatus(err.Error())
+			log.Error(err)
+			return err
+		}
+		app.socksServer = newSocksServer(listen)
+		go app.serveSocks(app.socksServer)
+		app.updateAllStatus("SOCKS5 proxy server connection succeeds", strIP, strPort)
+	}
+
 	return nil
 }
 
 func (app *NetAssistantApp) disconnect(serverType int) error {
-	if serverType == 1 {
+	if serverType == 1 || serverType == 6 {
 		if app.listener != nil {
 			app.listener.Close()
 		}
 	}
 
+	if serverType == 7 && app.socksServer != nil {
+		app.socksServer.close()
+		app.socksServer = nil
+	}
+
 	for _, conn := range app.connList {
 		conn.Close()
 	}
@@ -434,6 +1056,8 @@ func (app *NetAssistantApp) disconnect(serverType int) error {
 
 	app.updateStatus(getI18nText(IT_WAIT_CONN))
 	app.connList = []net.Conn{}
+	app.mqttClient = nil
+	app.httpInspector.reset()
 	return nil
 }
 
@@ -463,6 +1087,13 @@ func (app *NetAssistantApp) onBtnSend() {
 		log.Error(err)
 	}
 
+	label, err := app.btnSend.GetLabel()
+	if label != getI18nText(IT_SEND) {
+		close(app.chanClose)
+		app.btnSend.SetLabel(getI18nText(IT_SEND))
+		return
+	}
+
 	start, end := buff.GetBounds()
 	data, _ := buff.GetText(start, end, true)
 
@@ -470,7 +1101,12 @@ func (app *NetAssistantApp) onBtnSend() {
 		data += "\r\n"
 	}
 
-	sendData := []byte(data)
+	sendData, err := encodeText(app.combSendEncoding.GetActive(), data)
+	if err != nil && !app.cbSendByHex.GetActive() {
+		log.Error(err)
+		app.labelStatus.SetMarkup(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
 
 	if app.cbSendByHex.GetActive() {
 		data = strings.Replace(data, " ", "", -1)
@@ -486,70 +1122,28 @@ func (app *NetAssistantApp) onBtnSend() {
 		log.Info(hexData)
 	}
 
-	label, err := app.btnSend.GetLabel()
-	if label != getI18nText(IT_SEND) {
-		app.chanClose <- true
-		app.btnSend.SetLabel(getI18nText(IT_SEND))
-		return
-	}
-
 	if app.cbDataSourceCycleSend.GetActive() { // loop send
-		app.btnSend.SetLabel(getI18nText(IT_STOP))
-		strCycleTime, err := app.entryCycleTime.GetText()
+		settings, err := app.cycleSettings(len(sendData))
 		if err != nil {
-			strCycleTime = "1000"
+			app.labelStatus.SetMarkup(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+			return
 		}
-		cycle, err := strconv.Atoi(strCycleTime)
+		udpTarget, err := app.udpTargetAddr()
 		if err != nil {
-			cycle = 1000
+			log.Error(err)
+			return
 		}
-		go func(cycleTime int) {
-		END:
-			for {
-				select {
-				case <-app.chanClose: // waiting close
-					break END
-				default:
-					for _, conn := range app.connList { // range current connection
-						if udpConnection, ok := conn.(*net.UDPConn); ok && app.combProtoType.GetActive() == 3 { // if the connection is UDP
-							strIP, _ := app.entryCurAddr.GetText()
-							strPort, _ := app.entryCurPort.GetText()
-							address, err := net.ResolveUDPAddr("udp4", strIP+":"+strPort)
-							if err == nil {
-								udpConnection.WriteToUDP(sendData, address) // send udp message
-							} else {
-								log.Error(err)
-							}
-
-						} else {
-							conn.Write(sendData) // send message
-						}
-					}
-					if len(app.connList) == 0 {
-						glib.IdleAdd(func() {
-							app.labelStatus.SetText(getI18nText(IT_NO_CONN))
-							app.btnSend.SetLabel(getI18nText(IT_SEND))
-						})
-						break END
-					}
-				}
-				time.Sleep(time.Duration(cycleTime) * time.Millisecond)
-			}
-		}(cycle)
+		app.btnSend.SetLabel(getI18nText(IT_STOP))
+		app.chanClose = make(chan bool)
+		go app.cycleSend(sendData, udpTarget, settings, app.chanClose)
 	} else { // once send
-		for _, conn := range app.connList {
-			if cc, ok := conn.(*net.UDPConn); ok && app.combProtoType.GetActive() == 3 {
-				strIP, _ := app.entryCurAddr.GetText()
-				strPort, _ := app.entryCurPort.GetText()
-				address, err := net.ResolveUDPAddr("udp4", strIP+":"+strPort)
-				if err == nil {
-					cc.WriteToUDP(sendData, address)
-				}
-			} else {
-				conn.Write(sendData)
-			}
+		udpTarget, err := app.udpTargetAddr()
+		if err != nil {
+			log.Error(err)
+		} else {
+			n, _ := app.writeData(sendData, udpTarget)
 			log.Info("Write data", data)
-			app.updateSendCount(len(sendData))
+			app.updateSendCount(n)
 		}
 	}
 
@@ -560,6 +1154,7 @@ func (app *NetAssistantApp) onBtnSend() {
 }
 
 func (app *NetAssistantApp) onBtnClearRecvDisplay() {
+	app.clearRecords()
 	app.tbReceData.SetText("")
 }
 
@@ -596,6 +1191,10 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 	app.combProtoType.AppendText(getI18nText(IT_TCP_SERVER))
 	app.combProtoType.AppendText(getI18nText(IT_UDP_CLIENT))
 	app.combProtoType.AppendText(getI18nText(IT_UDP_SERVER))
+	app.combProtoType.AppendText(getI18nText(IT_MQTT_CLIENT))
+	app.combProtoType.AppendText(getI18nText(IT_WS_CLIENT))
+	app.combProtoType.AppendText(getI18nText(IT_WS_SERVER))
+	app.combProtoType.AppendText(getI18nText(IT_SOCKS_SERVER))
 	app.combProtoType.SetActive(0)
 	verticalBox.PackStart(labelProtType, false, false, 0)
 	verticalBox.PackStart(app.combProtoType, false, false, 0)
@@ -611,6 +1210,11 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 	app.entryPort.SetText("50023")
 	verticalBox.PackStart(labelPort, false, false, 0)
 	verticalBox.PackStart(app.entryPort, false, false, 0)
+	labelBehaviour, _ := gtk.LabelNew(getI18nText(IT_SERVER_BEHAVIOUR))
+	labelBehaviour.SetXAlign(0)
+	app.combServerBehaviour = app.createBehaviourCombo()
+	verticalBox.PackStart(labelBehaviour, false, false, 0)
+	verticalBox.PackStart(app.combServerBehaviour, false, false, 0)
 	app.btnConnect, _ = gtk.ButtonNewWithLabel(getI18nText(IT_CONNECT))
 	app.btnConnect.Connect("clicked", app.onBtnConnect)
 	verticalBox.PackStart(app.btnConnect, false, false, 0)
@@ -619,26 +1223,84 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 	notebookTab, _ := gtk.NotebookNew()
 	label1, _ := gtk.LabelNew(getI18nText(IT_RECV_SETTINGS))
 	label2, _ := gtk.LabelNew(getI18nText(IT_SEND_SETTINGS))
+	label3, _ := gtk.LabelNew(getI18nText(IT_SEQUENCE))
+	label4, _ := gtk.LabelNew(getI18nText(IT_BENCHMARK))
+	label5, _ := gtk.LabelNew(getI18nText(IT_CAPTURE))
+	label6, _ := gtk.LabelNew(getI18nText(IT_REPLAY))
+	label7, _ := gtk.LabelNew(getI18nText(IT_MODBUS))
+	label8, _ := gtk.LabelNew(getI18nText(IT_MQTT))
+	label9, _ := gtk.LabelNew(getI18nText(IT_WEBSOCKET))
+	label10, _ := gtk.LabelNew(getI18nText(IT_HTTP))
+	label11, _ := gtk.LabelNew(getI18nText(IT_TELNET))
+	label12, _ := gtk.LabelNew(getI18nText(IT_PROXY))
 
 	//  Recv Settings
 	frame1ContentBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
 	app.cbReceive2File, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SAVE_TO_FILE))
 	app.cbReceive2File.Connect("toggled", app.onCbReceive2File)
+	app.combCaptureFormat, _ = gtk.ComboBoxTextNew()
+	for _, key := range []string{IT_CAPTURE_RAW, IT_CAPTURE_HEX, IT_CAPTURE_LOG} {
+		app.combCaptureFormat.AppendText(getI18nText(key))
+	}
+	app.combCaptureFormat.SetActive(captureLog)
+	captureHboxContainer, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	captureHboxContainer.PackStart(app.cbReceive2File, false, false, 0)
+	captureHboxContainer.PackStart(app.combCaptureFormat, false, false, 0)
 	app.cbDisplayDate, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SHOW_RECV_TIME))
 	app.cbHexDisplay, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SHOW_HEX))
+	app.cbShowPeer, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SHOW_PEER))
+	app.combRecvEncoding = createEncodingCombo()
+	app.combRecvEncoding.Connect("changed", app.renderRecords)
+	labelRecvEncoding, _ := gtk.LabelNew(getI18nText(IT_ENCODING))
+	recvEncodingHboxContainer, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	recvEncodingHboxContainer.PackStart(labelRecvEncoding, false, false, 0)
+	recvEncodingHboxContainer.PackStart(app.combRecvEncoding, false, false, 0)
+	app.cbHexDump, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_HEX_DUMP))
+	app.cbHexDump.Connect("toggled", app.onCbHexDump)
+	app.combDumpWidth, _ = gtk.ComboBoxTextNew()
+	for _, width := range dumpWidths {
+		app.combDumpWidth.AppendText(strconv.Itoa(width))
+	}
+	app.combDumpWidth.SetActive(1)
+	app.combDumpWidth.Connect("changed", app.onCombDumpWidthChanged)
+	labelDumpWidth, _ := gtk.LabelNew(getI18nText(IT_BYTES_PER_ROW))
+	dumpHboxContainer, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	dumpHboxContainer.PackStart(labelDumpWidth, false, false, 0)
+	dumpHboxContainer.PackStart(app.combDumpWidth, false, false, 0)
+	app.btnToggleDump, _ = gtk.ButtonNewWithLabel(getI18nText(IT_TOGGLE_VIEW))
+	app.btnToggleDump.Connect("clicked", app.onBtnToggleDump)
+	app.cbShowRTT, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SHOW_RTT))
 	app.cbPauseDisplay, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_PAUSE))
+	app.entryMaxLines, _ = gtk.EntryNew()
+	app.entryMaxLines.SetPlaceholderText(getI18nText(IT_MAX_LINES))
+	app.entryMaxBytes, _ = gtk.EntryNew()
+	app.entryMaxBytes.SetPlaceholderText(getI18nText(IT_MAX_BYTES))
+	app.cbSaveRaw, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SAVE_RAW))
 	btnHboxContainer, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
 	app.btnSaveData, _ = gtk.ButtonNewWithLabel(getI18nText(IT_SAVE))
 	app.btnSaveData.Connect("clicked", app.onBtnSaveData)
+	app.btnExportPcap, _ = gtk.ButtonNewWithLabel(getI18nText(IT_EXPORT_PCAP))
+	app.btnExportPcap.Connect("clicked", app.onBtnExportPcap)
 	app.btnClearRecvDisplay, _ = gtk.ButtonNewWithLabel(getI18nText(IT_CLEAR))
 	app.btnClearRecvDisplay.Connect("clicked", app.onBtnClearRecvDisplay)
 
 	btnHboxContainer.PackStart(app.btnSaveData, true, false, 0)
+	btnHboxContainer.PackStart(app.btnExportPcap, true, false, 0)
 	btnHboxContainer.PackStart(app.btnClearRecvDisplay, true, false, 0)
-	frame1ContentBox.PackStart(app.cbReceive2File, false, false, 0)
+	frame1ContentBox.PackStart(captureHboxContainer, false, false, 0)
 	frame1ContentBox.PackStart(app.cbDisplayDate, false, false, 0)
+	frame1ContentBox.PackStart(app.cbShowPeer, false, false, 0)
 	frame1ContentBox.PackStart(app.cbHexDisplay, false, false, 0)
+	frame1ContentBox.PackStart(recvEncodingHboxContainer, false, false, 0)
+	frame1ContentBox.PackStart(app.createDecoderBox(), false, false, 0)
+	frame1ContentBox.PackStart(app.cbHexDump, false, false, 0)
+	frame1ContentBox.PackStart(dumpHboxContainer, false, false, 0)
+	frame1ContentBox.PackStart(app.btnToggleDump, false, false, 0)
+	frame1ContentBox.PackStart(app.cbShowRTT, false, false, 0)
 	frame1ContentBox.PackStart(app.cbPauseDisplay, false, false, 0)
+	frame1ContentBox.PackStart(app.entryMaxLines, false, false, 0)
+	frame1ContentBox.PackStart(app.entryMaxBytes, false, false, 0)
+	frame1ContentBox.PackStart(app.cbSaveRaw, false, false, 0)
 	frame1ContentBox.PackStart(btnHboxContainer, false, false, 0)
 	frame1ContentBox.SetBorderWidth(10)
 
@@ -648,8 +1310,29 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 	app.cbAutoCleanAfterSend, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_AUTO_CLEAR))
 	app.cbSendByHex, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SEND_HEX))
 	app.cbDataSourceCycleSend, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_SEND_CIRC))
+	app.combSendEncoding = createEncodingCombo()
+	labelSendEncoding, _ := gtk.LabelNew(getI18nText(IT_ENCODING))
+	sendEncodingHboxContainer, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	sendEncodingHboxContainer.PackStart(labelSendEncoding, false, false, 0)
+	sendEncodingHboxContainer.PackStart(app.combSendEncoding, false, false, 0)
 	app.entryCycleTime, _ = gtk.EntryNew()
 	app.entryCycleTime.SetPlaceholderText("default 1000(ms)")
+	app.entryCycleTime.SetWidthChars(10)
+	app.combRateUnit, _ = gtk.ComboBoxTextNew()
+	app.combRateUnit.AppendText(getI18nText(IT_INTERVAL_MS))
+	app.combRateUnit.AppendText(getI18nText(IT_MSG_PER_SEC))
+	app.combRateUnit.AppendText(getI18nText(IT_BYTES_PER_SEC))
+	app.combRateUnit.SetActive(rateUnitInterval)
+	rateHboxContainer, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	rateHboxContainer.PackStart(app.entryCycleTime, true, true, 0)
+	rateHboxContainer.PackStart(app.combRateUnit, false, false, 0)
+	app.entryCycleCount, _ = gtk.EntryNew()
+	app.entryCycleCount.SetPlaceholderText(getI18nText(IT_MAX_COUNT))
+	app.entryCycleDuration, _ = gtk.EntryNew()
+	app.entryCycleDuration.SetPlaceholderText(getI18nText(IT_MAX_DURATION))
+	app.labelCycleRate, _ = gtk.LabelNew("")
+	app.labelCycleRate.SetLineWrap(true)
+	app.labelCycleRate.SetMaxWidthChars(30)
 	btnHboxContainer2, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
 	app.btnLoadData, _ = gtk.ButtonNewWithLabel(getI18nText(IT_LOAD_DATA))
 	app.btnClearSendDisplay, _ = gtk.ButtonNewWithLabel(getI18nText(IT_CLEAR))
@@ -662,9 +1345,16 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 	frame2ContentBox.PackStart(app.cbAppendNewLine, false, false, 0)
 	frame2ContentBox.PackStart(app.cbAutoCleanAfterSend, false, false, 0)
 	frame2ContentBox.PackStart(app.cbSendByHex, false, false, 0)
+	frame2ContentBox.PackStart(sendEncodingHboxContainer, false, false, 0)
 	frame2ContentBox.PackStart(app.cbDataSourceCycleSend, false, false, 0)
-	frame2ContentBox.PackStart(app.entryCycleTime, false, false, 0)
+	frame2ContentBox.PackStart(rateHboxContainer, false, false, 0)
+	frame2ContentBox.PackStart(app.entryCycleCount, false, false, 0)
+	frame2ContentBox.PackStart(app.entryCycleDuration, false, false, 0)
+	frame2ContentBox.PackStart(app.labelCycleRate, false, false, 0)
+	btnTemplateForm, _ := gtk.ButtonNewWithLabel(getI18nText(IT_TEMPLATE_FORM))
+	btnTemplateForm.Connect("clicked", app.onBtnTemplateForm)
 	btnHboxContainer2.PackStart(app.btnLoadData, true, false, 0)
+	btnHboxContainer2.PackStart(btnTemplateForm, true, false, 0)
 	btnHboxContainer2.PackStart(app.btnClearSendDisplay, true, false, 0)
 	frame2ContentBox.PackStart(btnHboxContainer2, false, false, 0)
 	frame2ContentBox.SetBorderWidth(10)
@@ -676,11 +1366,23 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 
 	notebookTab.AppendPage(frame1, label1)
 	notebookTab.AppendPage(frame2, label2)
+	notebookTab.AppendPage(app.createSequencePage(), label3)
+	notebookTab.AppendPage(app.createBenchPage(), label4)
+	notebookTab.AppendPage(app.createCapturePage(), label5)
+	notebookTab.AppendPage(app.createReplayPage(), label6)
+	notebookTab.AppendPage(app.createModbusPage(), label7)
+	notebookTab.AppendPage(app.createMqttPage(), label8)
+	notebookTab.AppendPage(app.createWebSocketPage(), label9)
+	notebookTab.AppendPage(app.createHttpPage(), label10)
+	notebookTab.AppendPage(app.createTelnetPage(), label11)
+	notebookTab.AppendPage(app.createProxyPage(), label12)
+	notebookTab.SetScrollable(true)
 
 	// Data Received
 	titleDataReceiveArea, _ := gtk.LabelNew(getI18nText(IT_DATA_RECVED))
 	titleDataReceiveArea.SetXAlign(0)
 	windowContainerRight.PackStart(titleDataReceiveArea, false, false, 0)
+	windowContainerRight.PackStart(app.createSearchBar(), false, false, 0)
 	app.swDataRec, _ = gtk.ScrolledWindowNew(nil, nil)
 	app.tvDataReceive, _ = gtk.TextViewNew()
 	app.tvDataReceive.SetEditable(false)
@@ -726,6 +1428,11 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 	windowContainerBottom.PackStart(app.labelSendCount, true, false, 0)
 	app.labelReceveCount, _ = gtk.LabelNew(getI18nText(IT_RECEVER_COUNT))
 	windowContainerBottom.PackStart(app.labelReceveCount, true, false, 0)
+	app.labelRTT, _ = gtk.LabelNew("")
+	windowContainerBottom.PackStart(app.labelRTT, true, false, 0)
+	app.btnRTTHistogram, _ = gtk.ButtonNewWithLabel(getI18nText(IT_HISTOGRAM))
+	app.btnRTTHistogram.Connect("clicked", app.onBtnRTTHistogram)
+	windowContainerBottom.PackStart(app.btnRTTHistogram, false, false, 0)
 	app.btnCleanCount, _ = gtk.ButtonNewWithLabel(getI18nText(IT_RESET))
 	app.btnCleanCount.Connect("clicked", app.onBtnCleanCount)
 
@@ -748,6 +1455,12 @@ func (app *NetAssistantApp) doActivate(application *gtk.Application) {
 		app.tbReceData, _ = gtk.TextBufferNew(nil)
 		app.tvDataReceive.SetBuffer(app.tbReceData)
 	}
+	app.createRecvTags()
+	app.searchIndex = -1
+	for _, cb := range []*gtk.CheckButton{app.cbDisplayDate, app.cbShowPeer, app.cbHexDisplay, app.cbShowRTT} {
+		cb.Connect("toggled", app.renderRecords)
+	}
+	glib.TimeoutAdd(recvFlushInterval, app.flushRecv)
 }
 
 func init() {
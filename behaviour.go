@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"net"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// canned server behaviours
+const (
+	behaviourNone    = iota
+	behaviourEcho    // RFC 862
+	behaviourDiscard // RFC 863
+	behaviourChargen // RFC 864
+	behaviourDaytime // RFC 867
+	behaviourTime    // RFC 868
+	behaviourUpper
+	behaviourReverse
+	behaviourHex
+	behaviourModbus
+	behaviourHTTP
+)
+
+// seconds between 1900-01-01 and 1970-01-01
+const rfc868Offset = 2208988800
+
+const chargenLineSize = 72
+
+// chargenLine returns the n-th RFC 864 line, a rotating window of the printable ASCII characters
+func chargenLine(n int) []byte {
+	line := make([]byte, 0, chargenLineSize+2)
+	for i := 0; i < chargenLineSize; i++ {
+		line = append(line, byte(' '+(n+i)%95))
+	}
+	return append(line, '\r', '\n')
+}
+
+func daytimeData() []byte {
+	return []byte(time.Now().Format("Monday, January 2, 2006 15:04:05-MST") + "\r\n")
+}
+
+func timeData() []byte {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, uint32(time.Now().Unix()+rfc868Offset))
+	return data
+}
+
+// upperASCII uppercases a-z and leaves every other byte alone, so binary data is not mangled
+func upperASCII(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, c := range data {
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// reverseLines reverses the UTF-8 characters of every line and keeps the line endings in place,
+// a byte that is not valid UTF-8 is moved as one character
+func reverseLines(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		end := len(line)
+		for end > 0 && (line[end-1] == '\n' || line[end-1] == '\r') {
+			end--
+		}
+		for i := end; i > 0; {
+			_, size := utf8.DecodeLastRune(line[:i])
+			out = append(out, line[i-size:i]...)
+			i -= size
+		}
+		out = append(out, line[end:]...)
+	}
+	return out
+}
+
+func hexLine(data []byte) []byte {
+	return []byte(hexString(data) + "\r\n")
+}
+
+// behaviourReply returns the answer of the current behaviour to received data, nil for none
+func behaviourReply(behaviour int, data []byte, isUDP bool) []byte {
+	switch behaviour {
+	case behaviourEcho:
+		return data
+	case behaviourUpper:
+		return upperASCII(data)
+	case behaviourReverse:
+		return reverseLines(data)
+	case behaviourHex:
+		return hexLine(data)
+	}
+	if !isUDP {
+		return nil
+	}
+	// the UDP variants answer every datagram
+	switch behaviour {
+	case behaviourChargen:
+		size := rand.Intn(513)
+		var buf bytes.Buffer
+		for n := rand.Intn(95); buf.Len() < size; n++ {
+			buf.Write(chargenLine(n))
+		}
+		return buf.Bytes()[:size]
+	case behaviourDaytime:
+		return daytimeData()
+	case behaviourTime:
+		return timeData()
+	}
+	return nil
+}
+
+// behaviour returns the server behaviour selected in the GUI
+func (app *NetAssistantApp) behaviour() int {
+	return int(atomic.LoadInt32(&app.serverBehaviour))
+}
+
+// onServerAccept runs the behaviour for a new TCP connection, returns false if the connection is done
+func (app *NetAssistantApp) onServerAccept(conn net.Conn) bool {
+	behaviour := app.behaviour()
+	switch behaviour {
+	case behaviourDaytime, behaviourTime:
+		data := daytimeData()
+		if behaviour == behaviourTime {
+			data = timeData()
+		}
+		n, _ := conn.Write(data)
+		app.captureData(dirSend, conn, nil, data[:n])
+		app.countSent(n)
+		conn.Close()
+		return false
+	case behaviourChargen:
+		go func() {
+			var buf bytes.Buffer
+			for line := 0; app.behaviour() == behaviourChargen; {
+				buf.Reset()
+				for i := 0; i < 95; i++ {
+					buf.Write(chargenLine(line))
+					line++
+				}
+				n, err := conn.Write(buf.Bytes())
+				app.captureData(dirSend, conn, nil, buf.Bytes()[:n])
+				app.countSent(n)
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+	return true
+}
+
+// onServerData answers data received by a server connection
+func (app *NetAssistantApp) onServerData(conn net.Conn, data []byte, addr *net.UDPAddr) {
+	_, isUDP := conn.(*net.UDPConn)
+	behaviour := app.behaviour()
+	if behaviour == behaviourModbus {
+		app.modbusSlave.serve(conn, data, isUDP, func(reply []byte) {
+			app.serverWrite(conn, addr, reply)
+		})
+		return
+	}
+	if behaviour == behaviourHTTP {
+		if !isUDP && !app.httpMock.serve(conn, data, func(reply []byte) {
+			app.serverWrite(conn, addr, reply)
+		}) {
+			conn.Close()
+		}
+		return
+	}
+	reply := behaviourReply(behaviour, data, isUDP)
+	if reply == nil {
+		return
+	}
+	app.serverWrite(conn, addr, reply)
+}
+
+// serverWrite sends a reply to the peer of a server connection
+func (app *NetAssistantApp) serverWrite(conn net.Conn, addr *net.UDPAddr, reply []byte) {
+	udpConn, isUDP := conn.(*net.UDPConn)
+	var n int
+	var err error
+	if isUDP && addr != nil {
+		n, err = udpConn.WriteToUDP(reply, addr)
+	} else {
+		n, err = conn.Write(reply)
+	}
+	if err != nil {
+		log.Error(err)
+	}
+	app.captureData(dirSend, conn, addr, reply[:n])
+	app.countSent(n)
+}
+
+func (app *NetAssistantApp) createBehaviourCombo() *gtk.ComboBoxText {
+	comb, _ := gtk.ComboBoxTextNew()
+	for _, key := range []string{IT_BEHAVIOUR_NONE, IT_BEHAVIOUR_ECHO, IT_BEHAVIOUR_DISCARD, IT_BEHAVIOUR_CHARGEN,
+		IT_BEHAVIOUR_DAYTIME, IT_BEHAVIOUR_TIME, IT_BEHAVIOUR_UPPER, IT_BEHAVIOUR_REVERSE, IT_BEHAVIOUR_HEX, IT_BEHAVIOUR_MODBUS, IT_BEHAVIOUR_HTTP} {
+		comb.AppendText(getI18nText(key))
+	}
+	comb.SetActive(behaviourNone)
+	comb.Connect("changed", func() {
+		atomic.StoreInt32(&app.serverBehaviour, int32(comb.GetActive()))
+	})
+	return comb
+}
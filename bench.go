@@ -0,0 +1,565 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// benchmark packet: magic, sequence number, send time in unix nanoseconds, total length, pattern
+const (
+	benchMagic      = "NABM"
+	benchHeaderSize = 18
+	maxRTTSamples   = 1000000
+	maxSeqWindow    = 1 << 26
+)
+
+const (
+	benchModeClient = iota
+	benchModeEcho
+	benchModeSink
+)
+
+const (
+	patternFixed = iota
+	patternRandom
+	patternIncrement
+)
+
+// benchResult is the summary of a benchmark run, durations are in milliseconds
+type benchResult struct {
+	Time        time.Time `json:"time"`
+	Mode        string    `json:"mode"`
+	Duration    float64   `json:"duration_s"`
+	SentPackets int64     `json:"sent_packets"`
+	SentBytes   int64     `json:"sent_bytes"`
+	RecvPackets int64     `json:"recv_packets"`
+	RecvBytes   int64     `json:"recv_bytes"`
+	SendRate    float64   `json:"send_bytes_per_s"`
+	RecvRate    float64   `json:"recv_bytes_per_s"`
+	RTTMin      float64   `json:"rtt_min_ms"`
+	RTTAvg      float64   `json:"rtt_avg_ms"`
+	RTTMax      float64   `json:"rtt_max_ms"`
+	RTTP50      float64   `json:"rtt_p50_ms"`
+	RTTP90      float64   `json:"rtt_p90_ms"`
+	RTTP99      float64   `json:"rtt_p99_ms"`
+	Jitter      float64   `json:"jitter_ms"`
+	Lost        int64     `json:"lost"`
+	LossPercent float64   `json:"loss_percent"`
+	Reordered   int64     `json:"reordered"`
+	Duplicates  int64     `json:"duplicates"`
+	RTTSamples  []float64 `json:"rtt_samples_ms,omitempty"`
+}
+
+type benchmark struct {
+	mode int
+
+	mu          sync.Mutex
+	start       time.Time
+	end         time.Time
+	sentPackets int64
+	sentBytes   int64
+	recvPackets int64
+	recvBytes   int64
+	seen        []uint64 // bitset of received sequence numbers counted from seqBase
+	seqBase     int64
+	maxSeq      int64
+	reordered   int64
+	duplicates  int64
+	rtts        []time.Duration
+	rttCount    int64
+	rttSum      time.Duration
+	rttMin      time.Duration
+	rttMax      time.Duration
+	lastRTT     time.Duration
+	jitterSum   time.Duration
+	streams     map[net.Conn][]byte
+	chanStop    chan bool
+	stopOnce    sync.Once
+}
+
+func newBenchmark(mode int) *benchmark {
+	return &benchmark{
+		mode:     mode,
+		start:    time.Now(),
+		seqBase:  -1,
+		maxSeq:   -1,
+		streams:  map[net.Conn][]byte{},
+		chanStop: make(chan bool),
+	}
+}
+
+func (b *benchmark) stop() {
+	b.stopOnce.Do(func() {
+		b.mu.Lock()
+		if b.end.IsZero() {
+			b.end = time.Now()
+		}
+		b.mu.Unlock()
+		close(b.chanStop)
+	})
+}
+
+// buildBenchPacket fills a packet of size bytes for the given sequence number
+func buildBenchPacket(packet []byte, seq uint32, pattern int) {
+	copy(packet, benchMagic)
+	binary.BigEndian.PutUint32(packet[4:], seq)
+	binary.BigEndian.PutUint64(packet[8:], uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint16(packet[16:], uint16(len(packet)))
+	payload := packet[benchHeaderSize:]
+	switch pattern {
+	case patternRandom:
+		rand.Read(payload)
+	case patternIncrement:
+		for i := range payload {
+			payload[i] = byte(i)
+		}
+	default:
+		for i := range payload {
+			payload[i] = 0x55
+		}
+	}
+}
+
+func (b *benchmark) onSent(n int) {
+	b.mu.Lock()
+	b.sentPackets++
+	b.sentBytes += int64(n)
+	b.mu.Unlock()
+}
+
+// onRecv processes received data, echoing it back in echo mode
+func (b *benchmark) onRecv(conn net.Conn, data []byte, addr *net.UDPAddr) {
+	var echoed int
+	if b.mode == benchModeEcho {
+		if udpConn, ok := conn.(*net.UDPConn); ok && addr != nil {
+			echoed, _ = udpConn.WriteToUDP(data, addr)
+		} else {
+			echoed, _ = conn.Write(data)
+		}
+	}
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if echoed > 0 {
+		b.sentPackets++
+		b.sentBytes += int64(echoed)
+	}
+	b.recvBytes += int64(len(data))
+	if _, ok := conn.(*net.UDPConn); ok {
+		b.onPacket(data, now)
+		return
+	}
+	buf := append(b.streams[conn], data...)
+	for len(buf) >= benchHeaderSize {
+		if !bytes.HasPrefix(buf, []byte(benchMagic)) {
+			index := bytes.Index(buf[1:], []byte(benchMagic))
+			if index < 0 {
+				buf = buf[len(buf)-len(benchMagic)+1:]
+				break
+			}
+			buf = buf[index+1:]
+			continue
+		}
+		size := int(binary.BigEndian.Uint16(buf[16:]))
+		if size < benchHeaderSize {
+			buf = buf[1:]
+			continue
+		}
+		if len(buf) < size {
+			break
+		}
+		b.onPacket(buf[:size], now)
+		buf = buf[size:]
+	}
+	b.streams[conn] = append([]byte{}, buf...)
+}
+
+// onPacket updates the statistics for one packet, b.mu must be held
+func (b *benchmark) onPacket(packet []byte, now time.Time) {
+	if len(packet) < benchHeaderSize || !bytes.HasPrefix(packet, []byte(benchMagic)) {
+		return
+	}
+	seq := int64(binary.BigEndian.Uint32(packet[4:]))
+	sent := time.Unix(0, int64(binary.BigEndian.Uint64(packet[8:])))
+	b.recvPackets++
+	if b.seqBase < 0 {
+		b.seqBase = seq
+		b.maxSeq = seq
+	}
+	if seq < b.seqBase || seq-b.seqBase >= maxSeqWindow {
+		b.reordered++
+		return
+	}
+
+	word, bit := (seq-b.seqBase)/64, uint((seq-b.seqBase)%64)
+	for int64(len(b.seen)) <= word {
+		b.seen = append(b.seen, 0)
+	}
+	if b.seen[word]&(1<<bit) != 0 {
+		b.duplicates++
+		return
+	}
+	b.seen[word] |= 1 << bit
+	if seq < b.maxSeq {
+		b.reordered++
+	} else {
+		b.maxSeq = seq
+	}
+
+	if b.mode != benchModeClient {
+		return
+	}
+	rtt := now.Sub(sent)
+	if b.rttCount == 0 || rtt < b.rttMin {
+		b.rttMin = rtt
+	}
+	if rtt > b.rttMax {
+		b.rttMax = rtt
+	}
+	if b.rttCount > 0 {
+		diff := rtt - b.lastRTT
+		if diff < 0 {
+			diff = -diff
+		}
+		b.jitterSum += diff
+	}
+	b.lastRTT = rtt
+	b.rttCount++
+	b.rttSum += rtt
+	if len(b.rtts) < maxRTTSamples {
+		b.rtts = append(b.rtts, rtt)
+	} else if i := rand.Int63n(b.rttCount); i < maxRTTSamples {
+		b.rtts[i] = rtt
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(p/100*float64(len(sorted))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// result takes a snapshot of the statistics, samples adds the raw round-trip times
+func (b *benchmark) result(samples bool) benchResult {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	end := b.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	elapsed := end.Sub(b.start).Seconds()
+	res := benchResult{
+		Time:        b.start,
+		Mode:        []string{"client", "echo", "sink"}[b.mode],
+		Duration:    elapsed,
+		SentPackets: b.sentPackets,
+		SentBytes:   b.sentBytes,
+		RecvPackets: b.recvPackets,
+		RecvBytes:   b.recvBytes,
+		Reordered:   b.reordered,
+		Duplicates:  b.duplicates,
+	}
+	if elapsed > 0 {
+		res.SendRate = float64(b.sentBytes) / elapsed
+		res.RecvRate = float64(b.recvBytes) / elapsed
+	}
+	unique := b.recvPackets - b.duplicates
+	if unique < 0 {
+		unique = 0
+	}
+	var expected int64
+	if b.mode == benchModeClient {
+		expected = b.sentPackets
+	} else if b.seqBase >= 0 {
+		expected = b.maxSeq - b.seqBase + 1
+	}
+	if expected > unique {
+		res.Lost = expected - unique
+		res.LossPercent = float64(res.Lost) * 100 / float64(expected)
+	}
+	if b.rttCount > 0 {
+		sorted := append([]time.Duration{}, b.rtts...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		res.RTTMin = durationMs(b.rttMin)
+		res.RTTMax = durationMs(b.rttMax)
+		res.RTTAvg = durationMs(b.rttSum / time.Duration(b.rttCount))
+		res.RTTP50 = durationMs(percentile(sorted, 50))
+		res.RTTP90 = durationMs(percentile(sorted, 90))
+		res.RTTP99 = durationMs(percentile(sorted, 99))
+		if b.rttCount > 1 {
+			res.Jitter = durationMs(b.jitterSum / time.Duration(b.rttCount-1))
+		}
+		if samples {
+			res.RTTSamples = make([]float64, len(b.rtts))
+			for i, rtt := range b.rtts {
+				res.RTTSamples[i] = durationMs(rtt)
+			}
+		}
+	}
+	return res
+}
+
+func (res benchResult) String() string {
+	lines := []string{
+		fmt.Sprintf("time: %.1fs", res.Duration),
+		fmt.Sprintf("sent: %d pkt, %d B, %s", res.SentPackets, res.SentBytes, formatRate(res.SendRate, "B")),
+		fmt.Sprintf("recv: %d pkt, %d B, %s", res.RecvPackets, res.RecvBytes, formatRate(res.RecvRate, "B")),
+	}
+	if res.RTTAvg > 0 {
+		lines = append(lines,
+			fmt.Sprintf("rtt min/avg/max: %.3f/%.3f/%.3f ms", res.RTTMin, res.RTTAvg, res.RTTMax),
+			fmt.Sprintf("rtt p50/p90/p99: %.3f/%.3f/%.3f ms", res.RTTP50, res.RTTP90, res.RTTP99),
+			fmt.Sprintf("jitter: %.3f ms", res.Jitter))
+	}
+	lines = append(lines, fmt.Sprintf("lost: %d (%.2f%%), reordered: %d, dup: %d", res.Lost, res.LossPercent, res.Reordered, res.Duplicates))
+	return strings.Join(lines, "\n")
+}
+
+// exportBenchResult writes res as JSON when the file name ends with .json, otherwise appends a CSV row
+func exportBenchResult(fileName string, res benchResult) error {
+	if strings.EqualFold(filepath.Ext(fileName), ".json") {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(fileName, data, 0644)
+	}
+
+	_, err := os.Stat(fileName)
+	writeHeader := os.IsNotExist(err)
+	fd, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	w := csv.NewWriter(fd)
+	if writeHeader {
+		w.Write([]string{"time", "mode", "duration_s", "sent_packets", "sent_bytes", "recv_packets", "recv_bytes",
+			"send_bytes_per_s", "recv_bytes_per_s", "rtt_min_ms", "rtt_avg_ms", "rtt_max_ms",
+			"rtt_p50_ms", "rtt_p90_ms", "rtt_p99_ms", "jitter_ms", "lost", "loss_percent", "reordered", "duplicates"})
+	}
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) }
+	d := func(v int64) string { return strconv.FormatInt(v, 10) }
+	w.Write([]string{res.Time.Format(time.RFC3339), res.Mode, f(res.Duration), d(res.SentPackets), d(res.SentBytes),
+		d(res.RecvPackets), d(res.RecvBytes), f(res.SendRate), f(res.RecvRate), f(res.RTTMin), f(res.RTTAvg),
+		f(res.RTTMax), f(res.RTTP50), f(res.RTTP90), f(res.RTTP99), f(res.Jitter), d(res.Lost),
+		f(res.LossPercent), d(res.Reordered), d(res.Duplicates)})
+	w.Flush()
+	return w.Error()
+}
+
+// benchGenerate streams packets until the duration elapses or the benchmark is stopped
+func (app *NetAssistantApp) benchGenerate(b *benchmark, size, pattern int, interval, duration time.Duration, udpTarget *net.UDPAddr) {
+	packet := make([]byte, size)
+	var p *pacer
+	if interval > 0 {
+		p = newPacer(interval, b.start)
+	}
+	deadline := b.start.Add(duration)
+	for seq := uint32(0); time.Now().Before(deadline); seq++ {
+		select {
+		case <-b.chanStop:
+			return
+		default:
+		}
+		buildBenchPacket(packet, seq, pattern)
+		n, err := app.writeData(packet, udpTarget)
+		if err != nil && len(app.connList) == 0 {
+			break
+		}
+		b.onSent(n)
+		if p != nil && !p.wait(b.chanStop) {
+			return
+		}
+	}
+	// give the last echoes some time to come back, they don't count to the duration
+	b.mu.Lock()
+	b.end = time.Now()
+	b.mu.Unlock()
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-b.chanStop:
+	}
+	b.stop()
+}
+
+func (app *NetAssistantApp) onBtnBenchStart() {
+	if b := app.bench; b != nil {
+		b.stop()
+		return
+	}
+	mode := app.combBenchMode.GetActive()
+	b := newBenchmark(mode)
+	if mode == benchModeClient {
+		strSize, _ := app.entryBenchSize.GetText()
+		size, err := strconv.Atoi(strings.TrimSpace(strSize))
+		if strings.TrimSpace(strSize) == "" {
+			size, err = 1024, nil
+		}
+		if err != nil || size < benchHeaderSize || size > 65507 {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">packet size must be %d-65507</span>`, benchHeaderSize))
+			return
+		}
+		strRate, _ := app.entryBenchRate.GetText()
+		rate, err := parseLimit(strRate)
+		if err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+			return
+		}
+		var interval time.Duration
+		if rate > 0 {
+			if app.combBenchRateUnit.GetActive() == 1 {
+				rate = rate / float64(size)
+			}
+			interval = time.Duration(float64(time.Second) / rate)
+		}
+		strDuration, _ := app.entryBenchDuration.GetText()
+		seconds, err := parseLimit(strDuration)
+		if err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+			return
+		}
+		if seconds == 0 {
+			seconds = 10
+		}
+		if len(app.connList) == 0 {
+			app.labelStatus.SetText(getI18nText(IT_NO_CONN))
+			return
+		}
+		udpTarget, err := app.udpTargetAddr()
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		go app.benchGenerate(b, size, app.combBenchPattern.GetActive(), interval, time.Duration(seconds*float64(time.Second)), udpTarget)
+	}
+
+	app.bench = b
+	app.combBenchMode.SetSensitive(false)
+	app.btnBenchStart.SetLabel(getI18nText(IT_STOP))
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		var reported int64
+		show := func() {
+			res := b.result(false)
+			delta := int(res.SentBytes - reported)
+			reported = res.SentBytes
+			glib.IdleAdd(func() {
+				app.updateSendCount(delta)
+				app.labelBenchResult.SetText(res.String())
+			})
+		}
+		for {
+			select {
+			case <-ticker.C:
+				show()
+			case <-b.chanStop:
+				show()
+				glib.IdleAdd(func() {
+					app.benchResult = b.result(true)
+					app.bench = nil
+					app.combBenchMode.SetSensitive(true)
+					app.btnBenchStart.SetLabel(getI18nText(IT_START))
+					app.btnBenchExport.SetSensitive(true)
+				})
+				return
+			}
+		}
+	}()
+}
+
+func (app *NetAssistantApp) onBtnBenchExport() {
+	dialog, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_EXPORT), app.appWindow, gtk.FILE_CHOOSER_ACTION_SAVE, "Save", "Cancel")
+	dialog.FileChooser.SetCurrentName("benchmark.csv")
+	res := dialog.Run()
+	if res == int(gtk.RESPONSE_ACCEPT) {
+		fileName := dialog.FileChooser.GetFilename()
+		if err := exportBenchResult(fileName, app.benchResult); err != nil {
+			log.Error(err)
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		}
+	}
+	dialog.Destroy()
+}
+
+func (app *NetAssistantApp) createBenchPage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
+	box.SetBorderWidth(10)
+
+	app.combBenchMode, _ = gtk.ComboBoxTextNew()
+	app.combBenchMode.AppendText(getI18nText(IT_BENCH_CLIENT))
+	app.combBenchMode.AppendText(getI18nText(IT_BENCH_ECHO))
+	app.combBenchMode.AppendText(getI18nText(IT_BENCH_SINK))
+	app.combBenchMode.SetActive(benchModeClient)
+	app.combBenchPattern, _ = gtk.ComboBoxTextNew()
+	app.combBenchPattern.AppendText(getI18nText(IT_PATTERN_FIXED))
+	app.combBenchPattern.AppendText(getI18nText(IT_PATTERN_RANDOM))
+	app.combBenchPattern.AppendText(getI18nText(IT_PATTERN_INCREMENT))
+	app.combBenchPattern.SetActive(patternFixed)
+	app.entryBenchSize, _ = gtk.EntryNew()
+	app.entryBenchSize.SetPlaceholderText(getI18nText(IT_PACKET_SIZE))
+	app.entryBenchRate, _ = gtk.EntryNew()
+	app.entryBenchRate.SetPlaceholderText(getI18nText(IT_RATE_MAX))
+	app.entryBenchRate.SetWidthChars(10)
+	app.combBenchRateUnit, _ = gtk.ComboBoxTextNew()
+	app.combBenchRateUnit.AppendText(getI18nText(IT_MSG_PER_SEC))
+	app.combBenchRateUnit.AppendText(getI18nText(IT_BYTES_PER_SEC))
+	app.combBenchRateUnit.SetActive(0)
+	rateBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	rateBox.PackStart(app.entryBenchRate, true, true, 0)
+	rateBox.PackStart(app.combBenchRateUnit, false, false, 0)
+	app.entryBenchDuration, _ = gtk.EntryNew()
+	app.entryBenchDuration.SetPlaceholderText(getI18nText(IT_BENCH_DURATION))
+
+	btnBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 10)
+	app.btnBenchStart, _ = gtk.ButtonNewWithLabel(getI18nText(IT_START))
+	app.btnBenchStart.Connect("clicked", app.onBtnBenchStart)
+	app.btnBenchExport, _ = gtk.ButtonNewWithLabel(getI18nText(IT_EXPORT))
+	app.btnBenchExport.Connect("clicked", app.onBtnBenchExport)
+	app.btnBenchExport.SetSensitive(false)
+	btnBox.PackStart(app.btnBenchStart, true, false, 0)
+	btnBox.PackStart(app.btnBenchExport, true, false, 0)
+
+	app.labelBenchResult, _ = gtk.LabelNew("")
+	app.labelBenchResult.SetXAlign(0)
+	app.labelBenchResult.SetSelectable(true)
+
+	box.PackStart(app.combBenchMode, false, false, 0)
+	box.PackStart(app.combBenchPattern, false, false, 0)
+	box.PackStart(app.entryBenchSize, false, false, 0)
+	box.PackStart(rateBox, false, false, 0)
+	box.PackStart(app.entryBenchDuration, false, false, 0)
+	box.PackStart(btnBox, false, false, 0)
+	box.PackStart(app.labelBenchResult, false, false, 0)
+	return box
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// capture file formats
+const (
+	captureRaw = iota // received bytes exactly as they arrived
+	captureHex        // one line per read: direction, peer and hex bytes
+	captureLog        // one line per read: timestamp, direction, peer and quoted data
+)
+
+var directionNames = map[int]string{dirRecv: "RX", dirSend: "TX"}
+
+// captureWriter keeps the capture file open and writes the traffic of every connection to it
+type captureWriter struct {
+	mu       sync.Mutex
+	file     *os.File
+	fileName string
+	format   int
+	rotation rotation
+	size     int64
+	opened   time.Time
+	onError  func(err error) // the capture stopped
+	onNotice func(err error) // compressing or pruning the rotated files failed, the capture goes on
+}
+
+// captureLine formats one read or write, the raw format has no framing and only keeps received data
+func captureLine(format, dir int, peer string, t time.Time, data []byte) []byte {
+	switch format {
+	case captureHex:
+		return []byte(fmt.Sprintf("%s %s %s\n", directionNames[dir], peer, hexString(data)))
+	case captureLog:
+		return []byte(fmt.Sprintf("%s %s %s %s\n", t.Format(time.DateTime+".000000"), directionNames[dir], peer, strconv.Quote(string(data))))
+	}
+	if dir != dirRecv {
+		return nil
+	}
+	return data
+}
+
+func (c *captureWriter) open(fileName string, format int, rot rotation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+	c.fileName, _ = filepath.Abs(fileName)
+	c.format = format
+	c.rotation = rot
+	return c.openLocked()
+}
+
+func (c *captureWriter) openLocked() error {
+	file, err := os.OpenFile(c.fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	c.file = file
+	c.size = info.Size()
+	c.opened = time.Now()
+	return nil
+}
+
+// due reports whether the file has to be rotated before n more bytes are written
+func (c *captureWriter) due(n int, now time.Time) bool {
+	if c.size == 0 {
+		return false
+	}
+	if c.rotation.maxSize > 0 && c.size+int64(n) > c.rotation.maxSize {
+		return true
+	}
+	return c.rotation.maxAge > 0 && now.Sub(c.opened) >= c.rotation.maxAge
+}
+
+// rotateLocked moves the current file aside and starts a new one under the original name
+func (c *captureWriter) rotateLocked(now time.Time) error {
+	if err := c.closeLocked(); err != nil {
+		return err
+	}
+	rotated := rotatedName(c.fileName, c.rotation.dated, now)
+	if err := os.Rename(c.fileName, rotated); err != nil {
+		return err
+	}
+	finishRotation(c.fileName, rotated, c.rotation, c.onNotice)
+	return c.openLocked()
+}
+
+func (c *captureWriter) closeLocked() error {
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+func (c *captureWriter) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+func (c *captureWriter) active() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file != nil
+}
+
+// write appends to the capture file, a failed write stops the capture and is reported through onError
+func (c *captureWriter) write(dir int, peer string, t time.Time, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return
+	}
+	line := captureLine(c.format, dir, peer, t, data)
+	if len(line) == 0 {
+		return
+	}
+	now := time.Now()
+	var err error
+	if c.due(len(line), now) {
+		err = c.rotateLocked(now)
+	}
+	if err == nil {
+		var n int
+		n, err = c.file.Write(line)
+		c.size += int64(n)
+	}
+	if err != nil {
+		c.closeLocked()
+		if c.onError != nil {
+			c.onError(err)
+		}
+	}
+}
+
+// captureData writes traffic of a connection to the capture file and the session, addr is the peer of an unconnected UDP socket
+func (app *NetAssistantApp) captureData(dir int, conn net.Conn, addr *net.UDPAddr, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	peer := ""
+	if addr != nil {
+		peer = addr.String()
+	} else if remote := conn.RemoteAddr(); remote != nil {
+		peer = remote.String()
+	}
+	now := time.Now()
+	app.capture.write(dir, peer, now, data)
+	pkt := sessionPacket{dir: dir, time: now, local: conn.LocalAddr(), remote: conn.RemoteAddr(), data: append([]byte(nil), data...)}
+	switch conn.(type) {
+	case *net.UDPConn, *socksUDPConn:
+		pkt.udp = true
+	}
+	if addr != nil {
+		pkt.remote = addr
+	}
+	app.session.add(pkt)
+}
+
+func (app *NetAssistantApp) onCaptureError(err error) {
+	log.Error(err)
+	glib.IdleAdd(func() {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		app.cbReceive2File.SetActive(false)
+	})
+}
+
+// onRotateError reports a failed compress or prune of a rotated capture file without stopping the capture
+func (app *NetAssistantApp) onRotateError(err error) {
+	log.Error(err)
+	glib.IdleAdd(func() {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	})
+}
+
+func (app *NetAssistantApp) onCbReceive2File() {
+	if !app.cbReceive2File.GetActive() {
+		app.combCaptureFormat.SetSensitive(true)
+		if err := app.capture.close(); err != nil {
+			app.onCaptureError(err)
+		}
+		return
+	}
+	if app.capture.active() {
+		return
+	}
+	dialog, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_SAVE_TO_FILE), app.appWindow, gtk.FILE_CHOOSER_ACTION_SAVE, "Save", "Cancel")
+	dialog.FileChooser.SetCurrentName("capture.log")
+	res := dialog.Run()
+	fileName := dialog.FileChooser.GetFilename()
+	dialog.Destroy()
+	if res != int(gtk.RESPONSE_ACCEPT) {
+		app.cbReceive2File.SetActive(false)
+		return
+	}
+	rot, err := app.rotationSettings()
+	if err != nil {
+		app.onCaptureError(err)
+		return
+	}
+	app.capture.onError = app.onCaptureError
+	app.capture.onNotice = app.onRotateError
+	if err := app.capture.open(fileName, app.combCaptureFormat.GetActive(), rot); err != nil {
+		app.onCaptureError(err)
+		return
+	}
+	app.combCaptureFormat.SetSensitive(false)
+}
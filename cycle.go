@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// units of the cycle send rate entry
+const (
+	rateUnitInterval = iota // milliseconds between messages
+	rateUnitMessages        // messages per second
+	rateUnitBytes           // bytes per second
+)
+
+// below this the pacer busy waits instead of sleeping
+const spinThreshold = 200 * time.Microsecond
+
+// the pacer never tries to catch up more than this
+const maxBacklog = time.Second
+
+type cycleSettings struct {
+	interval    time.Duration
+	target      string
+	maxCount    int64
+	maxDuration time.Duration
+}
+
+// pacer schedules ticks on absolute deadlines so the period does not drift with the send time
+type pacer struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newPacer(interval time.Duration, start time.Time) *pacer {
+	return &pacer{interval: interval, next: start.Add(interval)}
+}
+
+// wait blocks until the next tick, returns false if chanStop is closed first
+func (p *pacer) wait(chanStop chan bool) bool {
+	now := time.Now()
+	if now.Sub(p.next) > maxBacklog {
+		p.next = now.Add(-maxBacklog)
+	}
+	if d := p.next.Sub(now) - spinThreshold; d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-chanStop:
+			timer.Stop()
+			return false
+		}
+	}
+	for time.Now().Before(p.next) {
+		runtime.Gosched()
+	}
+	p.next = p.next.Add(p.interval)
+	select {
+	case <-chanStop:
+		return false
+	default:
+		return true
+	}
+}
+
+// formatRate formats a per second value with a binary unit prefix
+func formatRate(value float64, unit string) string {
+	prefixes := []string{"", "K", "M", "G"}
+	i := 0
+	for value >= 1024 && i < len(prefixes)-1 {
+		value /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %s%s/s", value, prefixes[i], unit)
+}
+
+func parseLimit(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	return value, nil
+}
+
+// cycleSettings reads the cycle send options, size is the length of one message.
+// Every tick sends the message to all connections, so a bytes per second rate is shared between them.
+func (app *NetAssistantApp) cycleSettings(size int) (cycleSettings, error) {
+	settings := cycleSettings{}
+	if len(app.connList) > 1 {
+		size *= len(app.connList)
+	}
+	strCycleTime, _ := app.entryCycleTime.GetText()
+	value, err := parseLimit(strCycleTime)
+	if err != nil {
+		return settings, err
+	}
+	switch app.combRateUnit.GetActive() {
+	case rateUnitMessages:
+		if value == 0 {
+			return settings, fmt.Errorf("rate must be greater than 0")
+		}
+		settings.interval = time.Duration(float64(time.Second) / value)
+		settings.target = fmt.Sprintf("%g msg/s", value)
+	case rateUnitBytes:
+		if value == 0 {
+			return settings, fmt.Errorf("rate must be greater than 0")
+		}
+		if size == 0 {
+			return settings, fmt.Errorf("an empty message cannot be sent at a bytes per second rate")
+		}
+		settings.interval = time.Duration(float64(size) / value * float64(time.Second))
+		settings.target = formatRate(value, "B")
+	default:
+		if strings.TrimSpace(strCycleTime) == "" {
+			value = 1000
+		}
+		settings.interval = time.Duration(value * float64(time.Millisecond))
+		settings.target = "max" // an interval of 0 sends without pacing
+		if value > 0 {
+			settings.target = fmt.Sprintf("%g msg/s", 1000/value)
+		}
+	}
+	if settings.interval <= 0 && app.combRateUnit.GetActive() != rateUnitInterval {
+		return settings, fmt.Errorf("rate %s is too high, the interval would be below 1ns", settings.target)
+	}
+
+	strCount, _ := app.entryCycleCount.GetText()
+	maxCount, err := parseLimit(strCount)
+	if err != nil {
+		return settings, err
+	}
+	settings.maxCount = int64(maxCount)
+	strDuration, _ := app.entryCycleDuration.GetText()
+	maxDuration, err := parseLimit(strDuration)
+	if err != nil {
+		return settings, err
+	}
+	settings.maxDuration = time.Duration(maxDuration * float64(time.Second))
+	return settings, nil
+}
+
+// cycleSend repeats data until it is stopped or reaches the configured limits
+func (app *NetAssistantApp) cycleSend(data []byte, udpTarget *net.UDPAddr, settings cycleSettings, chanStop chan bool) {
+	var count, total, reported int64
+	start := time.Now()
+	showRate := func() {
+		elapsed := time.Since(start).Seconds()
+		n := atomic.LoadInt64(&count)
+		bytes := atomic.LoadInt64(&total)
+		delta := int(bytes - reported)
+		reported = bytes
+		msg := fmt.Sprintf("%d msg, %.1f msg/s, %s (target %s)", n, float64(n)/elapsed, formatRate(float64(bytes)/elapsed, "B"), settings.target)
+		glib.IdleAdd(func() {
+			app.updateSendCount(delta)
+			app.labelCycleRate.SetText(msg)
+		})
+	}
+
+	chanDone := make(chan bool)
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				showRate()
+			case <-chanDone:
+				showRate()
+				select {
+				case <-chanStop: // stopped from the send button
+				default:
+					glib.IdleAdd(func() {
+						app.btnSend.SetLabel(getI18nText(IT_SEND))
+					})
+				}
+				return
+			}
+		}
+	}()
+	defer close(chanDone)
+
+	p := newPacer(settings.interval, start)
+	for {
+		n, err := app.writeData(data, udpTarget)
+		if err != nil && len(app.connList) == 0 {
+			glib.IdleAdd(func() {
+				app.labelStatus.SetText(getI18nText(IT_NO_CONN))
+			})
+			return
+		}
+		atomic.AddInt64(&count, 1)
+		atomic.AddInt64(&total, int64(n))
+		if settings.maxCount > 0 && atomic.LoadInt64(&count) >= settings.maxCount {
+			return
+		}
+		if !p.wait(chanStop) {
+			return
+		}
+		if settings.maxDuration > 0 && time.Since(start) >= settings.maxDuration {
+			return
+		}
+	}
+}
@@ -0,0 +1,833 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// payloadDecoder turns a received payload into readable text shown below the raw data
+type payloadDecoder func(data []byte) (string, error)
+
+type namedDecoder struct {
+	key string // i18n key of the name
+	dec payloadDecoder
+}
+
+// decoders of the receive area, the protobuf descriptor and template decoders are set up when their definitions are loaded
+var payloadDecoders = []namedDecoder{
+	{IT_DECODER_NONE, nil},
+	{IT_DECODER_JSON, decodeJSON},
+	{IT_DECODER_MSGPACK, decodeMsgpack},
+	{IT_DECODER_CBOR, decodeCBOR},
+	{IT_DECODER_PROTOBUF, decodeProtobuf},
+	{IT_DECODER_PROTO_DESC, nil},
+	{IT_DECODER_TEMPLATE, nil},
+}
+
+// indexes of the decoders in payloadDecoders that depend on loaded definitions
+const (
+	decoderProtoDesc = 5
+	decoderTemplate  = 6
+)
+
+// nesting limit of decoded values, deeper input is rejected
+const maxDecodeDepth = 64
+
+var errTruncated = errors.New("truncated data")
+
+func decodeJSON(data []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// values of the decoded tree besides the plain Go types
+type (
+	mapEntry struct {
+		key   interface{}
+		value interface{}
+	}
+	extValue struct {
+		typ  int8
+		data []byte
+	}
+	taggedValue struct {
+		tag   uint64
+		value interface{}
+	}
+	simpleValue uint8
+	undefined   struct{}
+	fieldName   string // printed without quotes
+)
+
+func scalarString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(x)
+	case fieldName:
+		return string(x)
+	case []byte:
+		return fmt.Sprintf("bytes(%d) %s", len(x), hexString(x))
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case extValue:
+		return fmt.Sprintf("ext(%d) %s", x.typ, hexString(x.data))
+	case simpleValue:
+		return fmt.Sprintf("simple(%d)", x)
+	case undefined:
+		return "undefined"
+	case []interface{}, []mapEntry, taggedValue:
+		// containers used as map keys stay on one line
+		var sb strings.Builder
+		writeTree(&sb, "", v, "")
+		return strings.Join(strings.Fields(sb.String()), " ")
+	}
+	return fmt.Sprint(v)
+}
+
+// writeTree writes a value as indented lines, containers list their items one level deeper
+func writeTree(sb *strings.Builder, label string, v interface{}, indent string) {
+	switch x := v.(type) {
+	case []interface{}:
+		sb.WriteString(fmt.Sprintf("%s%s[%d]\n", indent, label, len(x)))
+		for i, item := range x {
+			writeTree(sb, fmt.Sprintf("%d: ", i), item, indent+"  ")
+		}
+	case []mapEntry:
+		sb.WriteString(fmt.Sprintf("%s%s{%d}\n", indent, label, len(x)))
+		for _, entry := range x {
+			writeTree(sb, scalarString(entry.key)+": ", entry.value, indent+"  ")
+		}
+	case taggedValue:
+		writeTree(sb, fmt.Sprintf("%stag(%d) ", label, x.tag), x.value, indent)
+	default:
+		sb.WriteString(indent + label + scalarString(v) + "\n")
+	}
+}
+
+// formatValues prints the values decoded from one payload, several values follow each other
+func formatValues(values []interface{}) string {
+	var sb strings.Builder
+	for _, v := range values {
+		writeTree(&sb, "", v, "")
+	}
+	return sb.String()
+}
+
+// binReader reads the big endian encodings of MessagePack and CBOR
+type binReader struct {
+	data  []byte
+	pos   int
+	depth int
+}
+
+func (r *binReader) next(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, errTruncated
+	}
+	b := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b, nil
+}
+
+func (r *binReader) uint(size int) (uint64, error) {
+	b, err := r.next(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// checkCount rejects item counts that cannot fit in the rest of the data, each item takes at least a byte
+func (r *binReader) checkCount(n uint64) error {
+	if n > uint64(len(r.data)-r.pos) {
+		return errTruncated
+	}
+	return nil
+}
+
+func (r *binReader) enter() error {
+	r.depth++
+	if r.depth > maxDecodeDepth {
+		return errors.New("nesting too deep")
+	}
+	return nil
+}
+
+// decodeAll decodes values until the data is used up
+func (r *binReader) decodeAll(value func() (interface{}, error)) (string, error) {
+	values := []interface{}{}
+	for r.pos < len(r.data) {
+		v, err := value()
+		if err != nil {
+			return "", fmt.Errorf("offset %d: %s", r.pos, err)
+		}
+		values = append(values, v)
+	}
+	return formatValues(values), nil
+}
+
+func decodeMsgpack(data []byte) (string, error) {
+	r := &binReader{data: data}
+	return r.decodeAll(r.msgpack)
+}
+
+func (r *binReader) msgpack() (interface{}, error) {
+	head, err := r.uint(1)
+	if err != nil {
+		return nil, err
+	}
+	b := byte(head)
+	switch {
+	case b <= 0x7F:
+		return int64(b), nil
+	case b <= 0x8F:
+		return r.msgpackMap(uint64(b & 0x0F))
+	case b <= 0x9F:
+		return r.msgpackArray(uint64(b & 0x0F))
+	case b <= 0xBF:
+		return r.msgpackStr(uint64(b & 0x1F))
+	case b >= 0xE0:
+		return int64(int8(b)), nil
+	}
+	switch b {
+	case 0xC0:
+		return nil, nil
+	case 0xC2:
+		return false, nil
+	case 0xC3:
+		return true, nil
+	case 0xC4, 0xC5, 0xC6:
+		n, err := r.uint(1 << (b - 0xC4))
+		if err != nil {
+			return nil, err
+		}
+		return r.next(n)
+	case 0xC7, 0xC8, 0xC9:
+		n, err := r.uint(1 << (b - 0xC7))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackExt(n)
+	case 0xCA:
+		n, err := r.uint(4)
+		return math.Float32frombits(uint32(n)), err
+	case 0xCB:
+		n, err := r.uint(8)
+		return math.Float64frombits(n), err
+	case 0xCC, 0xCD, 0xCE, 0xCF:
+		return r.uint(1 << (b - 0xCC))
+	case 0xD0, 0xD1, 0xD2, 0xD3:
+		size := 1 << (b - 0xD0)
+		n, err := r.uint(size)
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, err
+	case 0xD4, 0xD5, 0xD6, 0xD7, 0xD8:
+		return r.msgpackExt(1 << (b - 0xD4))
+	case 0xD9, 0xDA, 0xDB:
+		n, err := r.uint(1 << (b - 0xD9))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackStr(n)
+	case 0xDC, 0xDD:
+		n, err := r.uint(2 << (b - 0xDC))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackArray(n)
+	case 0xDE, 0xDF:
+		n, err := r.uint(2 << (b - 0xDE))
+		if err != nil {
+			return nil, err
+		}
+		return r.msgpackMap(n)
+	}
+	return nil, fmt.Errorf("invalid type byte 0x%02X", b)
+}
+
+func (r *binReader) msgpackStr(n uint64) (interface{}, error) {
+	b, err := r.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(b) {
+		return b, nil
+	}
+	return string(b), nil
+}
+
+// msgpackExt reads ext data, the timestamp extension -1 becomes a time
+func (r *binReader) msgpackExt(n uint64) (interface{}, error) {
+	typ, err := r.uint(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ) == -1 {
+		switch n {
+		case 4:
+			return time.Unix(int64(binary.BigEndian.Uint32(data)), 0), nil
+		case 8:
+			v := binary.BigEndian.Uint64(data)
+			return time.Unix(int64(v&0x3FFFFFFFF), int64(v>>34)), nil
+		case 12:
+			return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data))), nil
+		}
+	}
+	return extValue{int8(typ), data}, nil
+}
+
+func (r *binReader) msgpackArray(n uint64) (interface{}, error) {
+	if err := r.checkCount(n); err != nil {
+		return nil, err
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	list := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, err := r.msgpack()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (r *binReader) msgpackMap(n uint64) (interface{}, error) {
+	if err := r.checkCount(n); err != nil {
+		return nil, err
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	entries := make([]mapEntry, 0, n)
+	for i := uint64(0); i < n; i++ {
+		key, err := r.msgpack()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.msgpack()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, mapEntry{key, value})
+	}
+	return entries, nil
+}
+
+func decodeCBOR(data []byte) (string, error) {
+	r := &binReader{data: data}
+	return r.decodeAll(func() (interface{}, error) {
+		v, err := r.cbor()
+		if _, isBreak := v.(cborBreak); isBreak {
+			return nil, errors.New("unexpected break")
+		}
+		return v, err
+	})
+}
+
+// cborBreak ends an indefinite length item
+type cborBreak struct{}
+
+// cborArg reads the argument of an initial byte, indefinite reports the length 31 marker
+func (r *binReader) cborArg(info byte) (arg uint64, indefinite bool, err error) {
+	switch {
+	case info < 24:
+		return uint64(info), false, nil
+	case info <= 27:
+		arg, err = r.uint(1 << (info - 24))
+		return arg, false, err
+	case info == 31:
+		return 0, true, nil
+	}
+	return 0, false, fmt.Errorf("invalid additional info %d", info)
+}
+
+func (r *binReader) cbor() (interface{}, error) {
+	head, err := r.uint(1)
+	if err != nil {
+		return nil, err
+	}
+	major, info := byte(head)>>5, byte(head)&0x1F
+	if major == 7 {
+		return r.cborSimple(info)
+	}
+	arg, indefinite, err := r.cborArg(info)
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major == 0 || major == 1 || major == 6) {
+		return nil, fmt.Errorf("invalid indefinite length for major type %d", major)
+	}
+	switch major {
+	case 0:
+		return arg, nil
+	case 1:
+		if arg <= math.MaxInt64 {
+			return -1 - int64(arg), nil
+		}
+		n := new(big.Int).SetUint64(arg)
+		return n.Neg(n).Sub(n, big.NewInt(1)), nil
+	case 2, 3:
+		var b []byte
+		if indefinite {
+			b, err = r.cborChunks(major)
+		} else {
+			b, err = r.next(arg)
+		}
+		if err != nil || major == 2 {
+			return b, err
+		}
+		if !utf8.Valid(b) {
+			return nil, errors.New("invalid UTF-8 in text string")
+		}
+		return string(b), nil
+	case 4:
+		return r.cborArray(arg, indefinite)
+	case 5:
+		return r.cborMap(arg, indefinite)
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	v, err := r.cbor()
+	if err != nil {
+		return nil, err
+	}
+	if arg == 1 { // epoch time
+		switch t := v.(type) {
+		case uint64:
+			return time.Unix(int64(t), 0), nil
+		case int64:
+			return time.Unix(t, 0), nil
+		case float64:
+			sec, frac := math.Modf(t)
+			return time.Unix(int64(sec), int64(frac*1e9)), nil
+		}
+	}
+	return taggedValue{arg, v}, nil
+}
+
+// cborChunks joins the chunks of an indefinite length byte or text string
+func (r *binReader) cborChunks(major byte) ([]byte, error) {
+	b := []byte{}
+	for {
+		head, err := r.uint(1)
+		if err != nil {
+			return nil, err
+		}
+		if head == 0xFF {
+			return b, nil
+		}
+		if byte(head)>>5 != major {
+			return nil, errors.New("invalid chunk in indefinite string")
+		}
+		n, indefinite, err := r.cborArg(byte(head) & 0x1F)
+		if err != nil {
+			return nil, err
+		}
+		if indefinite {
+			return nil, errors.New("nested indefinite string")
+		}
+		chunk, err := r.next(n)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, chunk...)
+	}
+}
+
+func (r *binReader) cborArray(n uint64, indefinite bool) (interface{}, error) {
+	if !indefinite {
+		if err := r.checkCount(n); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	list := []interface{}{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		v, err := r.cbor()
+		if err != nil {
+			return nil, err
+		}
+		if _, isBreak := v.(cborBreak); isBreak {
+			if !indefinite {
+				return nil, errors.New("unexpected break")
+			}
+			break
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+func (r *binReader) cborMap(n uint64, indefinite bool) (interface{}, error) {
+	if !indefinite {
+		if err := r.checkCount(n); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { r.depth-- }()
+	entries := []mapEntry{}
+	for i := uint64(0); indefinite || i < n; i++ {
+		key, err := r.cbor()
+		if err != nil {
+			return nil, err
+		}
+		if _, isBreak := key.(cborBreak); isBreak {
+			if !indefinite {
+				return nil, errors.New("unexpected break")
+			}
+			break
+		}
+		value, err := r.cbor()
+		if err != nil {
+			return nil, err
+		}
+		if _, isBreak := value.(cborBreak); isBreak {
+			return nil, errors.New("map without value")
+		}
+		entries = append(entries, mapEntry{key, value})
+	}
+	return entries, nil
+}
+
+func (r *binReader) cborSimple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22:
+		return nil, nil
+	case 23:
+		return undefined{}, nil
+	case 24:
+		n, err := r.uint(1)
+		return simpleValue(n), err
+	case 25:
+		n, err := r.uint(2)
+		return halfFloat(uint16(n)), err
+	case 26:
+		n, err := r.uint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 27:
+		n, err := r.uint(8)
+		return math.Float64frombits(n), err
+	case 31:
+		return cborBreak{}, nil
+	}
+	if info < 20 {
+		return simpleValue(info), nil
+	}
+	return nil, fmt.Errorf("invalid simple value %d", info)
+}
+
+// halfFloat converts an IEEE 754 half precision number
+func halfFloat(h uint16) float64 {
+	exp, mant := int(h>>10)&0x1F, float64(h&0x3FF)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireStart   = 3
+	wireEnd     = 4
+	wireFixed32 = 5
+)
+
+var wireNames = []string{"varint", "fixed64", "bytes", "group", "end group", "fixed32"}
+
+// decodeProtobuf decodes a message without a schema, showing field numbers and wire types
+func decodeProtobuf(data []byte) (string, error) {
+	fields, err := protoFields(data, 0, -1)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	for _, field := range fields {
+		writeTree(&sb, scalarString(field.key)+": ", field.value, "")
+	}
+	return sb.String(), nil
+}
+
+func protoVarint(data []byte, pos int) (uint64, int, error) {
+	var v uint64
+	for shift := 0; shift < 64; shift += 7 {
+		if pos >= len(data) {
+			return 0, pos, errTruncated
+		}
+		b := data[pos]
+		pos++
+		v |= uint64(b&0x7F) << shift
+		if b < 0x80 {
+			return v, pos, nil
+		}
+	}
+	return 0, pos, errors.New("varint too long")
+}
+
+// protoFields parses fields until the data ends, or until the end of group endGroup when it is not -1
+func protoFields(data []byte, depth int, endGroup int) ([]mapEntry, error) {
+	_, fields, err := protoFieldsAt(data, 0, depth, endGroup)
+	return fields, err
+}
+
+func protoFieldsAt(data []byte, pos int, depth int, endGroup int) (int, []mapEntry, error) {
+	if depth > maxDecodeDepth {
+		return pos, nil, errors.New("nesting too deep")
+	}
+	fields := []mapEntry{}
+	for pos < len(data) {
+		key, next, err := protoVarint(data, pos)
+		if err != nil {
+			return pos, nil, err
+		}
+		pos = next
+		number, wire := key>>3, int(key&7)
+		if number == 0 || wire > wireFixed32 {
+			return pos, nil, fmt.Errorf("invalid field key %d", key)
+		}
+		name := fieldName(fmt.Sprintf("%d %s", number, wireNames[wire]))
+		var value interface{}
+		switch wire {
+		case wireVarint:
+			var v uint64
+			v, pos, err = protoVarint(data, pos)
+			if err != nil {
+				return pos, nil, err
+			}
+			value = fieldName(fmt.Sprintf("%d (sint %d)", v, int64(v>>1)^-int64(v&1)))
+		case wireFixed64:
+			if pos+8 > len(data) {
+				return pos, nil, errTruncated
+			}
+			v := binary.LittleEndian.Uint64(data[pos:])
+			pos += 8
+			value = fieldName(fmt.Sprintf("%d (double %s)", v, strconv.FormatFloat(math.Float64frombits(v), 'g', -1, 64)))
+		case wireFixed32:
+			if pos+4 > len(data) {
+				return pos, nil, errTruncated
+			}
+			v := binary.LittleEndian.Uint32(data[pos:])
+			pos += 4
+			value = fieldName(fmt.Sprintf("%d (float %s)", v, strconv.FormatFloat(float64(math.Float32frombits(v)), 'g', -1, 32)))
+		case wireBytes:
+			var n uint64
+			n, pos, err = protoVarint(data, pos)
+			if err != nil {
+				return pos, nil, err
+			}
+			if n > uint64(len(data)-pos) {
+				return pos, nil, errTruncated
+			}
+			value = protoBytesValue(data[pos:pos+int(n)], depth)
+			pos += int(n)
+		case wireStart:
+			var group []mapEntry
+			pos, group, err = protoFieldsAt(data, pos, depth+1, int(number))
+			if err != nil {
+				return pos, nil, err
+			}
+			value = group
+		case wireEnd:
+			if int(number) != endGroup {
+				return pos, nil, fmt.Errorf("unexpected end of group %d", number)
+			}
+			return pos, fields, nil
+		}
+		fields = append(fields, mapEntry{name, value})
+	}
+	if endGroup >= 0 {
+		return pos, nil, errTruncated
+	}
+	return pos, fields, nil
+}
+
+// protoBytesValue guesses what a length delimited field holds: a nested message, text or bytes
+func protoBytesValue(b []byte, depth int) interface{} {
+	if len(b) > 0 {
+		if fields, err := protoFields(b, depth+1, -1); err == nil {
+			return fields
+		}
+	}
+	if utf8.Valid(b) && isPrintable(string(b)) {
+		return string(b)
+	}
+	return b
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if r < 0x20 && r != '\t' && r != '\r' && r != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+// indentBlock prefixes every line of the decoded text so it stands apart from the raw data
+func indentBlock(text string) string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return ""
+	}
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ") + "\n"
+}
+
+// decodePayload runs the selected decoder, a payload it cannot decode shows the reason instead
+func (app *NetAssistantApp) decodePayload(data []byte) string {
+	if app.payloadDecoder == nil || len(data) == 0 {
+		return ""
+	}
+	text, err := app.payloadDecoder(data)
+	if err != nil {
+		name := getI18nText(payloadDecoders[app.combRecvDecoder.GetActive()].key)
+		return indentBlock(fmt.Sprintf("(%s: %s)", name, err))
+	}
+	return indentBlock(text)
+}
+
+func (app *NetAssistantApp) onDecoderChanged() {
+	index := app.combRecvDecoder.GetActive()
+	app.payloadDecoder = nil
+	if index > 0 && index < len(payloadDecoders) {
+		app.payloadDecoder = payloadDecoders[index].dec
+	}
+	if index == decoderProtoDesc {
+		if app.protoSet == nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, getI18nText(IT_NO_DESCRIPTOR_SET)))
+		} else {
+			name, _ := app.entryProtoMessage.GetText()
+			dec, err := app.protoSet.decoder(name)
+			if err != nil {
+				app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+			}
+			app.payloadDecoder = dec
+		}
+	}
+	if index == decoderTemplate {
+		if app.template == nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, getI18nText(IT_NO_TEMPLATE)))
+		} else {
+			app.payloadDecoder = app.template.decode
+		}
+	}
+	app.renderRecords()
+}
+
+func (app *NetAssistantApp) onBtnLoadDescriptorSet() {
+	dialog, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_DESCRIPTOR_SET), app.appWindow, gtk.FILE_CHOOSER_ACTION_OPEN, "Open", "Cancel")
+	res := dialog.Run()
+	fileName := dialog.FileChooser.GetFilename()
+	dialog.Destroy()
+	if res != int(gtk.RESPONSE_ACCEPT) {
+		return
+	}
+	set, err := loadDescriptorSet(fileName)
+	if err != nil {
+		log.Error(err)
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
+	app.protoSet = set
+	app.entryProtoMessage.SetPlaceholderText(set.defaultName)
+	if app.combRecvDecoder.GetActive() == decoderProtoDesc {
+		app.onDecoderChanged()
+	} else {
+		app.combRecvDecoder.SetActive(decoderProtoDesc)
+	}
+}
+
+func (app *NetAssistantApp) createDecoderBox() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	labelDecoder, _ := gtk.LabelNew(getI18nText(IT_DECODER))
+	app.combRecvDecoder, _ = gtk.ComboBoxTextNew()
+	for _, item := range payloadDecoders {
+		app.combRecvDecoder.AppendText(getI18nText(item.key))
+	}
+	app.combRecvDecoder.SetActive(0)
+	app.combRecvDecoder.Connect("changed", app.onDecoderChanged)
+	decoderHbox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	decoderHbox.PackStart(labelDecoder, false, false, 0)
+	decoderHbox.PackStart(app.combRecvDecoder, false, false, 0)
+
+	btnDescriptor, _ := gtk.ButtonNewWithLabel(getI18nText(IT_DESCRIPTOR_SET))
+	btnDescriptor.Connect("clicked", app.onBtnLoadDescriptorSet)
+	app.entryProtoMessage, _ = gtk.EntryNew()
+	app.entryProtoMessage.SetPlaceholderText(getI18nText(IT_MESSAGE_TYPE))
+	app.entryProtoMessage.Connect("activate", app.onDecoderChanged)
+	protoHbox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	protoHbox.PackStart(btnDescriptor, false, false, 0)
+	protoHbox.PackStart(app.entryProtoMessage, true, true, 0)
+
+	btnTemplate, _ := gtk.ButtonNewWithLabel(getI18nText(IT_TEMPLATE))
+	btnTemplate.Connect("clicked", app.onBtnTemplate)
+
+	box.PackStart(decoderHbox, false, false, 0)
+	box.PackStart(protoHbox, false, false, 0)
+	box.PackStart(btnTemplate, false, false, 0)
+	return box
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	"github.com/gotk3/gotk3/gtk"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+type textEncoding struct {
+	name string
+	enc  encoding.Encoding
+}
+
+// character encodings for received text and sent text, the first is the default
+var textEncodings = []textEncoding{
+	{"UTF-8", unicode.UTF8},
+	{"GBK", simplifiedchinese.GBK},
+	{"GB18030", simplifiedchinese.GB18030},
+	{"Big5", traditionalchinese.Big5},
+	{"Shift-JIS", japanese.ShiftJIS},
+	{"EUC-JP", japanese.EUCJP},
+	{"EUC-KR", korean.EUCKR},
+	{"ISO-8859-1", charmap.ISO8859_1},
+	{"ISO-8859-2", charmap.ISO8859_2},
+	{"ISO-8859-3", charmap.ISO8859_3},
+	{"ISO-8859-4", charmap.ISO8859_4},
+	{"ISO-8859-5", charmap.ISO8859_5},
+	{"ISO-8859-6", charmap.ISO8859_6},
+	{"ISO-8859-7", charmap.ISO8859_7},
+	{"ISO-8859-8", charmap.ISO8859_8},
+	{"ISO-8859-9", charmap.ISO8859_9},
+	{"ISO-8859-10", charmap.ISO8859_10},
+	{"ISO-8859-13", charmap.ISO8859_13},
+	{"ISO-8859-14", charmap.ISO8859_14},
+	{"ISO-8859-15", charmap.ISO8859_15},
+	{"ISO-8859-16", charmap.ISO8859_16},
+	{"Windows-1252", charmap.Windows1252},
+	{"UTF-16LE", unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)},
+	{"UTF-16BE", unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)},
+}
+
+func textEncodingAt(index int) encoding.Encoding {
+	if index < 0 || index >= len(textEncodings) {
+		index = 0
+	}
+	return textEncodings[index].enc
+}
+
+// streamDecoder decodes a byte stream read in chunks, a character split between reads is kept until the rest arrives
+type streamDecoder struct {
+	dec     transform.Transformer
+	pending []byte
+}
+
+func newStreamDecoder(enc encoding.Encoding) *streamDecoder {
+	return &streamDecoder{dec: enc.NewDecoder()}
+}
+
+func (d *streamDecoder) decode(data []byte) string {
+	src := append(d.pending, data...)
+	dst := make([]byte, 3*len(src)+utf8.UTFMax)
+	out := make([]byte, 0, len(dst))
+	for len(src) > 0 {
+		nDst, nSrc, err := d.dec.Transform(dst, src, false)
+		out = append(out, dst[:nDst]...)
+		src = src[nSrc:]
+		if err == nil || err == transform.ErrShortSrc {
+			break
+		}
+		if err != transform.ErrShortDst || nDst+nSrc == 0 {
+			// skip the byte the decoder gave up on
+			out = append(out, string(utf8.RuneError)...)
+			src = src[1:]
+		}
+	}
+	d.pending = append([]byte(nil), src...)
+	return string(out)
+}
+
+// encodeText converts the text of the send area, characters the encoding cannot represent are an error
+func encodeText(index int, text string) ([]byte, error) {
+	return textEncodingAt(index).NewEncoder().Bytes([]byte(text))
+}
+
+func createEncodingCombo() *gtk.ComboBoxText {
+	comb, _ := gtk.ComboBoxTextNew()
+	for _, item := range textEncodings {
+		comb.AppendText(item.name)
+	}
+	comb.SetActive(0)
+	return comb
+}
@@ -5,4 +5,6 @@ go 1.20
 require (
 	github.com/gotk3/gotk3 v0.6.2
 	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7
+	golang.org/x/text v0.22.0
+	google.golang.org/protobuf v1.34.2
 )
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// hex dump layout: "00000000  48 65 6C 6C 6F 20 77 6F  72 6C 64 0A               |Hello world.|"
+const dumpOffsetWidth = 10
+
+// hexString renders bytes as space separated hex
+func hexString(data []byte) string {
+	list := make([]string, len(data))
+	for i, b := range data {
+		list[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(list, " ")
+}
+
+// dumpLayout describes the column positions of a hex dump with width bytes per row
+type dumpLayout struct {
+	width int
+}
+
+// hexCol is the column of the first hex digit of byte c in a row, bytes are grouped by 8
+func (l dumpLayout) hexCol(c int) int {
+	return dumpOffsetWidth + 3*c + c/8
+}
+
+// asciiCol is the column of byte c in the ascii sidebar
+func (l dumpLayout) asciiCol(c int) int {
+	return l.hexCol(l.width-1) + 5 + c
+}
+
+// rowLen is the length of a full row including the newline
+func (l dumpLayout) rowLen() int {
+	return l.asciiCol(l.width) + 2
+}
+
+// byteAt maps a position in the dump text to the byte shown there
+func (l dumpLayout) byteAt(pos int, size int) (int, bool) {
+	row, col := pos/l.rowLen(), pos%l.rowLen()
+	c := -1
+	if col >= l.asciiCol(0) && col < l.asciiCol(l.width) {
+		c = col - l.asciiCol(0)
+	} else {
+		for i := 0; i < l.width; i++ {
+			if col >= l.hexCol(i) && col < l.hexCol(i)+2 {
+				c = i
+				break
+			}
+		}
+	}
+	index := row*l.width + c
+	if c < 0 || index >= size {
+		return 0, false
+	}
+	return index, true
+}
+
+// hexDump formats data with an offset column, grouped hex and an ascii sidebar
+func hexDump(data []byte, width int) string {
+	l := dumpLayout{width: width}
+	var sb strings.Builder
+	for offset := 0; offset < len(data); offset += width {
+		row := data[offset:]
+		if len(row) > width {
+			row = row[:width]
+		}
+		line := []byte(strings.Repeat(" ", l.asciiCol(0)))
+		copy(line, fmt.Sprintf("%08X", offset))
+		for c, b := range row {
+			copy(line[l.hexCol(c):], fmt.Sprintf("%02X", b))
+		}
+		line[l.asciiCol(0)-1] = '|'
+		for _, b := range row {
+			if b >= 0x20 && b < 0x7F {
+				line = append(line, b)
+			} else {
+				line = append(line, '.')
+			}
+		}
+		sb.Write(line)
+		sb.WriteString("|\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+var httpMethods = []string{"GET", "POST", "PUT", "DELETE", "HEAD", "OPTIONS", "PATCH"}
+
+// httpInspector parses the responses read by the TCP client, requests sent from the HTTP page tell it which answer a HEAD
+type httpInspector struct {
+	mu      sync.Mutex
+	enabled bool
+	streams map[net.Conn]*httpStream
+
+	result func(msg *httpMessage)
+	report func(msg string)
+}
+
+// httpStream is the unparsed data and the methods of the requests still waiting for an answer on one connection
+type httpStream struct {
+	buf     []byte
+	methods []string
+}
+
+func newHTTPInspector() *httpInspector {
+	return &httpInspector{streams: map[net.Conn]*httpStream{}}
+}
+
+func (h *httpInspector) setEnabled(enabled bool) {
+	h.mu.Lock()
+	h.enabled = enabled
+	h.streams = map[net.Conn]*httpStream{}
+	h.mu.Unlock()
+}
+
+func (h *httpInspector) reset() {
+	h.mu.Lock()
+	h.streams = map[net.Conn]*httpStream{}
+	h.mu.Unlock()
+}
+
+func (h *httpInspector) stream(conn net.Conn) *httpStream {
+	s := h.streams[conn]
+	if s == nil {
+		s = &httpStream{}
+		h.streams[conn] = s
+	}
+	return s
+}
+
+// sent queues the method of a request written to conns so that their responses are parsed accordingly
+func (h *httpInspector) sent(conns []net.Conn, method string) {
+	h.mu.Lock()
+	if h.enabled {
+		for _, conn := range conns {
+			s := h.stream(conn)
+			s.methods = append(s.methods, method)
+		}
+	}
+	h.mu.Unlock()
+}
+
+func (h *httpInspector) feed(conn net.Conn, data []byte) {
+	h.mu.Lock()
+	if !h.enabled {
+		h.mu.Unlock()
+		return
+	}
+	s := h.stream(conn)
+	s.buf = append(s.buf, data...)
+	msgs, err := s.parse(false)
+	h.mu.Unlock()
+	h.deliver(msgs, err)
+}
+
+// closed ends a response of conn that is delimited by the connection close
+func (h *httpInspector) closed(conn net.Conn) {
+	h.mu.Lock()
+	s := h.streams[conn]
+	delete(h.streams, conn)
+	h.mu.Unlock()
+	if s == nil {
+		return
+	}
+	msgs, err := s.parse(true)
+	if err == nil && len(s.buf) > 0 {
+		err = fmt.Errorf("connection closed with %d bytes of an incomplete response", len(s.buf))
+	}
+	h.deliver(msgs, err)
+}
+
+func (s *httpStream) parse(eof bool) ([]*httpMessage, error) {
+	var msgs []*httpMessage
+	for len(s.buf) > 0 {
+		head := len(s.methods) > 0 && s.methods[0] == "HEAD"
+		msg, n, err := parseHTTPMessage(s.buf, head, eof)
+		if err != nil {
+			s.buf = nil
+			return msgs, err
+		}
+		if msg == nil {
+			break
+		}
+		s.buf = s.buf[n:]
+		if msg.status()/100 != 1 && len(s.methods) > 0 {
+			s.methods = s.methods[1:]
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func (h *httpInspector) deliver(msgs []*httpMessage, err error) {
+	for _, msg := range msgs {
+		h.result(msg)
+	}
+	if err != nil {
+		h.report(err.Error())
+	}
+}
+
+// httpHost is the Host header for the connected peer, default ports are left out
+func (app *NetAssistantApp) httpHost() string {
+	strIP, _ := app.entryIP.GetText()
+	strPort, _ := app.entryPort.GetText()
+	strIP, strPort = strings.TrimSpace(strIP), strings.TrimSpace(strPort)
+	if tls := app.cbTcpTLS.GetActive(); tls && strPort == "443" || !tls && strPort == "80" {
+		if strings.Contains(strIP, ":") {
+			return "[" + strIP + "]"
+		}
+		return strIP
+	}
+	return net.JoinHostPort(strIP, strPort)
+}
+
+func (app *NetAssistantApp) onBtnHttpSend() {
+	method := strings.ToUpper(strings.TrimSpace(app.combHttpMethod.GetActiveText()))
+	if method == "" {
+		method = "GET"
+	}
+	path, _ := app.entryHttpPath.GetText()
+	start, end := app.tbHttpHeaders.GetBounds()
+	text, _ := app.tbHttpHeaders.GetText(start, end, true)
+	headers, err := parseHeaderLines(text)
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
+	start, end = app.tbHttpBody.GetBounds()
+	body, _ := app.tbHttpBody.GetText(start, end, true)
+	req := buildHTTPRequest(method, strings.TrimSpace(path), headers, body, app.httpHost(), app.cbHttpAutoHost.GetActive(), app.cbHttpAutoLength.GetActive())
+	app.httpInspector.sent(app.connList, method)
+	n, err := app.writeData(req, nil)
+	app.updateSendCount(n)
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	}
+}
+
+func (app *NetAssistantApp) onHttpResponse(msg *httpMessage) {
+	text := fmt.Sprintf("=== %s, %d bytes ===\n%s\n", time.Now().Format("15:04:05.000"), msg.size, msg)
+	glib.IdleAdd(func() {
+		app.tbHttpResponse.Insert(app.tbHttpResponse.GetEndIter(), text)
+		app.appendRecvLog(fmt.Sprintf("[HTTP] %s, %d bytes", msg.startLine, msg.size))
+	})
+}
+
+func (app *NetAssistantApp) onBtnHttpRoutesApply() {
+	start, end := app.tbHttpRoutes.GetBounds()
+	text, _ := app.tbHttpRoutes.GetText(start, end, true)
+	if err := app.httpMock.load(text); err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	}
+}
+
+func (app *NetAssistantApp) createHttpPage() *gtk.ScrolledWindow {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	box.SetBorderWidth(10)
+
+	app.httpInspector.result = app.onHttpResponse
+	app.httpInspector.report = func(msg string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog("[HTTP] " + msg)
+		})
+	}
+
+	app.httpMock.report = func(msg string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog("[HTTP] " + msg)
+		})
+	}
+
+	app.cbTcpTLS, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_TCP_TLS))
+	app.cbTcpInsecure, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_TLS_INSECURE))
+
+	reqBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	reqBox.SetBorderWidth(5)
+	app.combHttpMethod, _ = gtk.ComboBoxTextNewWithEntry()
+	for _, method := range httpMethods {
+		app.combHttpMethod.AppendText(method)
+	}
+	app.combHttpMethod.SetActive(0)
+	app.entryHttpPath, _ = gtk.EntryNew()
+	app.entryHttpPath.SetPlaceholderText(getI18nText(IT_WS_PATH))
+	lineBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	lineBox.PackStart(app.combHttpMethod, false, false, 0)
+	lineBox.PackStart(app.entryHttpPath, true, true, 0)
+	labelHeaders, _ := gtk.LabelNew(getI18nText(IT_HTTP_HEADERS))
+	labelHeaders.SetXAlign(0)
+	headerScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	headerScroller.SetSizeRequest(220, 80)
+	tvHeaders, _ := gtk.TextViewNew()
+	tvHeaders.SetMonospace(true)
+	app.tbHttpHeaders, _ = tvHeaders.GetBuffer()
+	headerScroller.Add(tvHeaders)
+	labelBody, _ := gtk.LabelNew(getI18nText(IT_HTTP_BODY))
+	labelBody.SetXAlign(0)
+	bodyScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	bodyScroller.SetSizeRequest(220, 80)
+	tvBody, _ := gtk.TextViewNew()
+	tvBody.SetMonospace(true)
+	app.tbHttpBody, _ = tvBody.GetBuffer()
+	bodyScroller.Add(tvBody)
+	app.cbHttpAutoHost, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_HTTP_AUTO_HOST))
+	app.cbHttpAutoHost.SetActive(true)
+	app.cbHttpAutoLength, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_HTTP_AUTO_LENGTH))
+	app.cbHttpAutoLength.SetActive(true)
+	btnSend, _ := gtk.ButtonNewWithLabel(getI18nText(IT_HTTP_SEND))
+	btnSend.Connect("clicked", app.onBtnHttpSend)
+	reqBox.PackStart(lineBox, false, false, 0)
+	reqBox.PackStart(labelHeaders, false, false, 0)
+	reqBox.PackStart(headerScroller, false, false, 0)
+	reqBox.PackStart(labelBody, false, false, 0)
+	reqBox.PackStart(bodyScroller, false, false, 0)
+	reqBox.PackStart(app.cbHttpAutoHost, false, false, 0)
+	reqBox.PackStart(app.cbHttpAutoLength, false, false, 0)
+	reqBox.PackStart(btnSend, false, false, 0)
+	reqFrame, _ := gtk.FrameNew(getI18nText(IT_HTTP_REQUEST))
+	reqFrame.Add(reqBox)
+
+	respBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	respBox.SetBorderWidth(5)
+	cbParse, _ := gtk.CheckButtonNewWithLabel(getI18nText(IT_HTTP_PARSE))
+	cbParse.Connect("toggled", func() {
+		app.httpInspector.setEnabled(cbParse.GetActive())
+	})
+	respScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	respScroller.SetSizeRequest(220, 200)
+	tvResponse, _ := gtk.TextViewNew()
+	tvResponse.SetMonospace(true)
+	tvResponse.SetEditable(false)
+	app.tbHttpResponse, _ = tvResponse.GetBuffer()
+	respScroller.Add(tvResponse)
+	btnClear, _ := gtk.ButtonNewWithLabel(getI18nText(IT_CLEAR))
+	btnClear.Connect("clicked", func() {
+		app.tbHttpResponse.SetText("")
+	})
+	respBox.PackStart(cbParse, false, false, 0)
+	respBox.PackStart(respScroller, true, true, 0)
+	respBox.PackStart(btnClear, false, false, 0)
+	respFrame, _ := gtk.FrameNew(getI18nText(IT_HTTP_RESPONSE))
+	respFrame.Add(respBox)
+
+	mockBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	mockBox.SetBorderWidth(5)
+	labelMock, _ := gtk.LabelNew(getI18nText(IT_HTTP_MOCK_HINT))
+	labelMock.SetLineWrap(true)
+	labelMock.SetMaxWidthChars(30)
+	labelMock.SetXAlign(0)
+	routeScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	routeScroller.SetSizeRequest(220, 200)
+	tvRoutes, _ := gtk.TextViewNew()
+	tvRoutes.SetMonospace(true)
+	app.tbHttpRoutes, _ = tvRoutes.GetBuffer()
+	app.tbHttpRoutes.SetText(httpRoutesExample)
+	app.httpMock.load(httpRoutesExample)
+	routeScroller.Add(tvRoutes)
+	btnApply, _ := gtk.ButtonNewWithLabel(getI18nText(IT_APPLY))
+	btnApply.Connect("clicked", app.onBtnHttpRoutesApply)
+	mockBox.PackStart(labelMock, false, false, 0)
+	mockBox.PackStart(routeScroller, true, true, 0)
+	mockBox.PackStart(btnApply, false, false, 0)
+	mockFrame, _ := gtk.FrameNew(getI18nText(IT_HTTP_MOCK))
+	mockFrame.Add(mockBox)
+
+	box.PackStart(app.cbTcpTLS, false, false, 0)
+	box.PackStart(app.cbTcpInsecure, false, false, 0)
+	box.PackStart(reqFrame, false, false, 0)
+	box.PackStart(respFrame, true, true, 0)
+	box.PackStart(mockFrame, true, true, 0)
+
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	scroller.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	scroller.Add(box)
+	return scroller
+}
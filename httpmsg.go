@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// heads above this size are refused instead of buffered
+const httpMaxHead = 64 << 10
+
+type httpHeader struct {
+	name  string
+	value string
+}
+
+// httpMessage is a request or response as it was on the wire, body has the transfer coding removed
+type httpMessage struct {
+	startLine string
+	headers   []httpHeader
+	body      []byte
+	chunks    int // number of chunks of a chunked body
+	size      int // bytes on the wire
+}
+
+func (m *httpMessage) get(name string) string {
+	for _, h := range m.headers {
+		if strings.EqualFold(h.name, name) {
+			return h.value
+		}
+	}
+	return ""
+}
+
+// hasToken reports whether a comma separated header such as Connection lists token
+func (m *httpMessage) hasToken(name, token string) bool {
+	for _, h := range m.headers {
+		if !strings.EqualFold(h.name, name) {
+			continue
+		}
+		for _, item := range strings.Split(h.value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// status returns the code of a response start line, 0 when it is not a response
+func (m *httpMessage) status() int {
+	proto, rest, _ := strings.Cut(m.startLine, " ")
+	if !strings.HasPrefix(proto, "HTTP/") || len(rest) < 3 {
+		return 0
+	}
+	code, _ := strconv.Atoi(rest[:3])
+	return code
+}
+
+// parseHeaderLines reads one "Name: value" header per line, keeping order and case
+func parseHeaderLines(text string) ([]httpHeader, error) {
+	var headers []httpHeader
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q, expected Name: value", line)
+		}
+		headers = append(headers, httpHeader{strings.TrimSpace(name), strings.TrimSpace(value)})
+	}
+	return headers, nil
+}
+
+// parseHTTPMessage parses the first message in buf and returns it with the bytes it used.
+// A nil message without error means more data is needed. Responses to HEAD requests
+// have no body, a response without length ends when the connection does, eof says it has.
+func parseHTTPMessage(buf []byte, head, eof bool) (*httpMessage, int, error) {
+	end := bytes.Index(buf, []byte("\r\n\r\n"))
+	sep := 4
+	if lf := bytes.Index(buf, []byte("\n\n")); lf >= 0 && (end < 0 || lf < end) {
+		end, sep = lf, 2
+	}
+	if end < 0 {
+		if len(buf) > httpMaxHead {
+			return nil, 0, errors.New("http: header too large")
+		}
+		return nil, 0, nil
+	}
+	msg := &httpMessage{}
+	lines := strings.Split(string(buf[:end]), "\n")
+	msg.startLine = strings.TrimRight(lines[0], "\r")
+	for _, line := range lines[1:] {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(msg.headers) > 0 {
+			last := &msg.headers[len(msg.headers)-1]
+			last.value += " " + strings.TrimSpace(line)
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, 0, fmt.Errorf("http: malformed header line %q", line)
+		}
+		msg.headers = append(msg.headers, httpHeader{name, strings.TrimSpace(value)})
+	}
+	n := end + sep
+	code := msg.status()
+	isRequest := code == 0
+	switch {
+	case head || code/100 == 1 || code == 204 || code == 304:
+	case msg.hasToken("Transfer-Encoding", "chunked"):
+		body, chunks, used, err := decodeChunked(buf[n:])
+		if err != nil || used == 0 {
+			return nil, 0, err
+		}
+		msg.body, msg.chunks = body, chunks
+		n += used
+	case msg.get("Content-Length") != "":
+		length, err := strconv.Atoi(msg.get("Content-Length"))
+		if err != nil || length < 0 {
+			return nil, 0, fmt.Errorf("http: invalid Content-Length %q", msg.get("Content-Length"))
+		}
+		if len(buf)-n < length {
+			return nil, 0, nil
+		}
+		msg.body = buf[n : n+length]
+		n += length
+	case isRequest:
+	case !eof:
+		return nil, 0, nil
+	default:
+		msg.body = buf[n:]
+		n = len(buf)
+	}
+	msg.body = append([]byte(nil), msg.body...)
+	msg.size = n
+	return msg, n, nil
+}
+
+// decodeChunked removes the chunked transfer coding, used is 0 while the last chunk is missing
+func decodeChunked(buf []byte) (body []byte, chunks, used int, err error) {
+	pos := 0
+	for {
+		eol := bytes.IndexByte(buf[pos:], '\n')
+		if eol < 0 {
+			return nil, 0, 0, nil
+		}
+		line := strings.TrimSpace(string(buf[pos : pos+eol]))
+		line, _, _ = strings.Cut(line, ";") // chunk extensions
+		size, err := strconv.ParseUint(strings.TrimSpace(line), 16, 31)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("http: invalid chunk size %q", line)
+		}
+		pos += eol + 1
+		if size == 0 {
+			break
+		}
+		if len(buf)-pos < int(size)+1 {
+			return nil, 0, 0, nil
+		}
+		body = append(body, buf[pos:pos+int(size)]...)
+		chunks++
+		pos += int(size)
+		if bytes.HasPrefix(buf[pos:], []byte("\r\n")) {
+			pos += 2
+		} else if buf[pos] == '\n' {
+			pos++
+		} else if len(buf)-pos >= 2 {
+			return nil, 0, 0, errors.New("http: missing CRLF after chunk")
+		} else {
+			return nil, 0, 0, nil
+		}
+	}
+	// trailer fields up to an empty line
+	for {
+		eol := bytes.IndexByte(buf[pos:], '\n')
+		if eol < 0 {
+			return nil, 0, 0, nil
+		}
+		line := strings.TrimSpace(string(buf[pos : pos+eol]))
+		pos += eol + 1
+		if line == "" {
+			return body, chunks, pos, nil
+		}
+	}
+}
+
+// decodeContent removes a gzip or deflate content coding
+func decodeContent(encoding string, body []byte) ([]byte, error) {
+	var reader io.Reader
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		reader = gz
+	case "deflate":
+		// deflate is meant to be zlib wrapped, some servers send the raw stream
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader = flate.NewReader(bytes.NewReader(body))
+		} else {
+			reader = zr
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+	return io.ReadAll(reader)
+}
+
+// String shows the start line, headers and the decoded body, binary bodies as a hex dump
+func (m *httpMessage) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.startLine + "\n")
+	for _, h := range m.headers {
+		sb.WriteString(h.name + ": " + h.value + "\n")
+	}
+	if len(m.body) == 0 {
+		return sb.String()
+	}
+	body, err := decodeContent(m.get("Content-Encoding"), m.body)
+	info := fmt.Sprintf("%d bytes", len(m.body))
+	if m.chunks > 0 {
+		info += fmt.Sprintf(" in %d chunks", m.chunks)
+	}
+	if err != nil {
+		info += ", " + err.Error()
+		body = m.body
+	} else if m.get("Content-Encoding") != "" {
+		info += fmt.Sprintf(", %s decoded to %d bytes", m.get("Content-Encoding"), len(body))
+	}
+	fmt.Fprintf(&sb, "\n--- body, %s ---\n", info)
+	if utf8.Valid(body) {
+		sb.Write(body)
+		if !bytes.HasSuffix(body, []byte("\n")) {
+			sb.WriteString("\n")
+		}
+	} else {
+		sb.WriteString(hexDump(body, 16))
+	}
+	return sb.String()
+}
+
+// buildHTTPRequest assembles a request, host and Content-Length are added unless given in headers
+func buildHTTPRequest(method, path string, headers []httpHeader, body, host string, autoHost, autoLength bool) []byte {
+	if path == "" {
+		path = "/"
+	}
+	msg := &httpMessage{headers: headers}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s HTTP/1.1\r\n", method, path)
+	if autoHost && msg.get("Host") == "" {
+		sb.WriteString("Host: " + host + "\r\n")
+	}
+	for _, h := range headers {
+		sb.WriteString(h.name + ": " + h.value + "\r\n")
+	}
+	if autoLength && msg.get("Content-Length") == "" && msg.get("Transfer-Encoding") == "" {
+		switch method {
+		case "POST", "PUT", "PATCH":
+			fmt.Fprintf(&sb, "Content-Length: %d\r\n", len(body))
+		default:
+			if body != "" {
+				fmt.Fprintf(&sb, "Content-Length: %d\r\n", len(body))
+			}
+		}
+	}
+	sb.WriteString("\r\n")
+	sb.WriteString(body)
+	return []byte(sb.String())
+}
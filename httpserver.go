@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const httpRoutesExample = `# METHOD PATH STATUS, then header lines, an empty line and the body, --- ends a route
+# the first matching route answers, * matches any method, any path or one path segment, a body of @/some/file is read from the file
+GET /status 200
+Content-Type: application/json
+
+{"status": "ok"}
+---
+POST /api/* 201
+Location: /api/1
+---
+* * 404
+Content-Type: text/plain
+
+not found
+`
+
+type httpRoute struct {
+	method  string
+	pattern string
+	status  int
+	headers []httpHeader
+	body    []byte
+	file    string // read for every request when set
+}
+
+func (r *httpRoute) matches(method, target string) bool {
+	// GET routes answer HEAD too, without the body
+	if r.method != "*" && !strings.EqualFold(r.method, method) && !(method == "HEAD" && strings.EqualFold(r.method, "GET")) {
+		return false
+	}
+	if r.pattern == "*" {
+		return true
+	}
+	ok, _ := path.Match(r.pattern, target)
+	return ok
+}
+
+// parseHTTPRoutes reads the route table of the mock server
+func parseHTTPRoutes(text string) ([]httpRoute, error) {
+	var routes []httpRoute
+	var route *httpRoute
+	var body []string
+	inBody := false
+	finish := func() {
+		if route == nil {
+			return
+		}
+		content := strings.TrimRight(strings.Join(body, "\n"), "\n")
+		if file, ok := strings.CutPrefix(strings.TrimSpace(content), "@"); ok && !strings.Contains(content, "\n") {
+			route.file = strings.TrimSpace(file)
+		} else {
+			route.body = []byte(content)
+		}
+		routes = append(routes, *route)
+		route, body, inBody = nil, nil, false
+	}
+	for lineNo, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.TrimSpace(line) == "---":
+			finish()
+		case inBody:
+			body = append(body, line)
+		case route == nil:
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("line %d: expected METHOD PATH STATUS", lineNo+1)
+			}
+			status, err := strconv.Atoi(fields[2])
+			if err != nil || status < 100 || status > 999 {
+				return nil, fmt.Errorf("line %d: invalid status %q", lineNo+1, fields[2])
+			}
+			if _, err := path.Match(fields[1], ""); err != nil {
+				return nil, fmt.Errorf("line %d: invalid path pattern %q", lineNo+1, fields[1])
+			}
+			route = &httpRoute{method: fields[0], pattern: fields[1], status: status}
+		case strings.TrimSpace(line) == "":
+			inBody = true
+		default:
+			name, value, ok := strings.Cut(line, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				return nil, fmt.Errorf("line %d: invalid header %q, expected Name: value", lineNo+1, line)
+			}
+			route.headers = append(route.headers, httpHeader{strings.TrimSpace(name), strings.TrimSpace(value)})
+		}
+	}
+	finish()
+	return routes, nil
+}
+
+// httpMock answers HTTP requests of the TCP server connections from the route table
+type httpMock struct {
+	mu      sync.Mutex
+	routes  []httpRoute
+	streams map[net.Conn][]byte
+
+	report func(msg string)
+}
+
+func newHTTPMock() *httpMock {
+	return &httpMock{streams: map[net.Conn][]byte{}}
+}
+
+func (m *httpMock) load(text string) error {
+	routes, err := parseHTTPRoutes(text)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.routes = routes
+	m.mu.Unlock()
+	return nil
+}
+
+// respond builds the response to req, keepAlive is false when the connection ends after it
+func (m *httpMock) respond(req *httpMessage) (resp []byte, status int, keepAlive bool) {
+	method, rest, _ := strings.Cut(req.startLine, " ")
+	target, proto, _ := strings.Cut(rest, " ")
+	target, _, _ = strings.Cut(target, "?")
+	if proto == "HTTP/1.0" {
+		keepAlive = req.hasToken("Connection", "keep-alive")
+	} else {
+		keepAlive = !req.hasToken("Connection", "close")
+	}
+
+	var route *httpRoute
+	m.mu.Lock()
+	for i := range m.routes {
+		if m.routes[i].matches(method, target) {
+			route = &m.routes[i]
+			break
+		}
+	}
+	m.mu.Unlock()
+	res := &httpMessage{}
+	var body []byte
+	switch {
+	case route == nil:
+		status, body = http.StatusNotFound, []byte("no route for "+method+" "+target+"\n")
+	case route.file != "":
+		data, err := os.ReadFile(route.file)
+		if err != nil {
+			status, body = http.StatusInternalServerError, []byte(err.Error()+"\n")
+		} else {
+			status, body, res.headers = route.status, data, route.headers
+		}
+	default:
+		status, body, res.headers = route.status, route.body, route.headers
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "HTTP/1.1 %d %s\r\n", status, http.StatusText(status))
+	for _, h := range res.headers {
+		sb.WriteString(h.name + ": " + h.value + "\r\n")
+	}
+	if res.get("Content-Length") == "" && res.get("Transfer-Encoding") == "" {
+		fmt.Fprintf(&sb, "Content-Length: %d\r\n", len(body))
+	}
+	if res.hasToken("Connection", "close") {
+		keepAlive = false
+	} else if !keepAlive {
+		sb.WriteString("Connection: close\r\n")
+	} else if proto == "HTTP/1.0" {
+		sb.WriteString("Connection: keep-alive\r\n")
+	}
+	sb.WriteString("\r\n")
+	if method != "HEAD" {
+		sb.Write(body)
+	}
+	return []byte(sb.String()), status, keepAlive
+}
+
+// serve answers the complete requests buffered for conn, it returns false when the connection should be closed
+func (m *httpMock) serve(conn net.Conn, data []byte, write func(reply []byte)) bool {
+	m.mu.Lock()
+	buf := append(m.streams[conn], data...)
+	m.mu.Unlock()
+	peer := conn.RemoteAddr().String()
+	for len(buf) > 0 {
+		req, n, err := parseHTTPMessage(buf, false, false)
+		if err != nil {
+			m.report(fmt.Sprintf("%s %s", peer, err))
+			reply := "HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"
+			write([]byte(reply))
+			m.forget(conn)
+			return false
+		}
+		if req == nil {
+			break
+		}
+		buf = buf[n:]
+		resp, status, keepAlive := m.respond(req)
+		m.report(fmt.Sprintf("%s -> %d %s\n%s", peer, status, http.StatusText(status), strings.TrimRight(req.String(), "\n")))
+		write(resp)
+		if !keepAlive {
+			m.forget(conn)
+			return false
+		}
+	}
+	m.mu.Lock()
+	m.streams[conn] = append([]byte(nil), buf...)
+	m.mu.Unlock()
+	return true
+}
+
+func (m *httpMock) forget(conn net.Conn) {
+	m.mu.Lock()
+	delete(m.streams, conn)
+	m.mu.Unlock()
+}
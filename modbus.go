@@ -0,0 +1,881 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// modbus function codes
+const (
+	mbReadCoils              = 0x01
+	mbReadDiscreteInputs     = 0x02
+	mbReadHoldingRegisters   = 0x03
+	mbReadInputRegisters     = 0x04
+	mbWriteSingleCoil        = 0x05
+	mbWriteSingleRegister    = 0x06
+	mbWriteMultipleCoils     = 0x0F
+	mbWriteMultipleRegisters = 0x10
+)
+
+// modbus exception codes
+const (
+	mbIllegalFunction = 0x01
+	mbIllegalAddress  = 0x02
+	mbIllegalValue    = 0x03
+)
+
+var modbusExceptions = map[byte]string{
+	mbIllegalFunction: "illegal function",
+	mbIllegalAddress:  "illegal data address",
+	mbIllegalValue:    "illegal data value",
+	0x04:              "server device failure",
+	0x05:              "acknowledge",
+	0x06:              "server device busy",
+	0x0A:              "gateway path unavailable",
+	0x0B:              "gateway target failed to respond",
+}
+
+// functions of the master, in combo box order
+var modbusFunctions = []struct {
+	code byte
+	key  string
+}{
+	{mbReadCoils, IT_MB_READ_COILS},
+	{mbReadDiscreteInputs, IT_MB_READ_DISCRETE},
+	{mbReadHoldingRegisters, IT_MB_READ_HOLDING},
+	{mbReadInputRegisters, IT_MB_READ_INPUT},
+	{mbWriteSingleCoil, IT_MB_WRITE_COIL},
+	{mbWriteSingleRegister, IT_MB_WRITE_REGISTER},
+	{mbWriteMultipleCoils, IT_MB_WRITE_COILS},
+	{mbWriteMultipleRegisters, IT_MB_WRITE_REGISTERS},
+}
+
+const (
+	mbapHeaderSize = 7
+	modbusMaxFrame = 260 // MBAP header plus the largest PDU
+	modbusTimeout  = 3 * time.Second
+)
+
+// register tables of the slave, the names are used by the register map text
+const (
+	tableCoils = iota
+	tableDiscrete
+	tableHolding
+	tableInput
+)
+
+var modbusTableNames = []string{"coil", "discrete", "holding", "input"}
+
+const modbusMapExample = `# table address = value, value, ...
+# tables: coil discrete holding input
+holding 0 = 1234, 0x10, -1
+coil 0 = 1, 0, 1
+`
+
+// modbusRegisters is the data model of the slave, every address of every table exists
+type modbusRegisters struct {
+	mu       sync.Mutex
+	coils    [65536]bool
+	discrete [65536]bool
+	holding  [65536]uint16
+	input    [65536]uint16
+}
+
+func modbusException(function, code byte) []byte {
+	return []byte{function | 0x80, code}
+}
+
+// checkRange validates a quantity and the address range it covers
+func checkRange(address, quantity, max int) byte {
+	if quantity < 1 || quantity > max {
+		return mbIllegalValue
+	}
+	if address+quantity > 65536 {
+		return mbIllegalAddress
+	}
+	return 0
+}
+
+func packBits(bits []bool) []byte {
+	data := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			data[i/8] |= 1 << (i % 8)
+		}
+	}
+	return data
+}
+
+// handle executes a request PDU and returns the response PDU
+func (r *modbusRegisters) handle(pdu []byte) []byte {
+	if len(pdu) == 0 {
+		return nil
+	}
+	function := pdu[0]
+	switch function {
+	case mbReadCoils, mbReadDiscreteInputs, mbReadHoldingRegisters, mbReadInputRegisters,
+		mbWriteSingleCoil, mbWriteSingleRegister, mbWriteMultipleCoils, mbWriteMultipleRegisters:
+	default:
+		return modbusException(function, mbIllegalFunction)
+	}
+	// every supported request starts with an address and a quantity or value, the writes of many check their data below
+	if len(pdu) < 5 {
+		return modbusException(function, mbIllegalValue)
+	}
+	address := int(binary.BigEndian.Uint16(pdu[1:]))
+	value := binary.BigEndian.Uint16(pdu[3:])
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch function {
+	case mbReadCoils, mbReadDiscreteInputs:
+		if code := checkRange(address, int(value), 2000); code != 0 {
+			return modbusException(function, code)
+		}
+		table := r.coils[:]
+		if function == mbReadDiscreteInputs {
+			table = r.discrete[:]
+		}
+		data := packBits(table[address : address+int(value)])
+		return append([]byte{function, byte(len(data))}, data...)
+	case mbReadHoldingRegisters, mbReadInputRegisters:
+		if code := checkRange(address, int(value), 125); code != 0 {
+			return modbusException(function, code)
+		}
+		table := r.holding[:]
+		if function == mbReadInputRegisters {
+			table = r.input[:]
+		}
+		resp := []byte{function, byte(value * 2)}
+		for _, reg := range table[address : address+int(value)] {
+			resp = binary.BigEndian.AppendUint16(resp, reg)
+		}
+		return resp
+	case mbWriteSingleCoil:
+		if value != 0xFF00 && value != 0 {
+			return modbusException(function, mbIllegalValue)
+		}
+		r.coils[address] = value == 0xFF00
+		return append([]byte(nil), pdu[:5]...)
+	case mbWriteSingleRegister:
+		r.holding[address] = value
+		return append([]byte(nil), pdu[:5]...)
+	case mbWriteMultipleCoils, mbWriteMultipleRegisters:
+		max, size := 1968, (int(value)+7)/8
+		if function == mbWriteMultipleRegisters {
+			max, size = 123, int(value)*2
+		}
+		if code := checkRange(address, int(value), max); code != 0 {
+			return modbusException(function, code)
+		}
+		if len(pdu) < 6 || int(pdu[5]) != size || len(pdu) < 6+size {
+			return modbusException(function, mbIllegalValue)
+		}
+		data := pdu[6 : 6+size]
+		for i := 0; i < int(value); i++ {
+			if function == mbWriteMultipleCoils {
+				r.coils[address+i] = data[i/8]&(1<<(i%8)) != 0
+			} else {
+				r.holding[address+i] = binary.BigEndian.Uint16(data[i*2:])
+			}
+		}
+		return append([]byte(nil), pdu[:5]...)
+	}
+	return modbusException(function, mbIllegalFunction)
+}
+
+// load replaces the registers with a register map text, see modbusMapExample
+func (r *modbusRegisters) load(text string) error {
+	type entry struct {
+		table, address int
+		values         []int64
+	}
+	var entries []entry
+	for lineNo, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		left, right, ok := strings.Cut(line, "=")
+		fields := strings.Fields(left)
+		if !ok || len(fields) != 2 {
+			return fmt.Errorf("line %d: expected table address = values", lineNo+1)
+		}
+		table := -1
+		for i, name := range modbusTableNames {
+			if fields[0] == name {
+				table = i
+			}
+		}
+		if table < 0 {
+			return fmt.Errorf("line %d: unknown table %q", lineNo+1, fields[0])
+		}
+		address, err := parseNumber(fields[1])
+		if err != nil || address < 0 || address > 65535 {
+			return fmt.Errorf("line %d: invalid address %q", lineNo+1, fields[1])
+		}
+		e := entry{table: table, address: int(address)}
+		for _, s := range strings.Split(right, ",") {
+			v, err := parseNumber(s)
+			if err != nil {
+				return fmt.Errorf("line %d: %s", lineNo+1, err)
+			}
+			if v < -32768 || v > 65535 || (table < tableHolding && v != 0 && v != 1) {
+				return fmt.Errorf("line %d: value %d out of range", lineNo+1, v)
+			}
+			e.values = append(e.values, v)
+		}
+		if e.address+len(e.values) > 65536 {
+			return fmt.Errorf("line %d: values beyond address 65535", lineNo+1)
+		}
+		entries = append(entries, e)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.coils, r.discrete = [65536]bool{}, [65536]bool{}
+	r.holding, r.input = [65536]uint16{}, [65536]uint16{}
+	for _, e := range entries {
+		for i, v := range e.values {
+			switch e.table {
+			case tableCoils:
+				r.coils[e.address+i] = v == 1
+			case tableDiscrete:
+				r.discrete[e.address+i] = v == 1
+			case tableHolding:
+				r.holding[e.address+i] = uint16(v)
+			case tableInput:
+				r.input[e.address+i] = uint16(v)
+			}
+		}
+	}
+	return nil
+}
+
+// String dumps the non zero values in the register map format, consecutive values share a line
+func (r *modbusRegisters) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sb strings.Builder
+	for table, name := range modbusTableNames {
+		value := func(address int) int {
+			switch table {
+			case tableCoils:
+				return boolInt(r.coils[address])
+			case tableDiscrete:
+				return boolInt(r.discrete[address])
+			case tableHolding:
+				return int(r.holding[address])
+			}
+			return int(r.input[address])
+		}
+		for address := 0; address < 65536; address++ {
+			if value(address) == 0 {
+				continue
+			}
+			var values []string
+			start := address
+			for ; address < 65536 && value(address) != 0 && len(values) < 8; address++ {
+				values = append(values, fmt.Sprint(value(address)))
+			}
+			address--
+			fmt.Fprintf(&sb, "%s %d = %s\n", name, start, strings.Join(values, ", "))
+		}
+	}
+	return sb.String()
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func mbapFrame(tid uint16, unit byte, pdu []byte) []byte {
+	frame := make([]byte, mbapHeaderSize, mbapHeaderSize+len(pdu))
+	binary.BigEndian.PutUint16(frame, tid)
+	binary.BigEndian.PutUint16(frame[4:], uint16(len(pdu)+1))
+	frame[6] = unit
+	return append(frame, pdu...)
+}
+
+// nextMBAP splits the first complete frame off buf, a buffer that is not modbus is dropped
+func nextMBAP(buf []byte) (frame, rest []byte) {
+	if len(buf) < mbapHeaderSize {
+		return nil, buf
+	}
+	size := int(binary.BigEndian.Uint16(buf[4:]))
+	if binary.BigEndian.Uint16(buf[2:]) != 0 || size < 2 || size+6 > modbusMaxFrame {
+		return nil, nil
+	}
+	if len(buf) < size+6 {
+		return nil, buf
+	}
+	return buf[:size+6], buf[size+6:]
+}
+
+// modbusSlave answers requests from the register map, TCP streams are reassembled per connection
+type modbusSlave struct {
+	regs       *modbusRegisters
+	mu         sync.Mutex
+	framing    int
+	silence    time.Duration
+	streams    map[net.Conn][]byte
+	rtuStreams map[net.Conn]*rtuStream
+
+	report func(msg string)
+}
+
+func newModbusSlave() *modbusSlave {
+	return &modbusSlave{
+		regs:       &modbusRegisters{},
+		silence:    rtuSilence,
+		streams:    map[net.Conn][]byte{},
+		rtuStreams: map[net.Conn]*rtuStream{},
+		report:     func(string) {},
+	}
+}
+
+func (s *modbusSlave) setFraming(framing int, silence time.Duration) {
+	s.mu.Lock()
+	s.framing, s.silence = framing, silence
+	s.streams = map[net.Conn][]byte{}
+	s.rtuStreams = map[net.Conn]*rtuStream{}
+	s.mu.Unlock()
+}
+
+// answer executes a request PDU and reports it with its outcome
+func (s *modbusSlave) answer(unit byte, pdu []byte) []byte {
+	resp := s.regs.handle(pdu)
+	if resp != nil {
+		s.report(newModbusRequest(unit, pdu).String() + ": " + modbusOutcome(resp))
+	}
+	return resp
+}
+
+// serve answers the complete requests received so far, RTU requests ended by silence are answered later
+func (s *modbusSlave) serve(conn net.Conn, data []byte, isUDP bool, write func(reply []byte)) {
+	s.mu.Lock()
+	if s.framing == framingRTU {
+		if isUDP {
+			s.mu.Unlock()
+			s.serveRTU(data, write)
+			return
+		}
+		stream := s.rtuStreams[conn]
+		if stream == nil {
+			stream = &rtuStream{}
+			s.rtuStreams[conn] = stream
+		}
+		silence := s.silence
+		s.mu.Unlock()
+		frames := stream.feed(data, true, silence, func(frame []byte) {
+			s.serveRTU(frame, write)
+		})
+		for _, frame := range frames {
+			s.serveRTU(frame, write)
+		}
+		return
+	}
+
+	buf := data
+	if !isUDP {
+		buf = append(s.streams[conn], data...)
+	}
+	var reply []byte
+	for {
+		var frame []byte
+		frame, buf = nextMBAP(buf)
+		if frame == nil {
+			break
+		}
+		if resp := s.answer(frame[6], frame[mbapHeaderSize:]); resp != nil {
+			reply = append(reply, mbapFrame(binary.BigEndian.Uint16(frame), frame[6], resp)...)
+		}
+	}
+	if !isUDP {
+		s.streams[conn] = append([]byte(nil), buf...)
+	}
+	s.mu.Unlock()
+	if len(reply) > 0 {
+		write(reply)
+	}
+}
+
+// serveRTU answers one RTU frame, frames with a bad CRC and broadcasts to unit 0 get no response
+func (s *modbusSlave) serveRTU(frame []byte, write func(reply []byte)) {
+	unit, pdu, ok := rtuCheck(frame)
+	if !ok {
+		s.report("RTU frame with bad CRC: " + hexString(frame))
+		return
+	}
+	if resp := s.answer(unit, pdu); resp != nil && unit != 0 {
+		write(rtuFrame(unit, resp))
+	}
+}
+
+// forget drops the stream buffers of a closed connection
+func (s *modbusSlave) forget(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.streams, conn)
+	delete(s.rtuStreams, conn)
+	s.mu.Unlock()
+}
+
+// modbusRequest is a request of the master waiting for its response
+type modbusRequest struct {
+	unit     byte
+	function byte
+	address  uint16
+	quantity uint16
+	sent     time.Time
+	timer    *time.Timer
+}
+
+func newModbusRequest(unit byte, pdu []byte) *modbusRequest {
+	req := &modbusRequest{unit: unit, function: pdu[0], sent: time.Now()}
+	if len(pdu) >= 5 {
+		req.address = binary.BigEndian.Uint16(pdu[1:])
+		req.quantity = binary.BigEndian.Uint16(pdu[3:])
+	}
+	return req
+}
+
+// target describes the addresses of the request
+func (req *modbusRequest) target() string {
+	if req.function == mbWriteSingleCoil || req.function == mbWriteSingleRegister {
+		return fmt.Sprintf("@%d", req.address)
+	}
+	return fmt.Sprintf("@%d x%d", req.address, req.quantity)
+}
+
+func (req *modbusRequest) String() string {
+	return fmt.Sprintf("unit %d %s %s", req.unit, modbusFunctionName(req.function), req.target())
+}
+
+// modbusOutcome summarizes a response PDU
+func modbusOutcome(resp []byte) string {
+	if resp[0]&0x80 == 0 {
+		return "ok"
+	}
+	if len(resp) < 2 {
+		return "short exception"
+	}
+	name := modbusExceptions[resp[1]]
+	if name == "" {
+		name = "unknown"
+	}
+	return fmt.Sprintf("exception 0x%02X %s", resp[1], name)
+}
+
+// buildModbusPDU encodes a master request, values are used by the write functions
+func buildModbusPDU(function byte, address, quantity int, values []int64) ([]byte, error) {
+	if address < 0 || address > 65535 {
+		return nil, fmt.Errorf("invalid address %d", address)
+	}
+	pdu := []byte{function}
+	pdu = binary.BigEndian.AppendUint16(pdu, uint16(address))
+	switch function {
+	case mbReadCoils, mbReadDiscreteInputs, mbReadHoldingRegisters, mbReadInputRegisters:
+		max := 2000
+		if function == mbReadHoldingRegisters || function == mbReadInputRegisters {
+			max = 125
+		}
+		if checkRange(address, quantity, max) != 0 {
+			return nil, fmt.Errorf("quantity must be 1 to %d within the address space", max)
+		}
+		return binary.BigEndian.AppendUint16(pdu, uint16(quantity)), nil
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no values to write")
+	}
+	for _, v := range values {
+		isCoil := function == mbWriteSingleCoil || function == mbWriteMultipleCoils
+		if (isCoil && v != 0 && v != 1) || v < -32768 || v > 65535 {
+			return nil, fmt.Errorf("value %d out of range", v)
+		}
+	}
+	switch function {
+	case mbWriteSingleCoil:
+		if values[0] == 1 {
+			return binary.BigEndian.AppendUint16(pdu, 0xFF00), nil
+		}
+		return binary.BigEndian.AppendUint16(pdu, 0), nil
+	case mbWriteSingleRegister:
+		return binary.BigEndian.AppendUint16(pdu, uint16(values[0])), nil
+	case mbWriteMultipleCoils:
+		if checkRange(address, len(values), 1968) != 0 {
+			return nil, errors.New("1 to 1968 coils within the address space can be written")
+		}
+		bits := make([]bool, len(values))
+		for i, v := range values {
+			bits[i] = v == 1
+		}
+		data := packBits(bits)
+		pdu = binary.BigEndian.AppendUint16(pdu, uint16(len(values)))
+		return append(append(pdu, byte(len(data))), data...), nil
+	case mbWriteMultipleRegisters:
+		if checkRange(address, len(values), 123) != 0 {
+			return nil, errors.New("1 to 123 registers within the address space can be written")
+		}
+		pdu = binary.BigEndian.AppendUint16(pdu, uint16(len(values)))
+		pdu = append(pdu, byte(len(values)*2))
+		for _, v := range values {
+			pdu = binary.BigEndian.AppendUint16(pdu, uint16(v))
+		}
+		return pdu, nil
+	}
+	return nil, fmt.Errorf("unsupported function 0x%02X", function)
+}
+
+func modbusFunctionName(function byte) string {
+	for _, f := range modbusFunctions {
+		if f.code == function&0x7F {
+			return getI18nText(f.key)
+		}
+	}
+	return fmt.Sprintf("function 0x%02X", function&0x7F)
+}
+
+// decodeModbusResponse renders a response PDU as a register table
+func decodeModbusResponse(req *modbusRequest, pdu []byte) string {
+	var sb strings.Builder
+	if pdu[0] == req.function|0x80 {
+		return modbusOutcome(pdu) + "\n"
+	}
+	if pdu[0] != req.function {
+		fmt.Fprintf(&sb, "unexpected function 0x%02X: %s\n", pdu[0], hexString(pdu))
+		return sb.String()
+	}
+	switch req.function {
+	case mbReadCoils, mbReadDiscreteInputs:
+		if len(pdu) < 2 || len(pdu) < 2+int(pdu[1]) || int(pdu[1])*8 < int(req.quantity) {
+			fmt.Fprintf(&sb, "short response: %s\n", hexString(pdu))
+			break
+		}
+		fmt.Fprintf(&sb, "%-8s %s\n", "address", "value")
+		for i := 0; i < int(req.quantity); i++ {
+			bit := pdu[2+i/8] >> (i % 8) & 1
+			fmt.Fprintf(&sb, "%-8d %d\n", int(req.address)+i, bit)
+		}
+	case mbReadHoldingRegisters, mbReadInputRegisters:
+		if len(pdu) < 2 || len(pdu) < 2+int(pdu[1]) || int(pdu[1]) < int(req.quantity)*2 {
+			fmt.Fprintf(&sb, "short response: %s\n", hexString(pdu))
+			break
+		}
+		fmt.Fprintf(&sb, "%-8s %-6s %-7s %s\n", "address", "hex", "uint16", "int16")
+		for i := 0; i < int(req.quantity); i++ {
+			reg := binary.BigEndian.Uint16(pdu[2+i*2:])
+			fmt.Fprintf(&sb, "%-8d %04X   %-7d %d\n", int(req.address)+i, reg, reg, int16(reg))
+		}
+	default:
+		if len(pdu) < 5 {
+			fmt.Fprintf(&sb, "short response: %s\n", hexString(pdu))
+			break
+		}
+		address, value := binary.BigEndian.Uint16(pdu[1:]), binary.BigEndian.Uint16(pdu[3:])
+		if req.function == mbWriteMultipleCoils || req.function == mbWriteMultipleRegisters {
+			fmt.Fprintf(&sb, "%d written from address %d\n", value, address)
+		} else {
+			fmt.Fprintf(&sb, "address %d = 0x%04X written\n", address, value)
+		}
+	}
+	return sb.String()
+}
+
+// modbusMaster assigns transaction IDs to requests and matches the responses
+type modbusMaster struct {
+	mu      sync.Mutex
+	framing int
+	silence time.Duration
+	nextTID uint16
+	pending map[uint16]*modbusRequest
+	buf     []byte
+	rtu     rtuStream
+
+	result func(title, table string)
+}
+
+func newModbusMaster() *modbusMaster {
+	return &modbusMaster{silence: rtuSilence, pending: map[uint16]*modbusRequest{}}
+}
+
+func (m *modbusMaster) setFraming(framing int, silence time.Duration) {
+	m.mu.Lock()
+	m.framing, m.silence = framing, silence
+	m.buf = nil
+	m.mu.Unlock()
+	m.rtu.reset()
+}
+
+// request frames a PDU with the next transaction ID and waits for its response in the background,
+// RTU frames carry no ID so their responses are matched by unit and function
+func (m *modbusMaster) request(unit byte, pdu []byte) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextTID++
+	tid := m.nextTID
+	req := newModbusRequest(unit, pdu)
+	req.timer = time.AfterFunc(modbusTimeout, func() {
+		m.mu.Lock()
+		_, ok := m.pending[tid]
+		delete(m.pending, tid)
+		m.mu.Unlock()
+		if ok {
+			m.result(fmt.Sprintf("#%d %s: timeout", tid, req), "")
+		}
+	})
+	m.pending[tid] = req
+	if m.framing == framingRTU {
+		return rtuFrame(unit, pdu)
+	}
+	return mbapFrame(tid, unit, pdu)
+}
+
+// respond completes a pending request, m.mu must be held
+func (m *modbusMaster) respond(tid uint16, req *modbusRequest, pdu []byte) {
+	req.timer.Stop()
+	delete(m.pending, tid)
+	title := fmt.Sprintf("#%d %s  %.1f ms", tid, req, durationMs(time.Since(req.sent)))
+	m.result(title, decodeModbusResponse(req, pdu))
+}
+
+// feed matches received data against the pending requests, nothing is kept while none are pending
+func (m *modbusMaster) feed(data []byte) {
+	m.mu.Lock()
+	if len(m.pending) == 0 {
+		m.buf = nil
+		m.mu.Unlock()
+		m.rtu.reset()
+		return
+	}
+	if m.framing == framingRTU {
+		silence := m.silence
+		m.mu.Unlock()
+		for _, frame := range m.rtu.feed(data, false, silence, m.onRTUFrame) {
+			m.onRTUFrame(frame)
+		}
+		return
+	}
+	defer m.mu.Unlock()
+	m.buf = append(m.buf, data...)
+	for {
+		var frame []byte
+		frame, m.buf = nextMBAP(m.buf)
+		if frame == nil {
+			break
+		}
+		tid := binary.BigEndian.Uint16(frame)
+		if req := m.pending[tid]; req != nil {
+			m.respond(tid, req, frame[mbapHeaderSize:])
+		}
+	}
+	m.buf = append([]byte(nil), m.buf...)
+}
+
+// onRTUFrame matches an RTU response to the oldest pending request of its unit and function
+func (m *modbusMaster) onRTUFrame(frame []byte) {
+	unit, pdu, ok := rtuCheck(frame)
+	if !ok {
+		m.result("RTU frame with bad CRC: "+hexString(frame), "")
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var tid uint16
+	var req *modbusRequest
+	for id, item := range m.pending {
+		if item.unit == unit && item.function == pdu[0]&0x7F && (req == nil || id < tid) {
+			tid, req = id, item
+		}
+	}
+	if req != nil {
+		m.respond(tid, req, pdu)
+	}
+}
+
+// parseModbusValues reads a comma or space separated list of values
+func parseModbusValues(s string) ([]int64, error) {
+	var values []int64
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		v, err := parseNumber(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func (app *NetAssistantApp) onBtnModbusSend() {
+	strUnit, _ := app.entryMbUnit.GetText()
+	strAddress, _ := app.entryMbAddress.GetText()
+	strQuantity, _ := app.entryMbQuantity.GetText()
+	strValues, _ := app.entryMbValues.GetText()
+	unit, quantity := int64(1), int64(1)
+	var address int64
+	var err error
+	if strings.TrimSpace(strUnit) != "" {
+		unit, err = parseNumber(strUnit)
+	}
+	if err == nil && (unit < 0 || unit > 255) {
+		err = fmt.Errorf("invalid unit id %d", unit)
+	}
+	if err == nil && strings.TrimSpace(strAddress) != "" {
+		address, err = parseNumber(strAddress)
+	}
+	if err == nil && strings.TrimSpace(strQuantity) != "" {
+		quantity, err = parseNumber(strQuantity)
+	}
+	var values []int64
+	if err == nil {
+		values, err = parseModbusValues(strValues)
+	}
+	var pdu []byte
+	if err == nil {
+		function := modbusFunctions[app.combMbFunction.GetActive()].code
+		pdu, err = buildModbusPDU(function, int(address), int(quantity), values)
+	}
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
+	udpTarget, err := app.udpTargetAddr()
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
+	n, err := app.writeData(app.modbusMaster.request(byte(unit), pdu), udpTarget)
+	app.updateSendCount(n)
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	}
+}
+
+// onModbusFramingChanged switches master and slave between MBAP and RTU framing
+func (app *NetAssistantApp) onModbusFramingChanged() {
+	strSilence, _ := app.entryMbSilence.GetText()
+	ms, err := parseLimit(strSilence)
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">invalid silence %q</span>`, glib.MarkupEscapeText(strings.TrimSpace(strSilence))))
+		return
+	}
+	silence := rtuSilence
+	if ms > 0 {
+		silence = time.Duration(ms * float64(time.Millisecond))
+	}
+	framing := app.combMbFraming.GetActive()
+	app.modbusMaster.setFraming(framing, silence)
+	app.modbusSlave.setFraming(framing, silence)
+}
+
+func (app *NetAssistantApp) onBtnModbusApply() {
+	start, end := app.tbMbRegisters.GetBounds()
+	text, _ := app.tbMbRegisters.GetText(start, end, true)
+	if err := app.modbusSlave.regs.load(text); err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	}
+}
+
+// onBtnModbusRefresh shows the current register map, including the writes of connected masters
+func (app *NetAssistantApp) onBtnModbusRefresh() {
+	app.tbMbRegisters.SetText(app.modbusSlave.regs.String())
+}
+
+func (app *NetAssistantApp) createModbusPage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
+	box.SetBorderWidth(10)
+
+	app.modbusMaster.result = func(title, table string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog("[MODBUS] " + title)
+			app.tbMbResult.SetText(title + "\n" + table)
+		})
+	}
+	app.modbusSlave.report = func(msg string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog("[MODBUS] " + msg)
+		})
+	}
+	app.combMbFraming, _ = gtk.ComboBoxTextNew()
+	app.combMbFraming.AppendText(getI18nText(IT_MB_FRAMING_TCP))
+	app.combMbFraming.AppendText(getI18nText(IT_MB_FRAMING_RTU))
+	app.combMbFraming.SetActive(framingMBAP)
+	app.combMbFraming.Connect("changed", app.onModbusFramingChanged)
+	app.entryMbSilence, _ = gtk.EntryNew()
+	app.entryMbSilence.SetPlaceholderText(getI18nText(IT_MB_SILENCE))
+	app.entryMbSilence.Connect("changed", app.onModbusFramingChanged)
+	masterBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	masterBox.SetBorderWidth(5)
+	app.combMbFunction, _ = gtk.ComboBoxTextNew()
+	for _, f := range modbusFunctions {
+		app.combMbFunction.AppendText(getI18nText(f.key))
+	}
+	app.combMbFunction.SetActive(2)
+	app.entryMbUnit, _ = gtk.EntryNew()
+	app.entryMbUnit.SetPlaceholderText(getI18nText(IT_MB_UNIT))
+	app.entryMbAddress, _ = gtk.EntryNew()
+	app.entryMbAddress.SetPlaceholderText(getI18nText(IT_MB_ADDRESS))
+	app.entryMbQuantity, _ = gtk.EntryNew()
+	app.entryMbQuantity.SetPlaceholderText(getI18nText(IT_MB_QUANTITY))
+	app.entryMbValues, _ = gtk.EntryNew()
+	app.entryMbValues.SetPlaceholderText(getI18nText(IT_MB_VALUES))
+	btnSend, _ := gtk.ButtonNewWithLabel(getI18nText(IT_SEND))
+	btnSend.Connect("clicked", app.onBtnModbusSend)
+	resultScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	resultScroller.SetSizeRequest(220, 120)
+	tvResult, _ := gtk.TextViewNew()
+	tvResult.SetMonospace(true)
+	tvResult.SetEditable(false)
+	app.tbMbResult, _ = tvResult.GetBuffer()
+	resultScroller.Add(tvResult)
+	masterBox.PackStart(app.combMbFunction, false, false, 0)
+	masterBox.PackStart(app.entryMbUnit, false, false, 0)
+	masterBox.PackStart(app.entryMbAddress, false, false, 0)
+	masterBox.PackStart(app.entryMbQuantity, false, false, 0)
+	masterBox.PackStart(app.entryMbValues, false, false, 0)
+	masterBox.PackStart(btnSend, false, false, 0)
+	masterBox.PackStart(resultScroller, true, true, 0)
+	masterFrame, _ := gtk.FrameNew(getI18nText(IT_MB_MASTER))
+	masterFrame.Add(masterBox)
+
+	slaveBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	slaveBox.SetBorderWidth(5)
+	labelSlave, _ := gtk.LabelNew(getI18nText(IT_MB_SLAVE_HINT))
+	labelSlave.SetLineWrap(true)
+	labelSlave.SetMaxWidthChars(30)
+	labelSlave.SetXAlign(0)
+	mapScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	mapScroller.SetSizeRequest(220, 120)
+	tvMap, _ := gtk.TextViewNew()
+	tvMap.SetMonospace(true)
+	app.tbMbRegisters, _ = tvMap.GetBuffer()
+	app.tbMbRegisters.SetText(modbusMapExample)
+	app.modbusSlave.regs.load(modbusMapExample)
+	mapScroller.Add(tvMap)
+	btnBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	btnApply, _ := gtk.ButtonNewWithLabel(getI18nText(IT_APPLY))
+	btnApply.Connect("clicked", app.onBtnModbusApply)
+	btnRefresh, _ := gtk.ButtonNewWithLabel(getI18nText(IT_REFRESH))
+	btnRefresh.Connect("clicked", app.onBtnModbusRefresh)
+	btnBox.PackStart(btnApply, true, true, 0)
+	btnBox.PackStart(btnRefresh, true, true, 0)
+	slaveBox.PackStart(labelSlave, false, false, 0)
+	slaveBox.PackStart(mapScroller, true, true, 0)
+	slaveBox.PackStart(btnBox, false, false, 0)
+	slaveFrame, _ := gtk.FrameNew(getI18nText(IT_MB_SLAVE))
+	slaveFrame.Add(slaveBox)
+
+	box.PackStart(app.combMbFraming, false, false, 0)
+	box.PackStart(app.entryMbSilence, false, false, 0)
+	box.PackStart(masterFrame, true, true, 0)
+	box.PackStart(slaveFrame, true, true, 0)
+	return box
+}
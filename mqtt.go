@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// mqttSettings reads the connect options from the MQTT page
+func (app *NetAssistantApp) mqttSettings() (mqttOptions, error) {
+	opts := mqttOptions{
+		version:    mqttV311,
+		transport:  app.combMqttTransport.GetActive(),
+		insecure:   app.cbMqttInsecure.GetActive(),
+		clean:      app.cbMqttClean.GetActive(),
+		willQoS:    byte(app.combMqttWillQoS.GetActive()),
+		willRetain: app.cbMqttWillRetain.GetActive(),
+		keepalive:  60,
+	}
+	if app.combMqttVersion.GetActive() == 1 {
+		opts.version = mqttV5
+	}
+	opts.wsPath, _ = app.entryMqttPath.GetText()
+	opts.clientID, _ = app.entryMqttClientID.GetText()
+	opts.username, _ = app.entryMqttUser.GetText()
+	opts.password, _ = app.entryMqttPassword.GetText()
+	opts.willTopic, _ = app.entryMqttWillTopic.GetText()
+	willPayload, _ := app.entryMqttWillPayload.GetText()
+	opts.willPayload = []byte(willPayload)
+	strKeepalive, _ := app.entryMqttKeepalive.GetText()
+	if strings.TrimSpace(strKeepalive) != "" {
+		keepalive, err := parseNumber(strKeepalive)
+		if err != nil || keepalive < 0 || keepalive > 65535 {
+			return opts, fmt.Errorf("invalid keepalive %q", strings.TrimSpace(strKeepalive))
+		}
+		opts.keepalive = int(keepalive)
+	}
+	return opts, nil
+}
+
+// connectMqttClient connects the MQTT client mode and starts reading messages
+func (app *NetAssistantApp) connectMqttClient(addr string) (*mqttClient, error) {
+	opts, err := app.mqttSettings()
+	if err != nil {
+		return nil, err
+	}
+	client, err := connectMqtt(addr, opts)
+	if err != nil {
+		return nil, err
+	}
+	client.setPublish(app.mqttPublish())
+	client.message = func(msg *mqttMessage) {
+		app.receive(client, nil, msg.payload, msg.tag())
+	}
+	client.report = func(msg string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog("[MQTT] " + msg)
+		})
+	}
+	go func() {
+		err := client.run()
+		log.Info("mqtt connection closed:", err)
+		tips := fmt.Sprintf(`<span foreground="red">connection closed: %s </span>`, glib.MarkupEscapeText(err.Error()))
+		glib.IdleAdd(func() {
+			app.labelStatus.SetMarkup(tips)
+			if app.mqttClient == client {
+				app.mqttClient = nil
+			}
+		})
+		for index, connItem := range app.connList {
+			if connItem == net.Conn(client) {
+				app.connList = append(app.connList[:index], app.connList[index+1:]...)
+				break
+			}
+		}
+	}()
+	app.appendRecvLog("[MQTT] connected as " + client.clientID)
+	return client, nil
+}
+
+func (app *NetAssistantApp) mqttPublish() (string, byte, bool) {
+	topic, _ := app.entryMqttTopic.GetText()
+	return strings.TrimSpace(topic), byte(app.combMqttQoS.GetActive()), app.cbMqttRetain.GetActive()
+}
+
+// onMqttPublishChanged hands the publish options to the client, the send box may publish from another goroutine
+func (app *NetAssistantApp) onMqttPublishChanged() {
+	if app.mqttClient != nil {
+		app.mqttClient.setPublish(app.mqttPublish())
+	}
+}
+
+func (app *NetAssistantApp) onBtnMqttSubscribe(subscribe bool) {
+	if app.mqttClient == nil {
+		app.labelStatus.SetText(getI18nText(IT_NO_CONN))
+		return
+	}
+	filter, _ := app.entryMqttFilter.GetText()
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return
+	}
+	var err error
+	if subscribe {
+		err = app.mqttClient.subscribe(filter, byte(app.combMqttFilterQoS.GetActive()))
+	} else {
+		err = app.mqttClient.unsubscribe(filter)
+	}
+	if err != nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	}
+}
+
+func createQoSCombo() *gtk.ComboBoxText {
+	comb, _ := gtk.ComboBoxTextNew()
+	for qos := 0; qos <= 2; qos++ {
+		comb.AppendText(fmt.Sprintf("QoS %d", qos))
+	}
+	comb.SetActive(0)
+	return comb
+}
+
+func (app *NetAssistantApp) createMqttPage() *gtk.ScrolledWindow {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	box.SetBorderWidth(10)
+
+	app.combMqttVersion, _ = gtk.ComboBoxTextNew()
+	app.combMqttVersion.AppendText("MQTT 3.1.1")
+	app.combMqttVersion.AppendText("MQTT 5.0")
+	app.combMqttVersion.SetActive(0)
+	app.combMqttTransport, _ = gtk.ComboBoxTextNew()
+	for _, key := range []string{IT_MQTT_TCP, IT_MQTT_TLS, IT_MQTT_WS, IT_MQTT_WSS} {
+		app.combMqttTransport.AppendText(getI18nText(key))
+	}
+	app.combMqttTransport.SetActive(mqttTransportTCP)
+	app.cbMqttInsecure, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_TLS_INSECURE))
+	app.entryMqttPath, _ = gtk.EntryNew()
+	app.entryMqttPath.SetPlaceholderText(getI18nText(IT_MQTT_WS_PATH))
+	app.entryMqttClientID, _ = gtk.EntryNew()
+	app.entryMqttClientID.SetPlaceholderText(getI18nText(IT_MQTT_CLIENT_ID))
+	app.entryMqttUser, _ = gtk.EntryNew()
+	app.entryMqttUser.SetPlaceholderText(getI18nText(IT_USERNAME))
+	app.entryMqttPassword, _ = gtk.EntryNew()
+	app.entryMqttPassword.SetPlaceholderText(getI18nText(IT_PASSWORD))
+	app.entryMqttPassword.SetVisibility(false)
+	app.entryMqttKeepalive, _ = gtk.EntryNew()
+	app.entryMqttKeepalive.SetPlaceholderText(getI18nText(IT_MQTT_KEEPALIVE))
+	app.cbMqttClean, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_MQTT_CLEAN))
+	app.cbMqttClean.SetActive(true)
+
+	willBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	willBox.SetBorderWidth(5)
+	app.entryMqttWillTopic, _ = gtk.EntryNew()
+	app.entryMqttWillTopic.SetPlaceholderText(getI18nText(IT_MQTT_TOPIC))
+	app.entryMqttWillPayload, _ = gtk.EntryNew()
+	app.entryMqttWillPayload.SetPlaceholderText(getI18nText(IT_MQTT_PAYLOAD))
+	app.combMqttWillQoS = createQoSCombo()
+	app.cbMqttWillRetain, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_MQTT_RETAIN))
+	willHbox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	willHbox.PackStart(app.combMqttWillQoS, false, false, 0)
+	willHbox.PackStart(app.cbMqttWillRetain, false, false, 0)
+	willBox.PackStart(app.entryMqttWillTopic, false, false, 0)
+	willBox.PackStart(app.entryMqttWillPayload, false, false, 0)
+	willBox.PackStart(willHbox, false, false, 0)
+	willFrame, _ := gtk.FrameNew(getI18nText(IT_MQTT_WILL))
+	willFrame.Add(willBox)
+
+	subBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	subBox.SetBorderWidth(5)
+	app.entryMqttFilter, _ = gtk.EntryNew()
+	app.entryMqttFilter.SetPlaceholderText(getI18nText(IT_MQTT_FILTER))
+	app.combMqttFilterQoS = createQoSCombo()
+	btnSubscribe, _ := gtk.ButtonNewWithLabel(getI18nText(IT_MQTT_SUBSCRIBE))
+	btnSubscribe.Connect("clicked", func() {
+		app.onBtnMqttSubscribe(true)
+	})
+	btnUnsubscribe, _ := gtk.ButtonNewWithLabel(getI18nText(IT_MQTT_UNSUBSCRIBE))
+	btnUnsubscribe.Connect("clicked", func() {
+		app.onBtnMqttSubscribe(false)
+	})
+	subHbox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	subHbox.PackStart(app.combMqttFilterQoS, false, false, 0)
+	subHbox.PackStart(btnSubscribe, true, true, 0)
+	subHbox.PackStart(btnUnsubscribe, true, true, 0)
+	subBox.PackStart(app.entryMqttFilter, false, false, 0)
+	subBox.PackStart(subHbox, false, false, 0)
+	subFrame, _ := gtk.FrameNew(getI18nText(IT_MQTT_SUBSCRIBE))
+	subFrame.Add(subBox)
+
+	pubBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	pubBox.SetBorderWidth(5)
+	app.entryMqttTopic, _ = gtk.EntryNew()
+	app.entryMqttTopic.SetPlaceholderText(getI18nText(IT_MQTT_TOPIC))
+	app.entryMqttTopic.Connect("changed", app.onMqttPublishChanged)
+	app.combMqttQoS = createQoSCombo()
+	app.combMqttQoS.Connect("changed", app.onMqttPublishChanged)
+	app.cbMqttRetain, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_MQTT_RETAIN))
+	app.cbMqttRetain.Connect("toggled", app.onMqttPublishChanged)
+	pubHbox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	pubHbox.PackStart(app.combMqttQoS, false, false, 0)
+	pubHbox.PackStart(app.cbMqttRetain, false, false, 0)
+	pubBox.PackStart(app.entryMqttTopic, false, false, 0)
+	pubBox.PackStart(pubHbox, false, false, 0)
+	pubFrame, _ := gtk.FrameNew(getI18nText(IT_MQTT_PUBLISH))
+	pubFrame.Add(pubBox)
+
+	box.PackStart(app.combMqttVersion, false, false, 0)
+	box.PackStart(app.combMqttTransport, false, false, 0)
+	box.PackStart(app.cbMqttInsecure, false, false, 0)
+	box.PackStart(app.entryMqttPath, false, false, 0)
+	box.PackStart(app.entryMqttClientID, false, false, 0)
+	box.PackStart(app.entryMqttUser, false, false, 0)
+	box.PackStart(app.entryMqttPassword, false, false, 0)
+	box.PackStart(app.entryMqttKeepalive, false, false, 0)
+	box.PackStart(app.cbMqttClean, false, false, 0)
+	box.PackStart(willFrame, false, false, 0)
+	box.PackStart(subFrame, false, false, 0)
+	box.PackStart(pubFrame, false, false, 0)
+
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	scroller.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	scroller.Add(box)
+	return scroller
+}
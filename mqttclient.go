@@ -0,0 +1,781 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mqtt control packet types
+const (
+	mqttConnect     = 1
+	mqttConnack     = 2
+	mqttPublish     = 3
+	mqttPuback      = 4
+	mqttPubrec      = 5
+	mqttPubrel      = 6
+	mqttPubcomp     = 7
+	mqttSubscribe   = 8
+	mqttSuback      = 9
+	mqttUnsubscribe = 10
+	mqttUnsuback    = 11
+	mqttPingreq     = 12
+	mqttPingresp    = 13
+	mqttDisconnect  = 14
+)
+
+// protocol levels of the CONNECT packet
+const (
+	mqttV311 = 4
+	mqttV5   = 5
+)
+
+// transports of the MQTT client, in combo box order
+const (
+	mqttTransportTCP = iota
+	mqttTransportTLS
+	mqttTransportWS
+	mqttTransportWSS
+)
+
+const (
+	mqttDialTimeout   = 10 * time.Second
+	mqttMaxPacket     = 16 << 20
+	mqttSessionExpiry = 24 * 60 * 60 // seconds an MQTT 5 session outlives the connection without clean start
+)
+
+// MQTT 3.1.1 CONNACK return codes
+var mqttReturnCodes = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// MQTT 5 reason codes
+var mqttReasonCodes = map[byte]string{
+	0x00: "success",
+	0x01: "granted QoS 1",
+	0x02: "granted QoS 2",
+	0x04: "disconnect with will message",
+	0x10: "no matching subscribers",
+	0x11: "no subscription existed",
+	0x80: "unspecified error",
+	0x81: "malformed packet",
+	0x82: "protocol error",
+	0x83: "implementation specific error",
+	0x84: "unsupported protocol version",
+	0x85: "client identifier not valid",
+	0x86: "bad user name or password",
+	0x87: "not authorized",
+	0x88: "server unavailable",
+	0x89: "server busy",
+	0x8A: "banned",
+	0x8B: "server shutting down",
+	0x8C: "bad authentication method",
+	0x8D: "keep alive timeout",
+	0x8E: "session taken over",
+	0x8F: "topic filter invalid",
+	0x90: "topic name invalid",
+	0x91: "packet identifier in use",
+	0x92: "packet identifier not found",
+	0x93: "receive maximum exceeded",
+	0x94: "topic alias invalid",
+	0x95: "packet too large",
+	0x96: "message rate too high",
+	0x97: "quota exceeded",
+	0x98: "administrative action",
+	0x99: "payload format invalid",
+	0x9A: "retain not supported",
+	0x9B: "QoS not supported",
+	0x9C: "use another server",
+	0x9D: "server moved",
+	0x9E: "shared subscriptions not supported",
+	0x9F: "connection rate exceeded",
+	0xA0: "maximum connect time",
+	0xA1: "subscription identifiers not supported",
+	0xA2: "wildcard subscriptions not supported",
+}
+
+func mqttReason(code byte) string {
+	if name, ok := mqttReasonCodes[code]; ok {
+		return fmt.Sprintf("0x%02X %s", code, name)
+	}
+	return fmt.Sprintf("0x%02X", code)
+}
+
+// value types of the MQTT 5 properties
+const (
+	propByte = iota
+	propUint16
+	propUint32
+	propVarint
+	propString
+	propBinary
+	propPair
+)
+
+var mqttPropertyTypes = map[byte]int{
+	0x01: propByte, 0x02: propUint32, 0x03: propString, 0x08: propString, 0x09: propBinary,
+	0x0B: propVarint, 0x11: propUint32, 0x12: propString, 0x13: propUint16, 0x15: propString,
+	0x16: propBinary, 0x17: propByte, 0x18: propUint32, 0x19: propByte, 0x1A: propString,
+	0x1C: propString, 0x1F: propString, 0x21: propUint16, 0x22: propUint16, 0x23: propUint16,
+	0x24: propByte, 0x25: propByte, 0x26: propPair, 0x27: propUint32, 0x28: propByte,
+	0x29: propByte, 0x2A: propByte,
+}
+
+// properties used by the client
+const (
+	propContentType      = 0x03
+	propSessionExpiry    = 0x11
+	propAssignedClientID = 0x12
+	propServerKeepAlive  = 0x13
+	propReasonString     = 0x1F
+	propTopicAlias       = 0x23
+	propUserProperty     = 0x26
+)
+
+type mqttProperty struct {
+	id    byte
+	value string
+	num   uint32
+}
+
+func mqttAppendVarint(b []byte, n int) []byte {
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func mqttAppendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func mqttPacket(typ, flags byte, body []byte) []byte {
+	packet := mqttAppendVarint([]byte{typ<<4 | flags}, len(body))
+	return append(packet, body...)
+}
+
+func readMqttPacket(r *bufio.Reader) (typ, flags byte, body []byte, err error) {
+	head, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	size, shift := 0, 0
+	for i := 0; ; i++ {
+		var digit byte
+		if digit, err = r.ReadByte(); err != nil {
+			return
+		}
+		size |= int(digit&0x7F) << shift
+		shift += 7
+		if digit&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			err = errors.New("mqtt: malformed remaining length")
+			return
+		}
+	}
+	if size > mqttMaxPacket {
+		err = fmt.Errorf("mqtt: packet of %d bytes is too large", size)
+		return
+	}
+	body = make([]byte, size)
+	_, err = io.ReadFull(r, body)
+	return head >> 4, head & 0x0F, body, err
+}
+
+// mqttDecoder reads the fields of a packet body, a short body sets err and yields zero values
+type mqttDecoder struct {
+	data []byte
+	err  error
+}
+
+// take returns the next n bytes, after an error the fixed width readers get zeros and the others nothing,
+// so a length read from a malformed packet is never allocated
+func (d *mqttDecoder) take(n int) []byte {
+	if d.err != nil || len(d.data) < n {
+		if d.err == nil {
+			d.err = errors.New("mqtt: packet too short")
+		}
+		if n > 4 {
+			return nil
+		}
+		return make([]byte, n)
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *mqttDecoder) u8() byte        { return d.take(1)[0] }
+func (d *mqttDecoder) u16() uint16     { return binary.BigEndian.Uint16(d.take(2)) }
+func (d *mqttDecoder) u32() uint32     { return binary.BigEndian.Uint32(d.take(4)) }
+func (d *mqttDecoder) str() string     { return string(d.bin()) }
+func (d *mqttDecoder) rest() []byte    { return d.take(len(d.data)) }
+func (d *mqttDecoder) remaining() bool { return d.err == nil && len(d.data) > 0 }
+func (d *mqttDecoder) fail(msg string) { d.err = errors.New("mqtt: " + msg) }
+
+func (d *mqttDecoder) bin() []byte {
+	if d.err != nil {
+		return nil
+	}
+	n := int(d.u16())
+	if d.err != nil {
+		return nil
+	}
+	return d.take(n)
+}
+
+// sub returns a decoder for a block with a variable byte integer length, such as the properties
+func (d *mqttDecoder) sub() mqttDecoder {
+	if d.err != nil {
+		return mqttDecoder{err: d.err}
+	}
+	n := int(d.varint())
+	if d.err != nil {
+		return mqttDecoder{err: d.err}
+	}
+	return mqttDecoder{data: d.take(n)}
+}
+
+func (d *mqttDecoder) varint() uint32 {
+	var n uint32
+	for i := 0; i < 4; i++ {
+		digit := d.u8()
+		n |= uint32(digit&0x7F) << (7 * i)
+		if digit&0x80 == 0 {
+			return n
+		}
+	}
+	d.fail("malformed variable byte integer")
+	return 0
+}
+
+// properties reads an MQTT 5 property list
+func (d *mqttDecoder) properties() []mqttProperty {
+	list := d.sub()
+	var props []mqttProperty
+	for list.remaining() {
+		prop := mqttProperty{id: list.u8()}
+		typ, ok := mqttPropertyTypes[prop.id]
+		if !ok {
+			d.fail(fmt.Sprintf("unknown property 0x%02X", prop.id))
+			return props
+		}
+		switch typ {
+		case propByte:
+			prop.num = uint32(list.u8())
+		case propUint16:
+			prop.num = uint32(list.u16())
+		case propUint32:
+			prop.num = list.u32()
+		case propVarint:
+			prop.num = list.varint()
+		case propString:
+			prop.value = list.str()
+		case propBinary:
+			prop.value = hexString(list.bin())
+		case propPair:
+			prop.value = list.str() + "=" + list.str()
+		}
+		props = append(props, prop)
+	}
+	if list.err != nil {
+		d.err = list.err
+	}
+	return props
+}
+
+func findProperty(props []mqttProperty, id byte) *mqttProperty {
+	for i := range props {
+		if props[i].id == id {
+			return &props[i]
+		}
+	}
+	return nil
+}
+
+// mqttOptions are the connect options of the client
+type mqttOptions struct {
+	version     byte
+	transport   int
+	insecure    bool
+	wsPath      string
+	clientID    string
+	username    string
+	password    string
+	keepalive   int // seconds
+	clean       bool
+	willTopic   string
+	willPayload []byte
+	willQoS     byte
+	willRetain  bool
+}
+
+// mqttMessage is a received application message
+type mqttMessage struct {
+	topic   string
+	payload []byte
+	qos     byte
+	retain  bool
+	dup     bool
+	props   []mqttProperty
+}
+
+func (m *mqttMessage) tag() string {
+	tag := fmt.Sprintf("%s QoS%d", m.topic, m.qos)
+	if m.retain {
+		tag += " retain"
+	}
+	if m.dup {
+		tag += " dup"
+	}
+	for _, prop := range m.props {
+		switch prop.id {
+		case propContentType:
+			tag += " " + prop.value
+		case propUserProperty:
+			tag += " " + prop.value
+		}
+	}
+	return tag
+}
+
+// mqttClient is a connected MQTT session, Write publishes to the configured topic so the send box works unchanged
+type mqttClient struct {
+	net.Conn
+	version   byte
+	reader    *bufio.Reader
+	keepalive time.Duration
+	clientID  string
+
+	mu       sync.Mutex // serializes packet writes and guards the fields below
+	nextID   uint16
+	pending  map[uint16]string // what an acknowledgement is for
+	aliases  map[uint16]string // topic aliases set by the server
+	inbound  map[uint16]bool   // QoS 2 messages shown but not yet released, a redelivery is not shown again
+	topic    string
+	qos      byte
+	retain   bool
+	chanStop chan bool
+	stopped  bool
+
+	message func(msg *mqttMessage)
+	report  func(msg string)
+}
+
+// dialMqtt opens the transport, WebSocket connections use the mqtt subprotocol
+func dialMqtt(addr string, opts mqttOptions) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: mqttDialTimeout}
+	var conn net.Conn
+	if opts.transport == mqttTransportTLS || opts.transport == mqttTransportWSS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host, InsecureSkipVerify: opts.insecure})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.transport == mqttTransportWS || opts.transport == mqttTransportWSS {
+		path := opts.wsPath
+		if path == "" {
+			path = "/mqtt"
+		}
+		conn.SetDeadline(time.Now().Add(mqttDialTimeout))
+		ws, err := wsClientHandshake(conn, addr, path, []string{"mqtt"}, nil)
+		conn.SetDeadline(time.Time{})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return ws, nil
+	}
+	return conn, nil
+}
+
+func randomClientID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "netassistant-" + hex.EncodeToString(b)
+}
+
+func connectPacket(opts mqttOptions) []byte {
+	flags := byte(0)
+	if opts.clean {
+		flags |= 0x02
+	}
+	if opts.willTopic != "" {
+		flags |= 0x04 | opts.willQoS<<3
+		if opts.willRetain {
+			flags |= 0x20
+		}
+	}
+	if opts.password != "" {
+		flags |= 0x40
+	}
+	if opts.username != "" {
+		flags |= 0x80
+	}
+	body := mqttAppendString(nil, "MQTT")
+	body = append(body, opts.version, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(opts.keepalive))
+	if opts.version == mqttV5 {
+		var props []byte
+		if !opts.clean {
+			props = binary.BigEndian.AppendUint32([]byte{propSessionExpiry}, mqttSessionExpiry)
+		}
+		body = append(mqttAppendVarint(body, len(props)), props...)
+	}
+	body = mqttAppendString(body, opts.clientID)
+	if opts.willTopic != "" {
+		if opts.version == mqttV5 {
+			body = mqttAppendVarint(body, 0)
+		}
+		body = mqttAppendString(body, opts.willTopic)
+		body = mqttAppendString(body, string(opts.willPayload))
+	}
+	if opts.username != "" {
+		body = mqttAppendString(body, opts.username)
+	}
+	if opts.password != "" {
+		body = mqttAppendString(body, opts.password)
+	}
+	return mqttPacket(mqttConnect, 0, body)
+}
+
+// connectMqtt opens a session and waits for the CONNACK
+func connectMqtt(addr string, opts mqttOptions) (*mqttClient, error) {
+	if opts.clientID == "" && (opts.version == mqttV311 || !opts.clean) {
+		opts.clientID = randomClientID()
+	}
+	conn, err := dialMqtt(addr, opts)
+	if err != nil {
+		return nil, err
+	}
+	c := &mqttClient{
+		Conn:      conn,
+		version:   opts.version,
+		reader:    bufio.NewReader(conn),
+		keepalive: time.Duration(opts.keepalive) * time.Second,
+		clientID:  opts.clientID,
+		pending:   map[uint16]string{},
+		aliases:   map[uint16]string{},
+		inbound:   map[uint16]bool{},
+		chanStop:  make(chan bool),
+	}
+	conn.SetDeadline(time.Now().Add(mqttDialTimeout))
+	err = c.send(connectPacket(opts))
+	var typ byte
+	var body []byte
+	if err == nil {
+		typ, _, body, err = readMqttPacket(c.reader)
+	}
+	conn.SetDeadline(time.Time{})
+	if err == nil && typ != mqttConnack {
+		err = fmt.Errorf("mqtt: expected CONNACK, got packet type %d", typ)
+	}
+	if err == nil {
+		d := mqttDecoder{data: body}
+		d.u8() // session present
+		code := d.u8()
+		if opts.version == mqttV5 {
+			props := d.properties()
+			if prop := findProperty(props, propAssignedClientID); prop != nil {
+				c.clientID = prop.value
+			}
+			if prop := findProperty(props, propServerKeepAlive); prop != nil {
+				c.keepalive = time.Duration(prop.num) * time.Second
+			}
+			if code >= 0x80 {
+				err = fmt.Errorf("mqtt: connection refused: %s", mqttReason(code))
+				if prop := findProperty(props, propReasonString); prop != nil {
+					err = fmt.Errorf("%s (%s)", err, prop.value)
+				}
+			}
+		} else if code != 0 {
+			err = fmt.Errorf("mqtt: connection refused: %d %s", code, mqttReturnCodes[code])
+		}
+		if err == nil {
+			err = d.err
+		}
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *mqttClient) send(packet []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.Conn.Write(packet)
+	return err
+}
+
+// packetID returns the next free packet identifier, c.mu must be held
+func (c *mqttClient) packetID(purpose string) uint16 {
+	for {
+		c.nextID++
+		if _, used := c.pending[c.nextID]; c.nextID != 0 && !used {
+			c.pending[c.nextID] = purpose
+			return c.nextID
+		}
+	}
+}
+
+func (c *mqttClient) setPublish(topic string, qos byte, retain bool) {
+	c.mu.Lock()
+	c.topic, c.qos, c.retain = topic, qos, retain
+	c.mu.Unlock()
+}
+
+// Write publishes p to the configured topic
+func (c *mqttClient) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	topic, qos, retain := c.topic, c.qos, c.retain
+	c.mu.Unlock()
+	if topic == "" {
+		return 0, errors.New("mqtt: no publish topic")
+	}
+	if strings.ContainsAny(topic, "+#") {
+		return 0, fmt.Errorf("mqtt: wildcards are not allowed in the publish topic %q", topic)
+	}
+	if err := c.publish(topic, p, qos, retain); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *mqttClient) publish(topic string, payload []byte, qos byte, retain bool) error {
+	flags := qos << 1
+	if retain {
+		flags |= 0x01
+	}
+	body := mqttAppendString(nil, topic)
+	if qos > 0 {
+		c.mu.Lock()
+		id := c.packetID("publish to " + topic)
+		c.mu.Unlock()
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	if c.version == mqttV5 {
+		body = mqttAppendVarint(body, 0)
+	}
+	return c.send(mqttPacket(mqttPublish, flags, append(body, payload...)))
+}
+
+func (c *mqttClient) subscribe(filter string, qos byte) error {
+	c.mu.Lock()
+	id := c.packetID("subscribe " + filter)
+	c.mu.Unlock()
+	body := binary.BigEndian.AppendUint16(nil, id)
+	if c.version == mqttV5 {
+		body = mqttAppendVarint(body, 0)
+	}
+	body = append(mqttAppendString(body, filter), qos)
+	return c.send(mqttPacket(mqttSubscribe, 0x02, body))
+}
+
+func (c *mqttClient) unsubscribe(filter string) error {
+	c.mu.Lock()
+	id := c.packetID("unsubscribe " + filter)
+	c.mu.Unlock()
+	body := binary.BigEndian.AppendUint16(nil, id)
+	if c.version == mqttV5 {
+		body = mqttAppendVarint(body, 0)
+	}
+	return c.send(mqttPacket(mqttUnsubscribe, 0x02, mqttAppendString(body, filter)))
+}
+
+// ack sends a PUBACK, PUBREC, PUBREL or PUBCOMP
+func (c *mqttClient) ack(typ byte, id uint16) error {
+	flags := byte(0)
+	if typ == mqttPubrel {
+		flags = 0x02
+	}
+	return c.send(mqttPacket(typ, flags, binary.BigEndian.AppendUint16(nil, id)))
+}
+
+// done removes a pending packet identifier and reports the reason codes of its acknowledgement
+func (c *mqttClient) done(id uint16, codes []byte) {
+	c.mu.Lock()
+	purpose, found := c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+	if !found {
+		return
+	}
+	for _, code := range codes {
+		if code < 0x80 {
+			c.report(fmt.Sprintf("%s: %s", purpose, mqttReason(code)))
+		} else {
+			c.report(fmt.Sprintf("%s failed: %s", purpose, mqttReason(code)))
+		}
+	}
+}
+
+// Close sends DISCONNECT before closing the transport
+func (c *mqttClient) Close() error {
+	c.mu.Lock()
+	if !c.stopped {
+		c.stopped = true
+		close(c.chanStop)
+		body := []byte{}
+		if c.version == mqttV5 {
+			body = []byte{0, 0}
+		}
+		c.Conn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.Conn.Write(mqttPacket(mqttDisconnect, 0, body))
+	}
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
+
+// pinger sends PINGREQ within the keepalive interval
+func (c *mqttClient) pinger() {
+	if c.keepalive <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.keepalive * 3 / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.send(mqttPacket(mqttPingreq, 0, nil)); err != nil {
+				return
+			}
+		case <-c.chanStop:
+			return
+		}
+	}
+}
+
+// run reads packets until the connection closes
+func (c *mqttClient) run() error {
+	go c.pinger()
+	for {
+		typ, flags, body, err := readMqttPacket(c.reader)
+		if err != nil {
+			return err
+		}
+		d := mqttDecoder{data: body}
+		switch typ {
+		case mqttPublish:
+			msg := &mqttMessage{qos: flags >> 1 & 3, retain: flags&1 != 0, dup: flags&8 != 0}
+			msg.topic = d.str()
+			var id uint16
+			if msg.qos > 0 {
+				id = d.u16()
+			}
+			if c.version == mqttV5 {
+				msg.props = d.properties()
+				if prop := findProperty(msg.props, propTopicAlias); prop != nil {
+					c.mu.Lock()
+					if msg.topic != "" {
+						c.aliases[uint16(prop.num)] = msg.topic
+					} else {
+						msg.topic = c.aliases[uint16(prop.num)]
+					}
+					c.mu.Unlock()
+				}
+			}
+			msg.payload = d.rest()
+			if d.err != nil {
+				return d.err
+			}
+			switch msg.qos {
+			case 0:
+				c.message(msg)
+			case 1:
+				c.message(msg)
+				err = c.ack(mqttPuback, id)
+			case 2:
+				c.mu.Lock()
+				seen := c.inbound[id]
+				c.inbound[id] = true
+				c.mu.Unlock()
+				if !seen {
+					c.message(msg)
+				}
+				err = c.ack(mqttPubrec, id)
+			}
+		case mqttPuback, mqttPubcomp:
+			id := d.u16()
+			var codes []byte
+			if d.remaining() && d.data[0] >= 0x80 {
+				codes = d.rest()[:1]
+			}
+			c.done(id, codes)
+		case mqttPubrec:
+			id := d.u16()
+			if d.remaining() && d.data[0] >= 0x80 {
+				c.done(id, d.rest()[:1])
+				break
+			}
+			err = c.ack(mqttPubrel, id)
+		case mqttPubrel:
+			id := d.u16()
+			c.mu.Lock()
+			delete(c.inbound, id)
+			c.mu.Unlock()
+			err = c.ack(mqttPubcomp, id)
+		case mqttSuback:
+			id := d.u16()
+			if c.version == mqttV5 {
+				d.properties()
+			}
+			c.done(id, d.rest())
+		case mqttUnsuback:
+			id := d.u16()
+			codes := []byte{0}
+			if c.version == mqttV5 {
+				d.properties()
+				codes = d.rest()
+			}
+			c.done(id, codes)
+		case mqttPingresp:
+		case mqttDisconnect:
+			reason := "server disconnected"
+			if d.remaining() {
+				reason += ": " + mqttReason(d.u8())
+				if d.remaining() {
+					if prop := findProperty(d.properties(), propReasonString); prop != nil {
+						reason += " (" + prop.value + ")"
+					}
+				}
+			}
+			return errors.New(reason)
+		default:
+			return fmt.Errorf("mqtt: unexpected packet type %d", typ)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
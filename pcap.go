@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// oldest packets are dropped once the session holds more data than this
+const sessionMaxBytes = 64 << 20
+
+// largest TCP payload of one synthesized segment, keeps the IP length within 16 bits
+const maxSegment = 65000
+
+// fake hardware addresses of the synthesized Ethernet frames
+var (
+	localMAC  = []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	remoteMAC = []byte{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+)
+
+// sessionPacket is one read or write of a connection
+type sessionPacket struct {
+	dir    int
+	time   time.Time
+	local  net.Addr
+	remote net.Addr
+	udp    bool
+	data   []byte
+}
+
+// sessionLog keeps the traffic of the current session for export
+type sessionLog struct {
+	mu      sync.Mutex
+	packets []sessionPacket
+	bytes   int
+}
+
+func (s *sessionLog) add(pkt sessionPacket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packets = append(s.packets, pkt)
+	s.bytes += len(pkt.data)
+	drop := 0
+	for s.bytes > sessionMaxBytes && drop < len(s.packets)-1 {
+		s.bytes -= len(s.packets[drop].data)
+		drop++
+	}
+	if drop > 0 {
+		s.packets = append([]sessionPacket(nil), s.packets[drop:]...)
+	}
+}
+
+func (s *sessionLog) snapshot() []sessionPacket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sessionPacket(nil), s.packets...)
+}
+
+func (s *sessionLog) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.packets = nil
+	s.bytes = 0
+}
+
+// addrIPPort splits a TCP or UDP address, unknown addresses are the unspecified IPv4 address
+func addrIPPort(addr net.Addr) (net.IP, int) {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		if a != nil {
+			return a.IP, a.Port
+		}
+	case *net.UDPAddr:
+		if a != nil {
+			return a.IP, a.Port
+		}
+	}
+	return net.IPv4zero, 0
+}
+
+func checksum(sum uint32, data []byte) uint32 {
+	for i := 0; i+1 < len(data); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(data[i:]))
+	}
+	if len(data)%2 == 1 {
+		sum += uint32(data[len(data)-1]) << 8
+	}
+	return sum
+}
+
+func foldChecksum(sum uint32) uint16 {
+	for sum > 0xFFFF {
+		sum = sum>>16 + sum&0xFFFF
+	}
+	return ^uint16(sum)
+}
+
+// flowKey identifies one direction of a TCP connection
+type flowKey struct {
+	src, dst string
+}
+
+// frameBuilder synthesizes Ethernet frames for session packets and tracks TCP sequence numbers per direction
+type frameBuilder struct {
+	seq map[flowKey]uint32
+}
+
+func newFrameBuilder() *frameBuilder {
+	return &frameBuilder{seq: map[flowKey]uint32{}}
+}
+
+// nextSeq returns the sequence number of the next segment of a direction, the initial number is derived from the ports
+func (b *frameBuilder) nextSeq(key flowKey, srcPort, dstPort int) uint32 {
+	seq, ok := b.seq[key]
+	if !ok {
+		seq = uint32(srcPort)<<16 | uint32(dstPort)
+		b.seq[key] = seq
+	}
+	return seq
+}
+
+// frames returns the Ethernet frames of a packet, large TCP writes are split into several segments
+func (b *frameBuilder) frames(pkt sessionPacket) [][]byte {
+	srcIP, srcPort := addrIPPort(pkt.local)
+	dstIP, dstPort := addrIPPort(pkt.remote)
+	srcMAC, dstMAC := localMAC, remoteMAC
+	if pkt.dir == dirRecv {
+		srcIP, dstIP = dstIP, srcIP
+		srcPort, dstPort = dstPort, srcPort
+		srcMAC, dstMAC = dstMAC, srcMAC
+	}
+	v4 := srcIP.To4() != nil && dstIP.To4() != nil
+	if v4 {
+		srcIP, dstIP = srcIP.To4(), dstIP.To4()
+	} else {
+		srcIP, dstIP = srcIP.To16(), dstIP.To16()
+	}
+	if pkt.udp {
+		return [][]byte{buildFrame(srcMAC, dstMAC, srcIP, dstIP, v4, 17, udpSegment(srcIP, dstIP, srcPort, dstPort, pkt.data))}
+	}
+	key := flowKey{fmt.Sprint(srcIP, srcPort), fmt.Sprint(dstIP, dstPort)}
+	reverse := flowKey{key.dst, key.src}
+	frames := [][]byte{}
+	for data := pkt.data; len(data) > 0; {
+		n := len(data)
+		if n > maxSegment {
+			n = maxSegment
+		}
+		seq := b.nextSeq(key, srcPort, dstPort)
+		ack := b.nextSeq(reverse, dstPort, srcPort)
+		segment := tcpSegment(srcIP, dstIP, srcPort, dstPort, seq, ack, data[:n])
+		frames = append(frames, buildFrame(srcMAC, dstMAC, srcIP, dstIP, v4, 6, segment))
+		b.seq[key] = seq + uint32(n)
+		data = data[n:]
+	}
+	return frames
+}
+
+// pseudoHeaderSum is the checksum of the IPv4 or IPv6 pseudo header of a transport segment
+func pseudoHeaderSum(srcIP, dstIP net.IP, proto byte, length int) uint32 {
+	sum := checksum(0, srcIP)
+	sum = checksum(sum, dstIP)
+	return sum + uint32(proto) + uint32(length)
+}
+
+func udpSegment(srcIP, dstIP net.IP, srcPort, dstPort int, data []byte) []byte {
+	segment := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint16(segment[0:], uint16(srcPort))
+	binary.BigEndian.PutUint16(segment[2:], uint16(dstPort))
+	binary.BigEndian.PutUint16(segment[4:], uint16(len(segment)))
+	copy(segment[8:], data)
+	sum := foldChecksum(checksum(pseudoHeaderSum(srcIP, dstIP, 17, len(segment)), segment))
+	if sum == 0 {
+		sum = 0xFFFF
+	}
+	binary.BigEndian.PutUint16(segment[6:], sum)
+	return segment
+}
+
+func tcpSegment(srcIP, dstIP net.IP, srcPort, dstPort int, seq, ack uint32, data []byte) []byte {
+	segment := make([]byte, 20+len(data))
+	binary.BigEndian.PutUint16(segment[0:], uint16(srcPort))
+	binary.BigEndian.PutUint16(segment[2:], uint16(dstPort))
+	binary.BigEndian.PutUint32(segment[4:], seq)
+	binary.BigEndian.PutUint32(segment[8:], ack)
+	segment[12] = 5 << 4
+	segment[13] = 0x18 // PSH, ACK
+	binary.BigEndian.PutUint16(segment[14:], 65535)
+	copy(segment[20:], data)
+	binary.BigEndian.PutUint16(segment[16:], foldChecksum(checksum(pseudoHeaderSum(srcIP, dstIP, 6, len(segment)), segment)))
+	return segment
+}
+
+// buildFrame wraps a transport segment in IP and Ethernet headers
+func buildFrame(srcMAC, dstMAC []byte, srcIP, dstIP net.IP, v4 bool, proto byte, segment []byte) []byte {
+	frame := make([]byte, 0, 14+40+len(segment))
+	frame = append(frame, dstMAC...)
+	frame = append(frame, srcMAC...)
+	if v4 {
+		frame = append(frame, 0x08, 0x00)
+		header := make([]byte, 20)
+		header[0] = 0x45
+		binary.BigEndian.PutUint16(header[2:], uint16(20+len(segment)))
+		header[6] = 0x40 // don't fragment
+		header[8] = 64
+		header[9] = proto
+		copy(header[12:], srcIP)
+		copy(header[16:], dstIP)
+		binary.BigEndian.PutUint16(header[10:], foldChecksum(checksum(0, header)))
+		frame = append(frame, header...)
+	} else {
+		frame = append(frame, 0x86, 0xDD)
+		header := make([]byte, 40)
+		header[0] = 0x60
+		binary.BigEndian.PutUint16(header[4:], uint16(len(segment)))
+		header[6] = proto
+		header[7] = 64
+		copy(header[8:], srcIP)
+		copy(header[24:], dstIP)
+		frame = append(frame, header...)
+	}
+	return append(frame, segment...)
+}
+
+// pcap and pcapng constants
+const (
+	pcapMagic        = 0xA1B2C3D4
+	pcapngSHB        = 0x0A0D0D0A
+	pcapngIDB        = 1
+	pcapngEPB        = 6
+	pcapngByteOrder  = 0x1A2B3C4D
+	linkTypeEthernet = 1
+	pcapSnapLen      = 262144
+)
+
+// pcapngBlock writes a block, the body is padded to 32 bits and the length is repeated at the end
+func pcapngBlock(w io.Writer, blockType uint32, body []byte) error {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	block := make([]byte, 12+len(body))
+	binary.LittleEndian.PutUint32(block[0:], blockType)
+	binary.LittleEndian.PutUint32(block[4:], uint32(len(block)))
+	copy(block[8:], body)
+	binary.LittleEndian.PutUint32(block[len(block)-4:], uint32(len(block)))
+	_, err := w.Write(block)
+	return err
+}
+
+// writePcapng writes the session as pcapng, the direction of each packet is kept in the epb_flags option
+func writePcapng(w io.Writer, packets []sessionPacket) error {
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb[0:], pcapngByteOrder)
+	binary.LittleEndian.PutUint16(shb[4:], 1)
+	binary.LittleEndian.PutUint64(shb[8:], 0xFFFFFFFFFFFFFFFF) // section length not given
+	if err := pcapngBlock(w, pcapngSHB, shb); err != nil {
+		return err
+	}
+	idb := make([]byte, 8)
+	binary.LittleEndian.PutUint16(idb[0:], linkTypeEthernet)
+	binary.LittleEndian.PutUint32(idb[4:], pcapSnapLen)
+	if err := pcapngBlock(w, pcapngIDB, idb); err != nil {
+		return err
+	}
+	builder := newFrameBuilder()
+	for _, pkt := range packets {
+		flags := uint32(2) // outbound
+		if pkt.dir == dirRecv {
+			flags = 1 // inbound
+		}
+		ts := uint64(pkt.time.UnixMicro())
+		for _, frame := range builder.frames(pkt) {
+			epb := make([]byte, 20, 20+len(frame)+16)
+			binary.LittleEndian.PutUint32(epb[4:], uint32(ts>>32))
+			binary.LittleEndian.PutUint32(epb[8:], uint32(ts))
+			binary.LittleEndian.PutUint32(epb[12:], uint32(len(frame)))
+			binary.LittleEndian.PutUint32(epb[16:], uint32(len(frame)))
+			epb = append(epb, frame...)
+			for len(epb)%4 != 0 {
+				epb = append(epb, 0)
+			}
+			option := make([]byte, 12)
+			binary.LittleEndian.PutUint16(option[0:], 2) // epb_flags
+			binary.LittleEndian.PutUint16(option[2:], 4)
+			binary.LittleEndian.PutUint32(option[4:], flags)
+			epb = append(epb, option...) // followed by opt_endofopt
+			if err := pcapngBlock(w, pcapngEPB, epb); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writePcap writes the session in the classic pcap format, which has no direction flags
+func writePcap(w io.Writer, packets []sessionPacket) error {
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(header[4:], 2)
+	binary.LittleEndian.PutUint16(header[6:], 4)
+	binary.LittleEndian.PutUint32(header[16:], pcapSnapLen)
+	binary.LittleEndian.PutUint32(header[20:], linkTypeEthernet)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	builder := newFrameBuilder()
+	for _, pkt := range packets {
+		for _, frame := range builder.frames(pkt) {
+			record := make([]byte, 16, 16+len(frame))
+			binary.LittleEndian.PutUint32(record[0:], uint32(pkt.time.Unix()))
+			binary.LittleEndian.PutUint32(record[4:], uint32(pkt.time.Nanosecond()/1000))
+			binary.LittleEndian.PutUint32(record[8:], uint32(len(frame)))
+			binary.LittleEndian.PutUint32(record[12:], uint32(len(frame)))
+			if _, err := w.Write(append(record, frame...)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportSession writes the session traffic to a file, .pcap files get the classic format and anything else pcapng
+func exportSession(fileName string, packets []sessionPacket) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	if strings.EqualFold(filepath.Ext(fileName), ".pcap") {
+		err = writePcap(w, packets)
+	} else {
+		err = writePcapng(w, packets)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (app *NetAssistantApp) onBtnExportPcap() {
+	packets := app.session.snapshot()
+	if len(packets) == 0 {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(getI18nText(IT_SESSION_EMPTY))))
+		return
+	}
+	dialog, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_EXPORT_PCAP), app.appWindow, gtk.FILE_CHOOSER_ACTION_SAVE, "Save", "Cancel")
+	dialog.FileChooser.SetCurrentName("session.pcapng")
+	res := dialog.Run()
+	fileName := dialog.FileChooser.GetFilename()
+	dialog.Destroy()
+	if res != int(gtk.RESPONSE_ACCEPT) {
+		return
+	}
+	if err := exportSession(fileName, packets); err != nil {
+		log.Error(err)
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// descriptorSet is a loaded FileDescriptorSet as written by protoc --descriptor_set_out --include_imports
+type descriptorSet struct {
+	files       *protoregistry.Files
+	defaultName string // first message of the last file, protoc lists the requested file last
+}
+
+func loadDescriptorSet(fileName string) (*descriptorSet, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("not a descriptor set: %s", err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+	result := &descriptorSet{files: files}
+	for _, file := range set.File {
+		if len(file.MessageType) > 0 {
+			result.defaultName = file.GetPackage() + "." + file.MessageType[0].GetName()
+			result.defaultName = strings.TrimPrefix(result.defaultName, ".")
+		}
+	}
+	return result, nil
+}
+
+// decoder decodes payloads as the named message, an empty name is the default message
+func (s *descriptorSet) decoder(name string) (payloadDecoder, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), ".")
+	if name == "" {
+		name = s.defaultName
+	}
+	if name == "" {
+		return nil, errors.New("the descriptor set has no messages")
+	}
+	desc, err := s.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("message %s: %s", name, err)
+	}
+	md, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a message", name)
+	}
+	return func(data []byte) (string, error) {
+		msg := dynamicpb.NewMessage(md)
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return "", err
+		}
+		text, err := prototext.MarshalOptions{Multiline: true, Indent: "  ", EmitUnknown: true}.Marshal(msg)
+		if err != nil {
+			return "", err
+		}
+		return string(md.FullName()) + "\n" + string(text), nil
+	}, nil
+}
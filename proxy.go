@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// proxy kinds, in the order of the proxy combo
+const (
+	proxyNone = iota
+	proxySOCKS4a
+	proxySOCKS5
+	proxyHTTP
+)
+
+const proxyTimeout = 10 * time.Second
+
+const (
+	socks4Version  = 4
+	socks5Version  = 5
+	socksConnect   = 1
+	socksAssociate = 3
+
+	socksAtypIPv4   = 1
+	socksAtypDomain = 3
+	socksAtypIPv6   = 4
+
+	socksAuthNone     = 0
+	socksAuthPassword = 2
+	socksAuthRefused  = 0xFF
+)
+
+// SOCKS5 reply codes, RFC 1928 section 6
+var socks5Replies = map[byte]string{
+	0: "succeeded",
+	1: "general SOCKS server failure",
+	2: "connection not allowed by ruleset",
+	3: "network unreachable",
+	4: "host unreachable",
+	5: "connection refused",
+	6: "TTL expired",
+	7: "command not supported",
+	8: "address type not supported",
+}
+
+// socks5ReplyError is a failure reply of a SOCKS5 proxy
+type socks5ReplyError byte
+
+func (e socks5ReplyError) Error() string {
+	if reason, ok := socks5Replies[byte(e)]; ok {
+		return reason
+	}
+	return fmt.Sprintf("reply %d", byte(e))
+}
+
+// atTarget reports whether the proxy could not reach the target, the other codes are failures of the proxy itself
+func (e socks5ReplyError) atTarget() bool {
+	return e >= 3 && e <= 6
+}
+
+// SOCKS4 reply codes
+var socks4Replies = map[byte]string{
+	90: "request granted",
+	91: "request rejected or failed",
+	92: "identd not reachable",
+	93: "identd user mismatch",
+}
+
+type proxyConfig struct {
+	kind     int
+	addr     string
+	user     string
+	password string
+}
+
+// proxyError tells which hop of a proxied connection failed
+type proxyError struct {
+	hop string
+	err error
+}
+
+func (e *proxyError) Error() string {
+	return e.hop + ": " + e.err.Error()
+}
+
+// socksAppendAddr appends the SOCKS5 address of host:port, host names are sent to the proxy unresolved
+func socksAppendAddr(buf []byte, addr string) ([]byte, error) {
+	host, strPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(strPort, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", strPort)
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip.To4() != nil:
+		buf = append(append(buf, socksAtypIPv4), ip.To4()...)
+	case ip != nil:
+		buf = append(append(buf, socksAtypIPv6), ip.To16()...)
+	case len(host) > 255:
+		return nil, fmt.Errorf("host name too long: %s", host)
+	default:
+		buf = append(append(buf, socksAtypDomain, byte(len(host))), host...)
+	}
+	return binary.BigEndian.AppendUint16(buf, uint16(port)), nil
+}
+
+// socksParseAddr reads a SOCKS5 address from the start of buf and returns it with its length
+func socksParseAddr(buf []byte) (string, int, error) {
+	if len(buf) < 1 {
+		return "", 0, io.ErrUnexpectedEOF
+	}
+	var host string
+	n := 1
+	switch buf[0] {
+	case socksAtypIPv4:
+		n += net.IPv4len
+	case socksAtypIPv6:
+		n += net.IPv6len
+	case socksAtypDomain:
+		if len(buf) < 2 {
+			return "", 0, io.ErrUnexpectedEOF
+		}
+		n += 1 + int(buf[1])
+	default:
+		return "", 0, fmt.Errorf("address type %d not supported", buf[0])
+	}
+	if len(buf) < n+2 {
+		return "", 0, io.ErrUnexpectedEOF
+	}
+	if buf[0] == socksAtypDomain {
+		host = string(buf[2:n])
+	} else {
+		host = net.IP(buf[1:n]).String()
+	}
+	port := binary.BigEndian.Uint16(buf[n:])
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), n + 2, nil
+}
+
+// socksReadAddr reads a SOCKS5 address from a stream
+func socksReadAddr(r io.Reader) (string, error) {
+	buf := make([]byte, 2, 2+255+2)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	var rest int
+	switch buf[0] {
+	case socksAtypIPv4:
+		rest = net.IPv4len + 1
+	case socksAtypIPv6:
+		rest = net.IPv6len + 1
+	case socksAtypDomain:
+		rest = int(buf[1]) + 2
+	default:
+		return "", fmt.Errorf("address type %d not supported", buf[0])
+	}
+	buf = buf[:2+rest]
+	if _, err := io.ReadFull(r, buf[2:]); err != nil {
+		return "", err
+	}
+	addr, _, err := socksParseAddr(buf)
+	return addr, err
+}
+
+// socks5Auth negotiates the authentication method, username/password when a user is given
+func socks5Auth(conn net.Conn, user, password string) error {
+	greeting := []byte{socks5Version, 1, socksAuthNone}
+	if user != "" {
+		greeting = []byte{socks5Version, 2, socksAuthNone, socksAuthPassword}
+	}
+	if _, err := conn.Write(greeting); err != nil {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	switch {
+	case reply[0] != socks5Version:
+		return fmt.Errorf("not a SOCKS5 proxy, version %d", reply[0])
+	case reply[1] == socksAuthNone:
+		return nil
+	case reply[1] == socksAuthPassword && user != "":
+	case reply[1] == socksAuthRefused:
+		return errors.New("no acceptable authentication method")
+	default:
+		return fmt.Errorf("unsupported authentication method %d", reply[1])
+	}
+	if len(user) > 255 || len(password) > 255 {
+		return errors.New("user name or password too long")
+	}
+	req := append([]byte{1, byte(len(user))}, user...)
+	req = append(append(req, byte(len(password))), password...)
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[1] != 0 {
+		return errors.New("authentication rejected")
+	}
+	return nil
+}
+
+// socks5Request sends a command and returns the address bound by the proxy
+func socks5Request(conn net.Conn, cmd byte, addr string) (string, error) {
+	req, err := socksAppendAddr([]byte{socks5Version, cmd, 0}, addr)
+	if err != nil {
+		return "", err
+	}
+	if _, err := conn.Write(req); err != nil {
+		return "", err
+	}
+	reply := make([]byte, 3)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return "", err
+	}
+	if reply[1] != 0 {
+		return "", socks5ReplyError(reply[1])
+	}
+	return socksReadAddr(conn)
+}
+
+// socks4aConnect connects with SOCKS4, host names use the 4a extension
+func socks4aConnect(conn net.Conn, addr, user string) error {
+	host, strPort, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(strPort, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid port %q", strPort)
+	}
+	req := binary.BigEndian.AppendUint16([]byte{socks4Version, socksConnect}, uint16(port))
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		req = append(req, 0, 0, 0, 1)
+	} else {
+		req = append(req, ip...)
+	}
+	req = append(append(req, user...), 0)
+	if ip == nil {
+		req = append(append(req, host...), 0)
+	}
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+	reply := make([]byte, 8)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return err
+	}
+	if reply[1] != 90 {
+		reason, ok := socks4Replies[reply[1]]
+		if !ok {
+			reason = fmt.Sprintf("reply %d", reply[1])
+		}
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// httpConnect opens a tunnel with CONNECT, the response is read byte by byte so no tunnel data is consumed
+func httpConnect(conn net.Conn, addr, user, password string) error {
+	req := fmt.Sprintf("CONNECT %s HTTP/1.1\r\nHost: %s\r\n", addr, addr)
+	if user != "" {
+		req += "Proxy-Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password)) + "\r\n"
+	}
+	if _, err := io.WriteString(conn, req+"\r\n"); err != nil {
+		return err
+	}
+	var head []byte
+	b := make([]byte, 1)
+	for !bytes.HasSuffix(head, []byte("\r\n\r\n")) && !bytes.HasSuffix(head, []byte("\n\n")) {
+		if len(head) > httpMaxHead {
+			return errors.New("http: header too large")
+		}
+		if _, err := conn.Read(b); err != nil {
+			return err
+		}
+		head = append(head, b[0])
+	}
+	msg, _, err := parseHTTPMessage(head, true, false)
+	if err != nil {
+		return err
+	}
+	if code := msg.status(); code/100 != 2 {
+		return fmt.Errorf("proxy answered %q", msg.startLine)
+	}
+	return nil
+}
+
+// socksUDPConn sends datagrams to one target through a SOCKS5 UDP relay, the association lives as long as ctrl
+type socksUDPConn struct {
+	*net.UDPConn
+	ctrl   net.Conn
+	target net.Addr
+	header []byte
+}
+
+// socksAddr is a target that only the proxy can resolve
+type socksAddr string
+
+func (a socksAddr) Network() string { return "udp" }
+func (a socksAddr) String() string  { return string(a) }
+
+// RemoteAddr is the target, not the relay the socket is connected to
+func (c *socksUDPConn) RemoteAddr() net.Addr {
+	return c.target
+}
+
+func (c *socksUDPConn) Write(p []byte) (int, error) {
+	if _, err := c.UDPConn.Write(append(c.header[:len(c.header):len(c.header)], p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Read returns the payload of the next unfragmented datagram of the relay
+func (c *socksUDPConn) Read(p []byte) (int, error) {
+	buf := make([]byte, 65536)
+	for {
+		n, err := c.UDPConn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		if n < 4 || buf[2] != 0 {
+			continue
+		}
+		_, size, err := socksParseAddr(buf[3:n])
+		if err != nil {
+			continue
+		}
+		return copy(p, buf[3+size:n]), nil
+	}
+}
+
+func (c *socksUDPConn) Close() error {
+	c.ctrl.Close()
+	return c.UDPConn.Close()
+}
+
+// dialSocksUDP asks the proxy for a UDP relay and connects a local socket to it
+func dialSocksUDP(ctrl net.Conn, proxyAddr, target string) (net.Conn, error) {
+	relay, err := socks5Request(ctrl, socksAssociate, "0.0.0.0:0")
+	if err != nil {
+		return nil, err
+	}
+	host, port, _ := net.SplitHostPort(relay)
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host, _, _ = net.SplitHostPort(proxyAddr)
+	}
+	udpAddr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	udpConn, err := net.DialUDP("udp", nil, udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	header, err := socksAppendAddr([]byte{0, 0, 0}, target)
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	conn := &socksUDPConn{UDPConn: udpConn, ctrl: ctrl, target: socksAddr(target), header: header}
+	if targetAddr, err := net.ResolveUDPAddr("udp", target); err == nil {
+		conn.target = targetAddr
+	}
+	go func() {
+		// the relay ends with the control connection
+		io.Copy(io.Discard, ctrl)
+		udpConn.Close()
+	}()
+	return conn, nil
+}
+
+// dialProxy connects to addr through the proxy, errors name the hop that failed
+func dialProxy(cfg proxyConfig, network, addr string) (net.Conn, error) {
+	hop := "proxy " + cfg.addr
+	isUDP := strings.HasPrefix(network, "udp")
+	if isUDP && cfg.kind != proxySOCKS5 {
+		return nil, &proxyError{hop, errors.New("UDP needs a SOCKS5 proxy")}
+	}
+	conn, err := net.DialTimeout("tcp", cfg.addr, proxyTimeout)
+	if err != nil {
+		return nil, &proxyError{hop, err}
+	}
+	conn.SetDeadline(time.Now().Add(proxyTimeout))
+	fail := func(hop string, err error) (net.Conn, error) {
+		conn.Close()
+		return nil, &proxyError{hop, err}
+	}
+	target := hop + " -> target " + addr
+	// a SOCKS5 reply names the hop that failed
+	requestHop := func(err error) string {
+		var reply socks5ReplyError
+		if errors.As(err, &reply) && !reply.atTarget() {
+			return hop
+		}
+		return target
+	}
+	switch cfg.kind {
+	case proxySOCKS4a:
+		if err := socks4aConnect(conn, addr, cfg.user); err != nil {
+			return fail(target, err)
+		}
+	case proxySOCKS5:
+		if err := socks5Auth(conn, cfg.user, cfg.password); err != nil {
+			return fail(hop, err)
+		}
+		if isUDP {
+			udpConn, err := dialSocksUDP(conn, cfg.addr, addr)
+			if err != nil {
+				return fail(requestHop(err), err)
+			}
+			conn.SetDeadline(time.Time{})
+			return udpConn, nil
+		}
+		if _, err := socks5Request(conn, socksConnect, addr); err != nil {
+			return fail(requestHop(err), err)
+		}
+	case proxyHTTP:
+		if err := httpConnect(conn, addr, cfg.user, cfg.password); err != nil {
+			return fail(target, err)
+		}
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+func (app *NetAssistantApp) proxySettings() proxyConfig {
+	cfg := proxyConfig{kind: app.combProxyType.GetActive()}
+	cfg.addr, _ = app.entryProxyAddr.GetText()
+	cfg.addr = strings.TrimSpace(cfg.addr)
+	cfg.user, _ = app.entryProxyUser.GetText()
+	cfg.password, _ = app.entryProxyPassword.GetText()
+	return cfg
+}
+
+// dialClient opens the connection of the TCP and UDP client modes, through the proxy when one is set
+func (app *NetAssistantApp) dialClient(network, addr string) (net.Conn, error) {
+	cfg := app.proxySettings()
+	if cfg.kind == proxyNone {
+		return net.Dial(network, addr)
+	}
+	return dialProxy(cfg, network, addr)
+}
+
+func (app *NetAssistantApp) createProxyPage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	box.SetBorderWidth(10)
+
+	labelHint, _ := gtk.LabelNew(getI18nText(IT_PROXY_HINT))
+	labelHint.SetLineWrap(true)
+	labelHint.SetMaxWidthChars(30)
+	labelHint.SetXAlign(0)
+	app.combProxyType, _ = gtk.ComboBoxTextNew()
+	for _, key := range []string{IT_PROXY_NONE, IT_PROXY_SOCKS4A, IT_PROXY_SOCKS5, IT_PROXY_HTTP} {
+		app.combProxyType.AppendText(getI18nText(key))
+	}
+	app.combProxyType.SetActive(proxyNone)
+	app.entryProxyAddr, _ = gtk.EntryNew()
+	app.entryProxyAddr.SetPlaceholderText(getI18nText(IT_PROXY_ADDR))
+	app.entryProxyUser, _ = gtk.EntryNew()
+	app.entryProxyUser.SetPlaceholderText(getI18nText(IT_USERNAME))
+	app.entryProxyPassword, _ = gtk.EntryNew()
+	app.entryProxyPassword.SetPlaceholderText(getI18nText(IT_PASSWORD))
+	app.entryProxyPassword.SetVisibility(false)
+
+	box.PackStart(labelHint, false, false, 0)
+	box.PackStart(app.combProxyType, false, false, 0)
+	box.PackStart(app.entryProxyAddr, false, false, 0)
+	box.PackStart(app.entryProxyUser, false, false, 0)
+	box.PackStart(app.entryProxyPassword, false, false, 0)
+	return box
+}
@@ -0,0 +1,500 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// bytes per row choices of the hex dump view
+var dumpWidths = []int{8, 16, 32}
+
+const (
+	tagDump       = "dump"
+	tagDumpSelect = "dump-select"
+)
+
+// default cap of the receive area
+const (
+	defaultMaxLines = 10000
+	defaultMaxBytes = 4 << 20
+)
+
+// received data waits here until the next periodic update of the receive area
+const recvFlushInterval = 100 // ms
+
+// beyond this many queued records the reads of a connection are merged into one record
+const recvMergeThreshold = 256
+
+type recvQueue struct {
+	mu      sync.Mutex
+	records []*recvRecord
+}
+
+func (q *recvQueue) push(rec *recvRecord) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if n := len(q.records); n >= recvMergeThreshold {
+		last := q.records[n-1]
+		if last.dir == dirRecv && rec.dir == dirRecv && last.peer == rec.peer && last.tag == "" && rec.tag == "" {
+			last.data = append(last.data, rec.data...)
+			return
+		}
+	}
+	q.records = append(q.records, rec)
+}
+
+func (q *recvQueue) take() []*recvRecord {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	recs := q.records
+	q.records = nil
+	return recs
+}
+
+// direction of a record
+const (
+	dirRecv = iota // data received from a peer
+	dirLog         // output of the tools, kept in place between the data
+	dirSend        // data sent to a peer
+)
+
+// viewOptions are the display options that apply to all records
+type viewOptions struct {
+	hex  bool
+	date bool
+	peer bool
+	rtt  bool
+}
+
+// recvRecord is a chunk of received data shown in the receive area, the raw bytes are kept so the view can change
+type recvRecord struct {
+	dir       int
+	data      []byte
+	time      time.Time
+	peer      string
+	tag       string // message details such as an MQTT topic, always shown before the data
+	sinceSend time.Duration
+	sincePrev time.Duration
+	dump      bool
+	width     int
+	text      string // data decoded with the receive encoding
+	decoded   string // output of the payload decoder, shown below the data
+
+	name   string
+	mark   *gtk.TextMark // start of the record text, right gravity so re-rendering the previous record pushes it along
+	length int           // chars of the rendered text
+	lines  int           // line breaks in the rendered text
+	dataAt int           // char offset of the data or hex dump in the rendered text
+}
+
+func (rec *recvRecord) prefix(opts viewOptions) string {
+	prefix := ""
+	if opts.date {
+		prefix = fmt.Sprintf("[%s]", rec.time.Format(time.DateTime+".000000"))
+	}
+	if opts.peer && rec.peer != "" {
+		prefix += fmt.Sprintf("[%s]", rec.peer)
+	}
+	if opts.rtt {
+		prefix += formatElapsed(rec.sinceSend, rec.sincePrev)
+	}
+	if rec.tag != "" {
+		prefix += fmt.Sprintf("[%s]", rec.tag)
+	}
+	return prefix
+}
+
+// format returns the text of the record and the offset of its data, a hex dump always starts on a new line
+func (rec *recvRecord) format(opts viewOptions, atLineStart bool) (string, int) {
+	if rec.dir == dirLog {
+		return rec.text + "\n", 0
+	}
+	prefix := rec.prefix(opts)
+	if !rec.dump {
+		text := rec.text
+		if opts.hex {
+			text = hexString(rec.data)
+		}
+		if prefix != "" {
+			text = prefix + text + "\n"
+		}
+		if rec.decoded != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		return text + rec.decoded, utf8.RuneCountInString(prefix)
+	}
+	head := ""
+	if !atLineStart {
+		head = "\n"
+	}
+	if prefix != "" {
+		head += prefix + "\n"
+	}
+	return head + hexDump(rec.data, rec.width) + rec.decoded, utf8.RuneCountInString(head)
+}
+
+// render formats the record for the receive area
+func (rec *recvRecord) render(opts viewOptions, atLineStart bool) string {
+	text, dataAt := rec.format(opts, atLineStart)
+	rec.dataAt = dataAt
+	return text
+}
+
+func (app *NetAssistantApp) viewOptions() viewOptions {
+	return viewOptions{
+		hex:  app.cbHexDisplay.GetActive(),
+		date: app.cbDisplayDate.GetActive(),
+		peer: app.cbShowPeer.GetActive(),
+		rtt:  app.cbShowRTT.GetActive(),
+	}
+}
+
+func (app *NetAssistantApp) dumpWidth() int {
+	if index := app.combDumpWidth.GetActive(); index >= 0 && index < len(dumpWidths) {
+		return dumpWidths[index]
+	}
+	return 16
+}
+
+func (app *NetAssistantApp) createRecvTags() {
+	app.tbReceData.CreateTag(tagDump, map[string]interface{}{"family": "monospace"})
+	app.tbReceData.CreateTag(tagDumpSelect, map[string]interface{}{"background": "#FFD54F"})
+	app.tbReceData.CreateTag(tagSearchMatch, map[string]interface{}{"background": "#A5D6A7"})
+	app.tbReceData.CreateTag(tagSearchCurrent, map[string]interface{}{"background": "#FF8A65"})
+	app.tbReceData.CreateTag(tagFiltered, map[string]interface{}{"invisible": true})
+	app.selFrom, app.selTo = -1, -1
+	app.tbReceData.Connect("mark-set", app.onRecvSelectionChanged)
+}
+
+// decodeRecord decodes the record with the state of its connection, records must be decoded in order
+func (app *NetAssistantApp) decodeRecord(rec *recvRecord) {
+	if rec.dir == dirLog {
+		rec.text = string(rec.data)
+		return
+	}
+	if app.recvDecoders == nil {
+		app.recvDecoders = map[string]*streamDecoder{}
+	}
+	dec := app.recvDecoders[rec.peer]
+	if dec == nil {
+		dec = newStreamDecoder(textEncodingAt(app.combRecvEncoding.GetActive()))
+		app.recvDecoders[rec.peer] = dec
+	}
+	rec.text = dec.decode(rec.data)
+	rec.decoded = app.decodePayload(rec.data)
+}
+
+// appendRecords adds records at the end of the receive area with a single insert and returns their text
+func (app *NetAssistantApp) appendRecords(recs []*recvRecord) string {
+	opts := app.viewOptions()
+	iter := app.tbReceData.GetEndIter()
+	offset := iter.GetOffset()
+	atLineStart := iter.StartsLine()
+	var sb strings.Builder
+	offsets := make([]int, len(recs))
+	for i, rec := range recs {
+		app.recordSeq++
+		rec.name = fmt.Sprintf("record%d", app.recordSeq)
+		app.decodeRecord(rec)
+		text := rec.render(opts, atLineStart)
+		sb.WriteString(text)
+		offsets[i] = offset
+		rec.length = utf8.RuneCountInString(text)
+		rec.lines = strings.Count(text, "\n")
+		offset += rec.length
+		atLineStart = strings.HasSuffix(text, "\n") || (atLineStart && text == "")
+		app.recordBytes += len(rec.data)
+	}
+	app.tbReceData.Insert(iter, sb.String())
+	for i, rec := range recs {
+		rec.mark = app.tbReceData.CreateMark(rec.name, app.tbReceData.GetIterAtOffset(offsets[i]), false)
+		app.tagRecord(rec, offsets[i])
+	}
+	app.records = append(app.records, recs...)
+	app.trimRecords()
+	// the trim may also have taken the oldest of the new records
+	if len(recs) > len(app.records) {
+		recs = recs[len(recs)-len(app.records):]
+	}
+	app.decorate(recs, false)
+	return sb.String()
+}
+
+// recvLimits reads the size cap of the receive area
+func (app *NetAssistantApp) recvLimits() (maxLines, maxBytes int) {
+	maxLines, maxBytes = defaultMaxLines, defaultMaxBytes
+	strLines, _ := app.entryMaxLines.GetText()
+	if value, err := parseLimit(strLines); err == nil && value > 0 {
+		maxLines = int(value)
+	}
+	strBytes, _ := app.entryMaxBytes.GetText()
+	if value, err := parseLimit(strBytes); err == nil && value > 0 {
+		maxBytes = int(value)
+	}
+	return
+}
+
+// trimRecords discards the oldest records until the receive area is within its cap, the newest record always stays.
+// The tags of the kept records move with their text and the search matches are relative to their record,
+// so only the matches of the discarded records are dropped.
+func (app *NetAssistantApp) trimRecords() {
+	maxLines, maxBytes := app.recvLimits()
+	lines := app.tbReceData.GetLineCount()
+	drop := 0
+	for drop < len(app.records)-1 && (lines > maxLines || app.recordBytes > maxBytes) {
+		rec := app.records[drop]
+		lines -= rec.lines
+		app.recordBytes -= len(rec.data)
+		drop++
+	}
+	if drop == 0 {
+		return
+	}
+	keep := app.records[drop]
+	app.tbReceData.Delete(app.tbReceData.GetStartIter(), app.tbReceData.GetIterAtMark(keep.mark))
+	dropped := map[*recvRecord]bool{}
+	for _, rec := range app.records[:drop] {
+		app.tbReceData.DeleteMark(rec.mark)
+		dropped[rec] = true
+	}
+	app.records = append([]*recvRecord(nil), app.records[drop:]...)
+	// the matches are in record order, so those of the discarded records come first
+	n := 0
+	for n < len(app.searchMatches) && dropped[app.searchMatches[n].rec] {
+		n++
+	}
+	if n > 0 {
+		app.searchMatches = append([]searchMatch(nil), app.searchMatches[n:]...)
+		app.searchIndex -= n
+		if app.searchIndex < 0 {
+			app.searchIndex = -1
+		}
+	}
+	app.refreshSelection()
+}
+
+// renderRecords rebuilds the receive area from the records after a display option changed
+func (app *NetAssistantApp) renderRecords() {
+	opts := app.viewOptions()
+	var sb strings.Builder
+	offsets := make([]int, len(app.records))
+	offset := 0
+	app.recvDecoders = nil
+	for i, rec := range app.records {
+		app.tbReceData.DeleteMark(rec.mark)
+		app.decodeRecord(rec)
+		text := rec.render(opts, sb.Len() == 0 || strings.HasSuffix(sb.String(), "\n"))
+		sb.WriteString(text)
+		offsets[i] = offset
+		rec.length = utf8.RuneCountInString(text)
+		rec.lines = strings.Count(text, "\n")
+		offset += rec.length
+	}
+	app.tbReceData.SetText(sb.String())
+	for i, rec := range app.records {
+		rec.mark = app.tbReceData.CreateMark(rec.name, app.tbReceData.GetIterAtOffset(offsets[i]), false)
+		app.tagRecord(rec, offsets[i])
+	}
+	app.decorate(nil, true)
+	app.refreshSelection()
+	app.scrollRecvToEnd()
+}
+
+// flushRecv moves the queued records into the receive area
+func (app *NetAssistantApp) flushRecv() bool {
+	app.updateRecvCount()
+	recs := app.recvQueue.take()
+	if len(recs) == 0 {
+		return true
+	}
+	app.labelRTT.SetText(app.rtt.summary())
+	for _, rec := range recs {
+		rec.dump = rec.dir == dirRecv && app.cbHexDump.GetActive()
+		rec.width = app.dumpWidth()
+	}
+	// records that would be trimmed right away are only decoded to keep the decoders in step with the stream
+	show := 0
+	if !app.cbPauseDisplay.GetActive() {
+		_, maxBytes := app.recvLimits()
+		size := 0
+		for show < len(recs) && (show == 0 || size+len(recs[len(recs)-1-show].data) <= maxBytes) {
+			size += len(recs[len(recs)-1-show].data)
+			show++
+		}
+	}
+	for _, rec := range recs[:len(recs)-show] {
+		app.decodeRecord(rec)
+	}
+	if show > 0 {
+		app.appendRecords(recs[len(recs)-show:])
+		app.scrollRecvToEnd()
+	}
+	return true
+}
+
+func (app *NetAssistantApp) updateRecvCount() {
+	app.labelReceveCount.SetText(getI18nText(IT_RECEVER_COUNT) + strconv.FormatInt(atomic.LoadInt64(&app.receCount), 10))
+}
+
+func (app *NetAssistantApp) scrollRecvToEnd() {
+	iter := app.tbReceData.GetEndIter()
+	app.tbReceData.CreateMark(getI18nText(IT_END), iter, false)
+	mark := app.tbReceData.GetMark(getI18nText(IT_END))
+	app.tvDataReceive.ScrollMarkOnscreen(mark)
+}
+
+// rerenderRecord replaces the text of a record after its view options changed
+func (app *NetAssistantApp) rerenderRecord(rec *recvRecord) {
+	offset := app.tbReceData.GetIterAtMark(rec.mark).GetOffset()
+	app.tbReceData.Delete(app.tbReceData.GetIterAtOffset(offset), app.tbReceData.GetIterAtOffset(offset+rec.length))
+	iter := app.tbReceData.GetIterAtOffset(offset)
+	text := rec.render(app.viewOptions(), iter.StartsLine())
+	app.tbReceData.Insert(iter, text)
+	rec.length = utf8.RuneCountInString(text)
+	rec.lines = strings.Count(text, "\n")
+	// the insert pushed the right gravity mark behind the new text
+	app.tbReceData.DeleteMark(rec.mark)
+	rec.mark = app.tbReceData.CreateMark(rec.name, app.tbReceData.GetIterAtOffset(offset), false)
+	app.tagRecord(rec, offset)
+}
+
+func (app *NetAssistantApp) tagRecord(rec *recvRecord, offset int) {
+	if rec.dump {
+		app.tbReceData.ApplyTagByName(tagDump, app.tbReceData.GetIterAtOffset(offset+rec.dataAt), app.tbReceData.GetIterAtOffset(offset+rec.length))
+	}
+}
+
+func (app *NetAssistantApp) clearRecords() {
+	for _, rec := range app.records {
+		app.tbReceData.DeleteMark(rec.mark)
+	}
+	app.records = nil
+	app.searchMatches = nil
+	app.searchIndex = -1
+	app.recordBytes = 0
+	app.recvDecoders = nil
+	app.selFrom, app.selTo = -1, -1
+}
+
+func (app *NetAssistantApp) onCbHexDump() {
+	for _, rec := range app.records {
+		rec.dump = app.cbHexDump.GetActive()
+		rec.width = app.dumpWidth()
+	}
+	app.renderRecords()
+}
+
+// saveRecvData replaces the file with the received bytes exactly as they arrived when raw is set, with the displayed text otherwise
+func (app *NetAssistantApp) saveRecvData(fileName string, raw bool) error {
+	if !raw {
+		return os.WriteFile(fileName, []byte(app.getRecvData()), 0644)
+	}
+	data := []byte{}
+	for _, rec := range app.records {
+		if rec.dir == dirRecv {
+			data = append(data, rec.data...)
+		}
+	}
+	return os.WriteFile(fileName, data, 0644)
+}
+
+// onBtnToggleDump switches the records under the cursor or selection between text and hex dump
+func (app *NetAssistantApp) onBtnToggleDump() {
+	start, end, _ := app.tbReceData.GetSelectionBounds()
+	from, to := start.GetOffset(), end.GetOffset()
+	for _, rec := range app.records {
+		offset := app.tbReceData.GetIterAtMark(rec.mark).GetOffset()
+		if from == to && (from < offset || from >= offset+rec.length) {
+			continue
+		}
+		if from != to && (to <= offset || from >= offset+rec.length) {
+			continue
+		}
+		rec.dump = !rec.dump
+		rec.width = app.dumpWidth()
+		app.rerenderRecord(rec)
+	}
+	app.decorate(nil, true)
+	app.refreshSelection()
+}
+
+func (app *NetAssistantApp) onCombDumpWidthChanged() {
+	for _, rec := range app.records {
+		rec.width = app.dumpWidth()
+	}
+	app.renderRecords()
+}
+
+func (app *NetAssistantApp) refreshSelection() {
+	app.selFrom, app.selTo = -1, -1
+	app.onRecvSelectionChanged()
+}
+
+// onRecvSelectionChanged highlights the selected bytes of hex dumps in both the hex and the ascii column
+func (app *NetAssistantApp) onRecvSelectionChanged() {
+	start, end, ok := app.tbReceData.GetSelectionBounds()
+	from, to := -1, -1
+	if ok {
+		from, to = start.GetOffset(), end.GetOffset()
+	}
+	if from == app.selFrom && to == app.selTo {
+		return
+	}
+	app.selFrom, app.selTo = from, to
+	first, last := app.tbReceData.GetBounds()
+	app.tbReceData.RemoveTagByName(tagDumpSelect, first, last)
+	if !ok {
+		return
+	}
+	for _, rec := range app.records {
+		if !rec.dump {
+			continue
+		}
+		offset := app.tbReceData.GetIterAtMark(rec.mark).GetOffset() + rec.dataAt
+		size := rec.length - rec.dataAt
+		if to <= offset || from >= offset+size {
+			continue
+		}
+		layout := dumpLayout{width: rec.width}
+		lo, hi := -1, -1
+		pos := from
+		if pos < offset {
+			pos = offset
+		}
+		for ; pos < to && pos < offset+size; pos++ {
+			if index, ok := layout.byteAt(pos-offset, len(rec.data)); ok {
+				if lo < 0 {
+					lo = index
+				}
+				hi = index
+			}
+		}
+		if lo < 0 {
+			continue
+		}
+		for row := lo / rec.width; row <= hi/rec.width; row++ {
+			c1, c2 := 0, rec.width-1
+			if row == lo/rec.width {
+				c1 = lo % rec.width
+			}
+			if row == hi/rec.width {
+				c2 = hi % rec.width
+			}
+			base := offset + row*layout.rowLen()
+			app.highlight(base+layout.hexCol(c1), base+layout.hexCol(c2)+2)
+			app.highlight(base+layout.asciiCol(c1), base+layout.asciiCol(c2)+1)
+		}
+	}
+}
+
+func (app *NetAssistantApp) highlight(from, to int) {
+	app.tbReceData.ApplyTagByName(tagDumpSelect, app.tbReceData.GetIterAtOffset(from), app.tbReceData.GetIterAtOffset(to))
+}
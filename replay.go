@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// replay timing modes
+const (
+	replayOriginal = iota // gaps between packets as captured
+	replayScaled          // captured gaps divided by the speed factor
+	replayFast            // no gaps
+)
+
+// replayRunner sends the packets of a stream, while paused each step sends one packet
+type replayRunner struct {
+	packets []replayPacket
+	timing  int
+	speed   float64
+
+	*pacedRunner
+	progress func(index, total int)
+}
+
+func newReplayRunner(paced *pacedRunner, packets []replayPacket, timing int, speed float64) *replayRunner {
+	return &replayRunner{pacedRunner: paced, packets: packets, timing: timing, speed: speed}
+}
+
+// delay is the wait before packet index, packets without timestamps are sent back to back
+func (r *replayRunner) delay(index int) time.Duration {
+	if index == 0 || r.timing == replayFast {
+		return 0
+	}
+	prev, cur := r.packets[index-1].time, r.packets[index].time
+	if prev.IsZero() || cur.IsZero() {
+		return 0
+	}
+	gap := cur.Sub(prev)
+	if r.timing == replayScaled && r.speed > 0 {
+		gap = time.Duration(float64(gap) / r.speed)
+	}
+	return gap
+}
+
+func (r *replayRunner) run() {
+	defer r.finished()
+	total := len(r.packets)
+	sent := 0
+	for index, pkt := range r.packets {
+		r.progress(index, total)
+		// a stepped packet goes out right away, the captured gap only applies while running
+		if !r.isPaused() && !r.sleep(r.delay(index)) {
+			return
+		}
+		if !r.waitTurn() {
+			return
+		}
+		if err := r.send(pkt.data); err != nil {
+			r.report(fmt.Sprintf("packet %d/%d send failed: %s", index+1, total, err))
+			return
+		}
+		sent += len(pkt.data)
+	}
+	r.progress(total, total)
+	r.report(fmt.Sprintf("sent %d packets, %d bytes", total, sent))
+}
+
+func (app *NetAssistantApp) onBtnReplayOpen() {
+	dialog, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_OPEN), app.appWindow, gtk.FILE_CHOOSER_ACTION_OPEN, "Open", "Cancel")
+	res := dialog.Run()
+	fileName := dialog.FileChooser.GetFilename()
+	dialog.Destroy()
+	if res != int(gtk.RESPONSE_ACCEPT) {
+		return
+	}
+	streams, err := loadReplayFile(fileName)
+	if err != nil {
+		log.Error(err)
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
+	app.replayStreams = streams
+	app.labelReplayFile.SetText(filepath.Base(fileName))
+	app.combReplayStream.RemoveAll()
+	for _, stream := range streams {
+		app.combReplayStream.AppendText(fmt.Sprintf("%s (%d, %dB)", stream.name, len(stream.packets), stream.bytes))
+	}
+	app.combReplayStream.SetActive(0)
+	app.pbReplayProgress.SetFraction(0)
+	app.pbReplayProgress.SetText("")
+}
+
+// startReplay runs the selected stream, a paused start waits for steps
+func (app *NetAssistantApp) startReplay(paused bool) {
+	index := app.combReplayStream.GetActive()
+	if index < 0 || index >= len(app.replayStreams) {
+		return
+	}
+	timing := app.combReplayTiming.GetActive()
+	speed := 1.0
+	if timing == replayScaled {
+		strSpeed, _ := app.entryReplaySpeed.GetText()
+		value, err := parseLimit(strSpeed)
+		if err != nil || value == 0 {
+			app.labelStatus.SetMarkup(fmt.Sprintf(`<span foreground="red">invalid speed %q</span>`, glib.MarkupEscapeText(strings.TrimSpace(strSpeed))))
+			return
+		}
+		speed = value
+	}
+	paced := app.newPacedRunner("[REPLAY]", func() {
+		app.replayRunner = nil
+		app.btnReplayRun.SetSensitive(true)
+		app.btnReplayPause.SetLabel(getI18nText(IT_PAUSE))
+		app.btnReplayPause.SetSensitive(false)
+		app.btnReplayStop.SetSensitive(false)
+	})
+	if paced == nil {
+		return
+	}
+	paced.paused = paused
+	runner := newReplayRunner(paced, app.replayStreams[index].packets, timing, speed)
+	var update guiUpdate
+	runner.progress = func(index, total int) {
+		update.set(func() {
+			app.pbReplayProgress.SetFraction(float64(index) / float64(total))
+			app.pbReplayProgress.SetText(fmt.Sprintf("%d/%d", index, total))
+		})
+	}
+
+	app.replayRunner = runner
+	app.btnReplayRun.SetSensitive(false)
+	app.btnReplayPause.SetSensitive(true)
+	if paused {
+		app.btnReplayPause.SetLabel(getI18nText(IT_RESUME))
+	}
+	app.btnReplayStop.SetSensitive(true)
+	go runner.run()
+}
+
+func (app *NetAssistantApp) onBtnReplayRun() {
+	if app.replayRunner == nil {
+		app.startReplay(false)
+	}
+}
+
+func (app *NetAssistantApp) onBtnReplayPause() {
+	if app.replayRunner == nil {
+		return
+	}
+	toggleRunnerPause(app.replayRunner.pacedRunner, app.btnReplayPause)
+}
+
+// onBtnReplayStep sends the next packet, pausing a running replay or starting a paused one
+func (app *NetAssistantApp) onBtnReplayStep() {
+	if app.replayRunner == nil {
+		app.startReplay(true)
+	} else if !app.replayRunner.isPaused() {
+		app.onBtnReplayPause()
+		return
+	}
+	if app.replayRunner != nil {
+		app.replayRunner.step()
+	}
+}
+
+func (app *NetAssistantApp) onBtnReplayStop() {
+	if app.replayRunner != nil {
+		app.replayRunner.stop()
+	}
+}
+
+func (app *NetAssistantApp) createReplayPage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
+	box.SetBorderWidth(10)
+
+	fileBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	btnOpen, _ := gtk.ButtonNewWithLabel(getI18nText(IT_OPEN))
+	btnOpen.Connect("clicked", app.onBtnReplayOpen)
+	app.labelReplayFile, _ = gtk.LabelNew("")
+	fileBox.PackStart(btnOpen, false, false, 0)
+	fileBox.PackStart(app.labelReplayFile, true, true, 0)
+
+	app.combReplayStream, _ = gtk.ComboBoxTextNew()
+	app.combReplayTiming, _ = gtk.ComboBoxTextNew()
+	for _, key := range []string{IT_TIMING_ORIGINAL, IT_TIMING_SCALED, IT_TIMING_FAST} {
+		app.combReplayTiming.AppendText(getI18nText(key))
+	}
+	app.combReplayTiming.SetActive(replayOriginal)
+	app.entryReplaySpeed, _ = gtk.EntryNew()
+	app.entryReplaySpeed.SetPlaceholderText(getI18nText(IT_SPEED))
+	app.pbReplayProgress, _ = gtk.ProgressBarNew()
+	app.pbReplayProgress.SetShowText(true)
+	app.pbReplayProgress.SetText("")
+
+	btnBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	app.btnReplayRun, _ = gtk.ButtonNewWithLabel(getI18nText(IT_RUN))
+	app.btnReplayRun.Connect("clicked", app.onBtnReplayRun)
+	app.btnReplayPause, _ = gtk.ButtonNewWithLabel(getI18nText(IT_PAUSE))
+	app.btnReplayPause.Connect("clicked", app.onBtnReplayPause)
+	app.btnReplayPause.SetSensitive(false)
+	btnStep, _ := gtk.ButtonNewWithLabel(getI18nText(IT_STEP))
+	btnStep.Connect("clicked", app.onBtnReplayStep)
+	app.btnReplayStop, _ = gtk.ButtonNewWithLabel(getI18nText(IT_STOP))
+	app.btnReplayStop.Connect("clicked", app.onBtnReplayStop)
+	app.btnReplayStop.SetSensitive(false)
+	btnBox.PackStart(app.btnReplayRun, true, true, 0)
+	btnBox.PackStart(app.btnReplayPause, true, true, 0)
+	btnBox.PackStart(btnStep, true, true, 0)
+	btnBox.PackStart(app.btnReplayStop, true, true, 0)
+
+	box.PackStart(fileBox, false, false, 0)
+	box.PackStart(app.combReplayStream, false, false, 0)
+	box.PackStart(app.combReplayTiming, false, false, 0)
+	box.PackStart(app.entryReplaySpeed, false, false, 0)
+	box.PackStart(app.pbReplayProgress, false, false, 0)
+	box.PackStart(btnBox, false, false, 0)
+	return box
+}
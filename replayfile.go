@@ -0,0 +1,415 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// replayPacket is one payload of a replayed stream, time is zero when the file has no timestamps
+type replayPacket struct {
+	time time.Time
+	data []byte
+}
+
+// replayStream is one direction of a conversation found in a capture file
+type replayStream struct {
+	name    string
+	packets []replayPacket
+	bytes   int
+
+	tcpStarted bool
+	tcpNext    uint32
+}
+
+// replayStreams collects packets by stream name in the order the streams first appear
+type replayStreams struct {
+	list   []*replayStream
+	byName map[string]*replayStream
+}
+
+func (s *replayStreams) get(name string) *replayStream {
+	if s.byName == nil {
+		s.byName = map[string]*replayStream{}
+	}
+	stream, ok := s.byName[name]
+	if !ok {
+		stream = &replayStream{name: name}
+		s.byName[name] = stream
+		s.list = append(s.list, stream)
+	}
+	return stream
+}
+
+func (s *replayStream) add(t time.Time, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	s.packets = append(s.packets, replayPacket{time: t, data: append([]byte(nil), data...)})
+	s.bytes += len(data)
+}
+
+// addTCP adds a segment, data already seen is dropped so retransmissions are not sent twice
+func (s *replayStream) addTCP(t time.Time, seq uint32, syn bool, data []byte) {
+	if syn {
+		seq++
+	}
+	if s.tcpStarted {
+		if diff := int32(seq - s.tcpNext); diff < 0 {
+			skip := int(-diff)
+			if skip >= len(data) {
+				return
+			}
+			data = data[skip:]
+			seq += uint32(skip)
+		}
+	}
+	s.tcpStarted = true
+	s.tcpNext = seq + uint32(len(data))
+	s.add(t, data)
+}
+
+// loadReplayFile reads a pcap, pcapng or session log file and returns its streams, streams without data are left out
+func loadReplayFile(fileName string) ([]*replayStream, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	streams := &replayStreams{}
+	switch {
+	case len(data) >= 4 && binary.LittleEndian.Uint32(data) == pcapngSHB:
+		err = readPcapng(data, streams)
+	case len(data) >= 24 && isPcapMagic(data):
+		err = readPcap(data, streams)
+	default:
+		err = readSessionLog(data, streams)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := []*replayStream{}
+	for _, stream := range streams.list {
+		if len(stream.packets) > 0 {
+			result = append(result, stream)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no TCP or UDP data found")
+	}
+	return result, nil
+}
+
+// readSessionLog parses capture files written in the log or hex format
+func readSessionLog(data []byte, streams *replayStreams) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 65536), 16<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		t, dir, peer, payload, err := parseLogLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		streams.get(dir+" "+peer).add(t, payload)
+	}
+	return scanner.Err()
+}
+
+// parseLogLine accepts "date time DIR peer quoted" lines of the log format and "DIR peer HEX" lines of the hex format
+func parseLogLine(line string) (time.Time, string, string, []byte, error) {
+	fields := strings.SplitN(line, " ", 5)
+	if len(fields) == 5 && isDirectionName(fields[2]) {
+		t, err := time.ParseInLocation(time.DateTime+".000000", fields[0]+" "+fields[1], time.Local)
+		if err != nil {
+			return t, "", "", nil, err
+		}
+		text, err := strconv.Unquote(fields[4])
+		if err != nil {
+			return t, "", "", nil, fmt.Errorf("invalid data %s", fields[4])
+		}
+		return t, fields[2], fields[3], []byte(text), nil
+	}
+	fields = strings.SplitN(line, " ", 3)
+	if len(fields) == 3 && isDirectionName(fields[0]) {
+		payload, err := hex.DecodeString(strings.ReplaceAll(fields[2], " ", ""))
+		if err != nil {
+			return time.Time{}, "", "", nil, err
+		}
+		return time.Time{}, fields[0], fields[1], payload, nil
+	}
+	return time.Time{}, "", "", nil, errors.New("not a session log line")
+}
+
+func isDirectionName(s string) bool {
+	for _, name := range directionNames {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isPcapMagic(data []byte) bool {
+	switch binary.LittleEndian.Uint32(data) {
+	case pcapMagic, 0xA1B23C4D, 0xD4C3B2A1, 0x4D3CB2A1:
+		return true
+	}
+	return false
+}
+
+func readPcap(data []byte, streams *replayStreams) error {
+	var order binary.ByteOrder = binary.LittleEndian
+	magic := order.Uint32(data)
+	if magic == 0xD4C3B2A1 || magic == 0x4D3CB2A1 {
+		order = binary.BigEndian
+		magic = order.Uint32(data)
+	}
+	nano := magic == 0xA1B23C4D
+	linkType := order.Uint32(data[20:]) & 0xFFFF
+	for data = data[24:]; len(data) > 0; {
+		if len(data) < 16 {
+			return io.ErrUnexpectedEOF
+		}
+		sec, frac, size := order.Uint32(data), order.Uint32(data[4:]), int64(order.Uint32(data[8:]))
+		if int64(len(data)) < 16+size {
+			return io.ErrUnexpectedEOF
+		}
+		t := time.Unix(int64(sec), int64(frac)*1000)
+		if nano {
+			t = time.Unix(int64(sec), int64(frac))
+		}
+		addFrame(streams, linkType, t, data[16:16+size])
+		data = data[16+size:]
+	}
+	return nil
+}
+
+// pcapngInterface is what an interface description block tells about its packets
+type pcapngInterface struct {
+	linkType uint32
+	perSec   uint64 // timestamp units per second
+}
+
+func readPcapng(data []byte, streams *replayStreams) error {
+	var order binary.ByteOrder = binary.LittleEndian
+	interfaces := []pcapngInterface{}
+	for len(data) > 0 {
+		if len(data) < 12 {
+			return io.ErrUnexpectedEOF
+		}
+		blockType := order.Uint32(data)
+		if blockType == pcapngSHB {
+			// the byte order magic of each section decides how the rest is read
+			if binary.LittleEndian.Uint32(data[8:]) == pcapngByteOrder {
+				order = binary.LittleEndian
+			} else {
+				order = binary.BigEndian
+			}
+			interfaces = interfaces[:0]
+		}
+		size := int64(order.Uint32(data[4:]))
+		if size < 12 || size > int64(len(data)) {
+			return fmt.Errorf("invalid pcapng block length %d", size)
+		}
+		body := data[8 : size-4]
+		switch blockType {
+		case pcapngIDB:
+			if len(body) < 8 {
+				return io.ErrUnexpectedEOF
+			}
+			iface := pcapngInterface{linkType: uint32(order.Uint16(body)), perSec: 1000000}
+			if resol := pcapngOption(order, body[8:], 9); len(resol) > 0 {
+				iface.perSec = tsResolution(resol[0])
+			}
+			interfaces = append(interfaces, iface)
+		case pcapngEPB:
+			if len(body) < 20 {
+				return io.ErrUnexpectedEOF
+			}
+			id, captured := int64(order.Uint32(body)), int64(order.Uint32(body[12:]))
+			if id >= int64(len(interfaces)) || 20+captured > int64(len(body)) {
+				return errors.New("invalid pcapng packet block")
+			}
+			ts := uint64(order.Uint32(body[4:]))<<32 | uint64(order.Uint32(body[8:]))
+			addFrame(streams, interfaces[id].linkType, pcapngTime(ts, interfaces[id].perSec), body[20:20+captured])
+		case 3: // simple packet block, no timestamp
+			if len(body) < 4 || len(interfaces) == 0 {
+				return errors.New("invalid pcapng simple packet block")
+			}
+			captured := len(body) - 4
+			if n := int64(order.Uint32(body)); n < int64(captured) {
+				captured = int(n)
+			}
+			addFrame(streams, interfaces[0].linkType, time.Time{}, body[4:4+captured])
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+// pcapngOption returns the value of an option, nil if it is not present or the options are malformed.
+// Only the requested option may miss its padding at the end of the block.
+func pcapngOption(order binary.ByteOrder, options []byte, code uint16) []byte {
+	for len(options) >= 4 {
+		optCode, length := order.Uint16(options), int(order.Uint16(options[2:]))
+		if optCode == 0 || 4+length > len(options) {
+			return nil
+		}
+		if optCode == code {
+			return options[4 : 4+length]
+		}
+		padded := (length + 3) &^ 3
+		if 4+padded > len(options) {
+			return nil
+		}
+		options = options[4+padded:]
+	}
+	return nil
+}
+
+// tsResolution decodes if_tsresol, the high bit selects a power of two instead of a power of ten
+func tsResolution(value byte) uint64 {
+	perSec := uint64(1)
+	for i := byte(0); i < value&0x7F && i < 63; i++ {
+		if value&0x80 != 0 {
+			perSec *= 2
+		} else {
+			perSec *= 10
+		}
+	}
+	return perSec
+}
+
+func pcapngTime(ts, perSec uint64) time.Time {
+	sec, frac := ts/perSec, ts%perSec
+	return time.Unix(int64(sec), int64(float64(frac)*1e9/float64(perSec)))
+}
+
+// link layer types the replay understands
+const (
+	linkTypeNull     = 0
+	linkTypeRaw      = 101
+	linkTypeLoop     = 108
+	linkTypeLinuxSLL = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+	linkTypeSLL2     = 276
+)
+
+// linkPayload strips the link layer header, returns nil for frames that do not carry IP
+func linkPayload(linkType uint32, frame []byte) []byte {
+	switch linkType {
+	case linkTypeEthernet:
+		offset := 12
+		for offset+2 <= len(frame) {
+			etherType := binary.BigEndian.Uint16(frame[offset:])
+			if etherType == 0x8100 || etherType == 0x88A8 {
+				offset += 4 // VLAN tag
+				continue
+			}
+			if etherType != 0x0800 && etherType != 0x86DD {
+				return nil
+			}
+			return frame[offset+2:]
+		}
+		return nil
+	case linkTypeNull, linkTypeLoop:
+		if len(frame) < 4 {
+			return nil
+		}
+		return frame[4:]
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+		return frame
+	case linkTypeLinuxSLL:
+		if len(frame) < 16 {
+			return nil
+		}
+		return frame[16:]
+	case linkTypeSLL2:
+		if len(frame) < 20 {
+			return nil
+		}
+		return frame[20:]
+	}
+	return nil
+}
+
+// addFrame decodes a captured frame and adds its TCP or UDP payload to the stream of its direction
+func addFrame(streams *replayStreams, linkType uint32, t time.Time, frame []byte) {
+	packet := linkPayload(linkType, frame)
+	if len(packet) < 20 {
+		return
+	}
+	var srcIP, dstIP net.IP
+	var proto byte
+	var payload []byte
+	switch packet[0] >> 4 {
+	case 4:
+		headerLen := int(packet[0]&0x0F) * 4
+		total := int(binary.BigEndian.Uint16(packet[2:]))
+		if headerLen < 20 || total < headerLen || total > len(packet) {
+			return
+		}
+		if binary.BigEndian.Uint16(packet[6:])&0x3FFF != 0 {
+			return // fragments are not reassembled
+		}
+		srcIP, dstIP = net.IP(packet[12:16]), net.IP(packet[16:20])
+		proto, payload = packet[9], packet[headerLen:total]
+	case 6:
+		if len(packet) < 40 {
+			return
+		}
+		length := int(binary.BigEndian.Uint16(packet[4:]))
+		if 40+length > len(packet) {
+			return
+		}
+		srcIP, dstIP = net.IP(packet[8:24]), net.IP(packet[24:40])
+		proto, payload = packet[6], packet[40:40+length]
+		// skip hop-by-hop, routing and destination options headers
+		for (proto == 0 || proto == 43 || proto == 60) && len(payload) >= 8 {
+			extLen := (int(payload[1]) + 1) * 8
+			if extLen > len(payload) {
+				return
+			}
+			proto, payload = payload[0], payload[extLen:]
+		}
+	default:
+		return
+	}
+	switch proto {
+	case 6:
+		if len(payload) < 20 {
+			return
+		}
+		offset := int(payload[12]>>4) * 4
+		if offset < 20 || offset > len(payload) {
+			return
+		}
+		srcPort, dstPort := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:])
+		name := streamName("TCP", srcIP, srcPort, dstIP, dstPort)
+		streams.get(name).addTCP(t, binary.BigEndian.Uint32(payload[4:]), payload[13]&0x02 != 0, payload[offset:])
+	case 17:
+		if len(payload) < 8 {
+			return
+		}
+		srcPort, dstPort := binary.BigEndian.Uint16(payload), binary.BigEndian.Uint16(payload[2:])
+		streams.get(streamName("UDP", srcIP, srcPort, dstIP, dstPort)).add(t, payload[8:])
+	}
+}
+
+func streamName(proto string, srcIP net.IP, srcPort uint16, dstIP net.IP, dstPort uint16) string {
+	return fmt.Sprintf("%s %s -> %s", proto,
+		net.JoinHostPort(srcIP.String(), strconv.Itoa(int(srcPort))),
+		net.JoinHostPort(dstIP.String(), strconv.Itoa(int(dstPort))))
+}
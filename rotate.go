@@ -0,0 +1,193 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// rotation limits of the capture file, zero values are unlimited
+type rotation struct {
+	maxSize  int64
+	maxAge   time.Duration
+	keep     int
+	dated    bool // rotated files get a date stamp instead of a sequence number
+	compress bool
+}
+
+// rotated files are compressed and pruned one at a time
+var rotateMu sync.Mutex
+
+// rotatedPattern matches the rotated files of a capture file
+func rotatedPattern(fileName string, dated bool) *regexp.Regexp {
+	if dated {
+		ext := filepath.Ext(fileName)
+		stem := strings.TrimSuffix(filepath.Base(fileName), ext)
+		return regexp.MustCompile(`^` + regexp.QuoteMeta(stem) + `-\d{8}-\d{6}(-\d+)?` + regexp.QuoteMeta(ext) + `(\.gz)?$`)
+	}
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(filepath.Base(fileName)) + `\.(\d+)(\.gz)?$`)
+}
+
+// rotatedName returns a free name for the next rotated file
+func rotatedName(fileName string, dated bool, now time.Time) string {
+	dir := filepath.Dir(fileName)
+	pattern := rotatedPattern(fileName, dated)
+	entries, _ := os.ReadDir(dir)
+	taken := map[string]bool{}
+	next := 1
+	for _, entry := range entries {
+		match := pattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		taken[strings.TrimSuffix(entry.Name(), ".gz")] = true
+		if n, err := strconv.Atoi(match[1]); !dated && err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	if !dated {
+		return fmt.Sprintf("%s.%d", fileName, next)
+	}
+	ext := filepath.Ext(fileName)
+	stem := strings.TrimSuffix(filepath.Base(fileName), ext)
+	name := fmt.Sprintf("%s-%s%s", stem, now.Format("20060102-150405"), ext)
+	for i := 1; taken[name]; i++ {
+		name = fmt.Sprintf("%s-%s-%d%s", stem, now.Format("20060102-150405"), i, ext)
+	}
+	return filepath.Join(dir, name)
+}
+
+func gzipFile(fileName string) error {
+	src, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(fileName+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(fileName + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(fileName)
+}
+
+// pruneRotated deletes the oldest rotated files beyond the number to keep
+func pruneRotated(fileName string, rot rotation) error {
+	if rot.keep <= 0 {
+		return nil
+	}
+	dir := filepath.Dir(fileName)
+	pattern := rotatedPattern(fileName, rot.dated)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	files := []os.FileInfo{}
+	for _, entry := range entries {
+		if !pattern.MatchString(entry.Name()) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, info)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+	for _, info := range files[min(rot.keep, len(files)):] {
+		if err := os.Remove(filepath.Join(dir, info.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishRotation compresses and prunes in the background so the capture does not wait for it
+func finishRotation(fileName, rotated string, rot rotation, onError func(err error)) {
+	go func() {
+		rotateMu.Lock()
+		defer rotateMu.Unlock()
+		var err error
+		if rot.compress {
+			err = gzipFile(rotated)
+		}
+		if err == nil {
+			err = pruneRotated(fileName, rot)
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}()
+}
+
+// min is the builtin from Go 1.21 on, drop it when go.mod moves past 1.20
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// rotationSettings reads the rotation options of the capture page
+func (app *NetAssistantApp) rotationSettings() (rotation, error) {
+	rot := rotation{dated: app.cbRotateDated.GetActive(), compress: app.cbRotateGzip.GetActive()}
+	strSize, _ := app.entryRotateSize.GetText()
+	size, err := parseLimit(strSize)
+	if err != nil {
+		return rot, err
+	}
+	rot.maxSize = int64(size * 1024 * 1024)
+	strAge, _ := app.entryRotateAge.GetText()
+	age, err := parseLimit(strAge)
+	if err != nil {
+		return rot, err
+	}
+	rot.maxAge = time.Duration(age * float64(time.Minute))
+	strKeep, _ := app.entryRotateKeep.GetText()
+	keep, err := parseLimit(strKeep)
+	if err != nil {
+		return rot, err
+	}
+	rot.keep = int(keep)
+	return rot, nil
+}
+
+func (app *NetAssistantApp) createCapturePage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
+	box.SetBorderWidth(10)
+	app.entryRotateSize, _ = gtk.EntryNew()
+	app.entryRotateSize.SetPlaceholderText(getI18nText(IT_ROTATE_SIZE))
+	app.entryRotateAge, _ = gtk.EntryNew()
+	app.entryRotateAge.SetPlaceholderText(getI18nText(IT_ROTATE_AGE))
+	app.entryRotateKeep, _ = gtk.EntryNew()
+	app.entryRotateKeep.SetPlaceholderText(getI18nText(IT_ROTATE_KEEP))
+	app.cbRotateDated, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_ROTATE_DATED))
+	app.cbRotateGzip, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_ROTATE_GZIP))
+	box.PackStart(app.entryRotateSize, false, false, 0)
+	box.PackStart(app.entryRotateAge, false, false, 0)
+	box.PackStart(app.entryRotateKeep, false, false, 0)
+	box.PackStart(app.cbRotateDated, false, false, 0)
+	box.PackStart(app.cbRotateGzip, false, false, 0)
+	return box
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// upper bounds of the response time histogram buckets, the last bucket takes everything above
+var rttBuckets = []time.Duration{
+	time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second,
+}
+
+// rttStats tracks the time between a send and the first data received after it
+type rttStats struct {
+	lastSend int64 // unix nanoseconds of the latest send
+	lastRecv int64 // unix nanoseconds of the latest receive
+	waiting  int32 // set by a send, cleared by the first receive after it
+
+	mu      sync.Mutex
+	count   int
+	sum     time.Duration
+	min     time.Duration
+	max     time.Duration
+	buckets []int
+}
+
+// rttMark is the state before a send, restored by cancelSend
+type rttMark struct {
+	lastSend int64
+	waiting  int32
+}
+
+// markSend is called before the data is written so a fast reply cannot arrive first
+func (s *rttStats) markSend() rttMark {
+	return rttMark{
+		lastSend: atomic.SwapInt64(&s.lastSend, time.Now().UnixNano()),
+		waiting:  atomic.SwapInt32(&s.waiting, 1),
+	}
+}
+
+// cancelSend undoes markSend when nothing could be written
+func (s *rttStats) cancelSend(prev rttMark) {
+	atomic.StoreInt64(&s.lastSend, prev.lastSend)
+	atomic.StoreInt32(&s.waiting, prev.waiting)
+}
+
+// markRecv returns the elapsed time since the last send and since the previous receive, zero if unknown
+func (s *rttStats) markRecv(now time.Time) (sinceSend, sincePrev time.Duration) {
+	if last := atomic.SwapInt64(&s.lastRecv, now.UnixNano()); last != 0 {
+		sincePrev = now.Sub(time.Unix(0, last))
+	}
+	if last := atomic.LoadInt64(&s.lastSend); last != 0 {
+		sinceSend = now.Sub(time.Unix(0, last))
+	}
+	if atomic.CompareAndSwapInt32(&s.waiting, 1, 0) {
+		s.add(sinceSend)
+	}
+	return
+}
+
+func (s *rttStats) add(rtt time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buckets == nil {
+		s.buckets = make([]int, len(rttBuckets)+1)
+	}
+	if s.count == 0 || rtt < s.min {
+		s.min = rtt
+	}
+	if rtt > s.max {
+		s.max = rtt
+	}
+	s.count++
+	s.sum += rtt
+	index := 0
+	for index < len(rttBuckets) && rtt >= rttBuckets[index] {
+		index++
+	}
+	s.buckets[index]++
+}
+
+func (s *rttStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count, s.sum, s.min, s.max, s.buckets = 0, 0, 0, 0, nil
+	atomic.StoreInt32(&s.waiting, 0)
+}
+
+// summary returns min/avg/max for the status bar
+func (s *rttStats) summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("RTT %.1f/%.1f/%.1f ms (%d)", durationMs(s.min), durationMs(s.sum/time.Duration(s.count)), durationMs(s.max), s.count)
+}
+
+// histogram renders the bucket counts as text bars
+func (s *rttStats) histogram() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		return "no responses"
+	}
+	largest := 0
+	for _, n := range s.buckets {
+		if n > largest {
+			largest = n
+		}
+	}
+	lines := []string{}
+	for i, n := range s.buckets {
+		name := ""
+		if i < len(rttBuckets) {
+			name = "< " + rttBuckets[i].String()
+		} else {
+			name = ">= " + rttBuckets[len(rttBuckets)-1].String()
+		}
+		bar := strings.Repeat("#", (n*40+largest-1)/largest)
+		lines = append(lines, fmt.Sprintf("%8s %6d %s", name, n, bar))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatElapsed formats the response time prefix of received data
+func formatElapsed(sinceSend, sincePrev time.Duration) string {
+	format := func(d time.Duration) string {
+		if d == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("+%.3fms", durationMs(d))
+	}
+	return fmt.Sprintf("[send %s, recv %s]", format(sinceSend), format(sincePrev))
+}
+
+func (app *NetAssistantApp) onBtnRTTHistogram() {
+	dialog, _ := gtk.DialogNew()
+	dialog.SetTitle(getI18nText(IT_HISTOGRAM))
+	dialog.SetTransientFor(app.appWindow)
+	dialog.SetModal(true)
+	dialog.AddButton("OK", gtk.RESPONSE_OK)
+	content, _ := dialog.GetContentArea()
+	label, _ := gtk.LabelNew("")
+	label.SetMarkup("<tt>" + glib.MarkupEscapeText(app.rtt.histogram()) + "</tt>")
+	label.SetSelectable(true)
+	content.SetBorderWidth(10)
+	content.PackStart(label, true, true, 0)
+	dialog.ShowAll()
+	dialog.Run()
+	dialog.Destroy()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// modbus framings
+const (
+	framingMBAP = iota // Modbus TCP
+	framingRTU         // unit ID, PDU and CRC-16 as on a serial line
+)
+
+// default inter-frame silence, serial lines use 3.5 character times but gateways add network jitter
+const rtuSilence = 20 * time.Millisecond
+
+func rtuFrame(unit byte, pdu []byte) []byte {
+	frame := append([]byte{unit}, pdu...)
+	return binary.LittleEndian.AppendUint16(frame, uint16(calcChecksum("crc16modbus", frame)))
+}
+
+// rtuCheck verifies the CRC of a frame and splits it into unit ID and PDU
+func rtuCheck(frame []byte) (byte, []byte, bool) {
+	if len(frame) < 4 {
+		return 0, nil, false
+	}
+	end := len(frame) - 2
+	if uint16(calcChecksum("crc16modbus", frame[:end])) != binary.LittleEndian.Uint16(frame[end:]) {
+		return 0, nil, false
+	}
+	return frame[0], frame[1:end], true
+}
+
+// rtuFrameLength is the size of the frame at the start of buf as given by its function code,
+// 0 if more data is needed or the function is unknown
+func rtuFrameLength(buf []byte, request bool) int {
+	if len(buf) < 2 {
+		return 0
+	}
+	function := buf[1]
+	if !request && function&0x80 != 0 {
+		return 5
+	}
+	switch function {
+	case mbReadCoils, mbReadDiscreteInputs, mbReadHoldingRegisters, mbReadInputRegisters:
+		if request {
+			return 8
+		}
+		if len(buf) < 3 {
+			return 0
+		}
+		return 5 + int(buf[2])
+	case mbWriteSingleCoil, mbWriteSingleRegister:
+		return 8
+	case mbWriteMultipleCoils, mbWriteMultipleRegisters:
+		if !request {
+			return 8
+		}
+		if len(buf) < 7 {
+			return 0
+		}
+		return 9 + int(buf[6])
+	}
+	return 0
+}
+
+// rtuStream splits a byte stream into RTU frames, by length where the function code tells it
+// and by inter-frame silence otherwise
+type rtuStream struct {
+	mu   sync.Mutex
+	buf  []byte
+	last time.Time
+	gen  int
+}
+
+// feed returns the frames completed by data, an incomplete rest is passed to flush after silence
+func (s *rtuStream) feed(data []byte, request bool, silence time.Duration, flush func(frame []byte)) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var frames [][]byte
+	if len(s.buf) > 0 && now.Sub(s.last) >= silence {
+		frames = append(frames, s.buf)
+		s.buf = nil
+	}
+	s.last = now
+	s.gen++
+	buf := append(s.buf, data...)
+	for {
+		n := rtuFrameLength(buf, request)
+		if n == 0 || len(buf) < n {
+			break
+		}
+		frames = append(frames, buf[:n])
+		buf = buf[n:]
+	}
+	s.buf = append([]byte(nil), buf...)
+	if len(s.buf) > 0 {
+		gen := s.gen
+		time.AfterFunc(silence, func() {
+			s.mu.Lock()
+			frame := s.buf
+			if s.gen != gen || len(frame) == 0 {
+				s.mu.Unlock()
+				return
+			}
+			s.buf = nil
+			s.mu.Unlock()
+			flush(frame)
+		})
+	}
+	return frames
+}
+
+func (s *rtuStream) reset() {
+	s.mu.Lock()
+	s.buf = nil
+	s.gen++
+	s.mu.Unlock()
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// pacedRunner is the send, pause, step and stop control shared by the sequence and the replay runner
+type pacedRunner struct {
+	send     func(data []byte) error
+	report   func(msg string)
+	finished func()
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	paused   bool
+	steps    int
+	stopped  bool
+	chanStop chan bool
+}
+
+func newPacedRunner() *pacedRunner {
+	runner := &pacedRunner{chanStop: make(chan bool)}
+	runner.cond = sync.NewCond(&runner.mu)
+	return runner
+}
+
+func (r *pacedRunner) setPaused(paused bool) {
+	r.mu.Lock()
+	r.paused = paused
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+func (r *pacedRunner) isPaused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.paused
+}
+
+// step lets a paused runner send the next packet
+func (r *pacedRunner) step() {
+	r.mu.Lock()
+	r.steps++
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+func (r *pacedRunner) stop() {
+	r.mu.Lock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.chanStop)
+	}
+	r.mu.Unlock()
+	r.cond.Broadcast()
+}
+
+// waitTurn blocks while paused until a step is requested, returns false once stopped
+func (r *pacedRunner) waitTurn() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.paused && r.steps == 0 && !r.stopped {
+		r.cond.Wait()
+	}
+	if r.paused && r.steps > 0 {
+		r.steps--
+	}
+	return !r.stopped
+}
+
+func (r *pacedRunner) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.chanStop:
+		return false
+	}
+}
+
+// newPacedRunner connects a runner to the current connections, its log lines start with tag and done resets the page.
+// It returns nil after showing the reason in the status bar when there is nothing to send to.
+func (app *NetAssistantApp) newPacedRunner(tag string, done func()) *pacedRunner {
+	if len(app.connList) == 0 {
+		app.labelStatus.SetText(getI18nText(IT_NO_CONN))
+		return nil
+	}
+	udpTarget, err := app.udpTargetAddr()
+	if err != nil {
+		app.labelStatus.SetMarkup(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return nil
+	}
+	runner := newPacedRunner()
+	runner.send = func(data []byte) error {
+		n, err := app.writeData(data, udpTarget)
+		app.countSent(n)
+		return err
+	}
+	runner.report = func(msg string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog(tag + " " + msg)
+		})
+	}
+	runner.finished = func() {
+		glib.IdleAdd(func() {
+			done()
+			app.appendRecvLog(tag + " " + getI18nText(IT_END))
+		})
+	}
+	return runner
+}
+
+// guiUpdate coalesces the updates of a runner goroutine, only the latest one runs when the gui catches up
+type guiUpdate struct {
+	mu      sync.Mutex
+	pending bool
+	latest  func()
+}
+
+func (u *guiUpdate) set(update func()) {
+	u.mu.Lock()
+	u.latest = update
+	schedule := !u.pending
+	u.pending = true
+	u.mu.Unlock()
+	if schedule {
+		glib.IdleAdd(func() {
+			u.mu.Lock()
+			update := u.latest
+			u.pending = false
+			u.mu.Unlock()
+			update()
+		})
+	}
+}
+
+// toggleRunnerPause pauses or resumes a runner and shows the next action on its button
+func toggleRunnerPause(runner *pacedRunner, btn *gtk.Button) {
+	if runner.isPaused() {
+		runner.setPaused(false)
+		btn.SetLabel(getI18nText(IT_PAUSE))
+	} else {
+		runner.setPaused(true)
+		btn.SetLabel(getI18nText(IT_RESUME))
+	}
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gotk3/gotk3/gdk"
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// search modes of the receive area
+const (
+	searchText = iota
+	searchHex
+	searchRegex
+)
+
+var searchModeNames = []string{"text", "hex", "regex"}
+
+const (
+	tagSearchMatch   = "search-match"
+	tagSearchCurrent = "search-current"
+	tagFiltered      = "filtered"
+	tagRulePrefix    = "rule:"
+)
+
+const highlightExample = `# color | mode | scope | pattern, the pattern is the rest of the line
+# mode is text, hex or regex, scope is match or record
+# red | text | record | ERROR
+# #FF8000 | hex | match | 15 03 01
+`
+
+// matcher finds a text, a byte sequence or a regular expression in a record
+type matcher struct {
+	mode int
+	text string
+	data []byte
+	re   *regexp.Regexp
+}
+
+func newMatcher(mode int, pattern string) (*matcher, error) {
+	m := &matcher{mode: mode}
+	var err error
+	switch mode {
+	case searchHex:
+		m.data, err = parsePayload(pattern, true)
+		if err == nil && len(m.data) == 0 {
+			err = errors.New("empty pattern")
+		}
+	case searchRegex:
+		m.re, err = regexp.Compile(pattern)
+	default:
+		m.text = pattern
+		if pattern == "" {
+			err = errors.New("empty pattern")
+		}
+	}
+	return m, err
+}
+
+// find returns the char ranges of the matches in the rendered text of a record.
+// Byte sequences are searched in the raw data and mapped to the hex bytes shown, or to the whole data if it is shown as text.
+func (m *matcher) find(rec *recvRecord, text string, hex bool) [][2]int {
+	matches := [][2]int{}
+	chars := func(i int) int {
+		return utf8.RuneCountInString(text[:i])
+	}
+	switch m.mode {
+	case searchHex:
+		if rec.dir != dirRecv {
+			return nil
+		}
+		for from := 0; ; {
+			i := bytes.Index(rec.data[from:], m.data)
+			if i < 0 {
+				break
+			}
+			i += from
+			from = i + len(m.data)
+			matches = append(matches, rec.byteRange(i, from, hex, utf8.RuneCountInString(text)))
+		}
+	case searchRegex:
+		for _, loc := range m.re.FindAllStringIndex(text, -1) {
+			if loc[0] < loc[1] {
+				matches = append(matches, [2]int{chars(loc[0]), chars(loc[1])})
+			}
+		}
+	default:
+		for from := 0; ; {
+			i := strings.Index(text[from:], m.text)
+			if i < 0 {
+				break
+			}
+			i += from
+			from = i + len(m.text)
+			matches = append(matches, [2]int{chars(i), chars(from)})
+		}
+	}
+	return matches
+}
+
+// byteRange maps the data bytes [from, to) to chars of the rendered record
+func (rec *recvRecord) byteRange(from, to int, hex bool, length int) [2]int {
+	if rec.dump {
+		layout := dumpLayout{width: rec.width}
+		pos := func(i int) int {
+			return rec.dataAt + i/rec.width*layout.rowLen() + layout.hexCol(i%rec.width)
+		}
+		return [2]int{pos(from), pos(to-1) + 2}
+	}
+	if hex {
+		return [2]int{rec.dataAt + 3*from, rec.dataAt + 3*to - 1}
+	}
+	return [2]int{rec.dataAt, length}
+}
+
+type highlightRule struct {
+	tag   string
+	whole bool
+	m     *matcher
+}
+
+// parseHighlightRules parses one rule per line, blank lines and lines starting with # are skipped
+func parseHighlightRules(script string) ([]highlightRule, error) {
+	rules := []highlightRule{}
+	for lineNo, line := range strings.Split(script, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		fields := strings.SplitN(line, "|", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("line %d: expected color | mode | scope | pattern", lineNo+1)
+		}
+		color := strings.TrimSpace(fields[0])
+		if !gdk.NewRGBA().Parse(color) {
+			return nil, fmt.Errorf("line %d: invalid color %q", lineNo+1, color)
+		}
+		mode := -1
+		for i, name := range searchModeNames {
+			if strings.TrimSpace(fields[1]) == name {
+				mode = i
+			}
+		}
+		if mode < 0 {
+			return nil, fmt.Errorf("line %d: invalid mode %q", lineNo+1, strings.TrimSpace(fields[1]))
+		}
+		rule := highlightRule{tag: tagRulePrefix + color}
+		switch scope := strings.TrimSpace(fields[2]); scope {
+		case "record":
+			rule.whole = true
+		case "match":
+		default:
+			return nil, fmt.Errorf("line %d: invalid scope %q", lineNo+1, scope)
+		}
+		m, err := newMatcher(mode, strings.TrimSpace(fields[3]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo+1, err)
+		}
+		rule.m = m
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// searchMatch is a match of the search in a record, the offsets are relative to the record text
+type searchMatch struct {
+	rec      *recvRecord
+	from, to int
+}
+
+func (app *NetAssistantApp) applyTag(name string, from, to int) {
+	app.tbReceData.ApplyTagByName(name, app.tbReceData.GetIterAtOffset(from), app.tbReceData.GetIterAtOffset(to))
+}
+
+// decorate applies the highlight rules, the search and the filter to records, reset starts over for the whole buffer
+func (app *NetAssistantApp) decorate(recs []*recvRecord, reset bool) {
+	var current *searchMatch
+	if reset && app.searchIndex >= 0 && app.searchIndex < len(app.searchMatches) {
+		current = &app.searchMatches[app.searchIndex]
+	}
+	if reset {
+		first, last := app.tbReceData.GetBounds()
+		for _, name := range []string{tagSearchMatch, tagSearchCurrent, tagFiltered} {
+			app.tbReceData.RemoveTagByName(name, first, last)
+		}
+		for _, rule := range app.highlightRules {
+			app.tbReceData.RemoveTagByName(rule.tag, first, last)
+		}
+		app.searchMatches = nil
+		app.searchIndex = -1
+		recs = app.records
+	}
+	if app.searcher != nil || len(app.highlightRules) > 0 {
+		hex := app.cbHexDisplay.GetActive()
+		filter := app.cbSearchFilter.GetActive()
+		for _, rec := range recs {
+			offset := app.tbReceData.GetIterAtMark(rec.mark).GetOffset()
+			text := app.tbReceData.GetIterAtOffset(offset).GetText(app.tbReceData.GetIterAtOffset(offset + rec.length))
+			for _, rule := range app.highlightRules {
+				matches := rule.m.find(rec, text, hex)
+				if len(matches) > 0 && rule.whole {
+					app.applyTag(rule.tag, offset, offset+rec.length)
+					continue
+				}
+				for _, m := range matches {
+					app.applyTag(rule.tag, offset+m[0], offset+m[1])
+				}
+			}
+			if app.searcher == nil {
+				continue
+			}
+			matches := app.searcher.find(rec, text, hex)
+			for _, m := range matches {
+				app.searchMatches = append(app.searchMatches, searchMatch{rec: rec, from: m[0], to: m[1]})
+				app.applyTag(tagSearchMatch, offset+m[0], offset+m[1])
+			}
+			if filter && len(matches) == 0 {
+				app.applyTag(tagFiltered, offset, offset+rec.length)
+			}
+		}
+	}
+	// keep the position of the navigation when the buffer was rebuilt
+	for i, m := range app.searchMatches {
+		if current != nil && m.rec == current.rec && m.from == current.from {
+			offset := app.tbReceData.GetIterAtMark(m.rec.mark).GetOffset()
+			app.applyTag(tagSearchCurrent, offset+m.from, offset+m.to)
+			app.searchIndex = i
+			break
+		}
+	}
+	app.updateSearchLabel()
+}
+
+func (app *NetAssistantApp) updateSearchLabel() {
+	if app.searcher == nil {
+		return
+	}
+	app.labelSearch.SetText(fmt.Sprintf("%d/%d", app.searchIndex+1, len(app.searchMatches)))
+}
+
+// gotoMatch moves to the next or previous match and scrolls to it
+func (app *NetAssistantApp) gotoMatch(delta int) {
+	n := len(app.searchMatches)
+	if n == 0 {
+		return
+	}
+	app.searchIndex = ((app.searchIndex+delta)%n + n) % n
+	m := app.searchMatches[app.searchIndex]
+	first, last := app.tbReceData.GetBounds()
+	app.tbReceData.RemoveTagByName(tagSearchCurrent, first, last)
+	offset := app.tbReceData.GetIterAtMark(m.rec.mark).GetOffset()
+	app.applyTag(tagSearchCurrent, offset+m.from, offset+m.to)
+	app.tvDataReceive.ScrollToIter(app.tbReceData.GetIterAtOffset(offset+m.from), 0.1, false, 0, 0)
+	app.updateSearchLabel()
+}
+
+func (app *NetAssistantApp) onSearchChanged() {
+	pattern, _ := app.entrySearch.GetText()
+	app.searcher = nil
+	app.searchIndex = -1
+	app.labelSearch.SetText("")
+	if pattern != "" {
+		m, err := newMatcher(app.combSearchMode.GetActive(), pattern)
+		if err != nil {
+			app.labelSearch.SetMarkup(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		} else {
+			app.searcher = m
+		}
+	}
+	app.decorate(nil, true)
+	app.gotoMatch(1)
+}
+
+func (app *NetAssistantApp) onBtnHighlightRules() {
+	dialog, _ := gtk.DialogNew()
+	dialog.SetTitle(getI18nText(IT_HIGHLIGHT_RULES))
+	dialog.SetTransientFor(app.appWindow)
+	dialog.SetModal(true)
+	dialog.SetDefaultSize(480, 300)
+	dialog.AddButton("Cancel", gtk.RESPONSE_CANCEL)
+	dialog.AddButton("OK", gtk.RESPONSE_OK)
+	content, _ := dialog.GetContentArea()
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	tv, _ := gtk.TextViewNew()
+	tv.SetMonospace(true)
+	buff, _ := tv.GetBuffer()
+	if app.highlightText == "" {
+		buff.SetText(highlightExample)
+	} else {
+		buff.SetText(app.highlightText)
+	}
+	scroller.Add(tv)
+	content.PackStart(scroller, true, true, 0)
+	dialog.ShowAll()
+	if dialog.Run() == gtk.RESPONSE_OK {
+		start, end := buff.GetBounds()
+		app.highlightText, _ = buff.GetText(start, end, true)
+		rules, err := parseHighlightRules(app.highlightText)
+		if err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		} else {
+			app.decorate(nil, true)
+			for _, rule := range rules {
+				if tagTable, err := app.tbReceData.GetTagTable(); err == nil {
+					if _, err := tagTable.Lookup(rule.tag); err != nil {
+						app.tbReceData.CreateTag(rule.tag, map[string]interface{}{"foreground": strings.TrimPrefix(rule.tag, tagRulePrefix)})
+					}
+				}
+			}
+			app.highlightRules = rules
+			app.decorate(nil, true)
+		}
+	}
+	dialog.Destroy()
+}
+
+func (app *NetAssistantApp) createSearchBar() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	search, _ := gtk.SearchEntryNew()
+	app.entrySearch = &search.Entry
+	app.entrySearch.SetPlaceholderText(getI18nText(IT_SEARCH))
+	search.Connect("search-changed", app.onSearchChanged)
+	search.Connect("activate", func() {
+		app.gotoMatch(1)
+	})
+	app.combSearchMode, _ = gtk.ComboBoxTextNew()
+	for _, key := range []string{IT_SEARCH_TEXT, IT_SEARCH_HEX, IT_SEARCH_REGEX} {
+		app.combSearchMode.AppendText(getI18nText(key))
+	}
+	app.combSearchMode.SetActive(searchText)
+	app.combSearchMode.Connect("changed", app.onSearchChanged)
+	btnPrev, _ := gtk.ButtonNewWithLabel("<")
+	btnPrev.Connect("clicked", func() {
+		app.gotoMatch(-1)
+	})
+	btnNext, _ := gtk.ButtonNewWithLabel(">")
+	btnNext.Connect("clicked", func() {
+		app.gotoMatch(1)
+	})
+	app.labelSearch, _ = gtk.LabelNew("")
+	app.cbSearchFilter, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_FILTER))
+	app.cbSearchFilter.Connect("toggled", func() {
+		app.decorate(nil, true)
+	})
+	btnRules, _ := gtk.ButtonNewWithLabel(getI18nText(IT_HIGHLIGHT_RULES))
+	btnRules.Connect("clicked", app.onBtnHighlightRules)
+
+	box.PackStart(search, true, true, 0)
+	box.PackStart(app.combSearchMode, false, false, 0)
+	box.PackStart(btnPrev, false, false, 0)
+	box.PackStart(btnNext, false, false, 0)
+	box.PackStart(app.labelSearch, false, false, 0)
+	box.PackStart(app.cbSearchFilter, false, false, 0)
+	box.PackStart(btnRules, false, false, 0)
+	return box
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+const sequenceExample = `# payload | delay=ms | repeat=n | expect=data | timeout=ms
+# text payloads accept \r \n \t \\ and \xHH escapes
+hello\r\n | delay=100
+ping | repeat=3 | delay=500 | expect=pong | timeout=1000
+`
+
+// seqStep is one entry of a send sequence
+type seqStep struct {
+	payload []byte
+	delay   time.Duration // wait before each send
+	repeat  int
+	expect  []byte // response to wait for after each send, nil means don't wait
+	timeout time.Duration
+}
+
+// unescapeText converts \r \n \t \\ and \xHH escapes to bytes
+func unescapeText(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'r':
+			buf.WriteByte('\r')
+		case 'n':
+			buf.WriteByte('\n')
+		case 't':
+			buf.WriteByte('\t')
+		case '\\':
+			buf.WriteByte('\\')
+		case 'x':
+			if i+3 > len(s) {
+				return nil, fmt.Errorf("invalid escape \\x%s", s[i+1:])
+			}
+			b, err := hex.DecodeString(s[i+1 : i+3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid escape \\x%s", s[i+1:i+3])
+			}
+			buf.Write(b)
+			i += 2
+		default:
+			buf.WriteByte('\\')
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func parsePayload(s string, isHex bool) ([]byte, error) {
+	if isHex {
+		s = strings.Replace(s, " ", "", -1)
+		return hex.DecodeString(s)
+	}
+	return unescapeText(s)
+}
+
+// parseSequence parses one step per line, blank lines and lines starting with # are skipped
+func parseSequence(script string, isHex bool) ([]seqStep, error) {
+	steps := []seqStep{}
+	for lineNo, line := range strings.Split(script, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		payload, err := parsePayload(strings.TrimSpace(fields[0]), isHex)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo+1, err)
+		}
+		step := seqStep{payload: payload, repeat: 1, timeout: time.Second}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("line %d: invalid option %q", lineNo+1, field)
+			}
+			key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+			switch key {
+			case "delay", "timeout":
+				ms, err := strconv.ParseFloat(value, 64)
+				if err != nil || ms < 0 {
+					return nil, fmt.Errorf("line %d: invalid %s %q", lineNo+1, key, value)
+				}
+				if key == "delay" {
+					step.delay = time.Duration(ms * float64(time.Millisecond))
+				} else {
+					step.timeout = time.Duration(ms * float64(time.Millisecond))
+				}
+			case "repeat":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 {
+					return nil, fmt.Errorf("line %d: invalid repeat %q", lineNo+1, value)
+				}
+				step.repeat = n
+			case "expect":
+				step.expect, err = parsePayload(value, isHex)
+				if err != nil || len(step.expect) == 0 {
+					return nil, fmt.Errorf("line %d: invalid expect %q", lineNo+1, value)
+				}
+			default:
+				return nil, fmt.Errorf("line %d: unknown option %q", lineNo+1, key)
+			}
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("sequence is empty")
+	}
+	return steps, nil
+}
+
+// seqRunner plays a list of steps once or looped
+type seqRunner struct {
+	steps []seqStep
+	loop  bool
+
+	*pacedRunner
+	progress func(step, total, round int)
+	recvBuf  []byte
+	chanRecv chan bool
+}
+
+func newSeqRunner(paced *pacedRunner, steps []seqStep, loop bool) *seqRunner {
+	return &seqRunner{pacedRunner: paced, steps: steps, loop: loop, chanRecv: make(chan bool, 1)}
+}
+
+// feed hands received data to the runner so it can match expected responses
+func (r *seqRunner) feed(data []byte) {
+	r.mu.Lock()
+	r.recvBuf = append(r.recvBuf, data...)
+	r.mu.Unlock()
+	select {
+	case r.chanRecv <- true:
+	default:
+	}
+}
+
+// waitFor waits until expect shows up in the received data, returns matched and not stopped
+func (r *seqRunner) waitFor(expect []byte, timeout time.Duration) (bool, bool) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		r.mu.Lock()
+		matched := bytes.Contains(r.recvBuf, expect)
+		r.mu.Unlock()
+		if matched {
+			return true, true
+		}
+		select {
+		case <-r.chanRecv:
+		case <-timer.C:
+			return false, true
+		case <-r.chanStop:
+			return false, false
+		}
+	}
+}
+
+// sends closer together than this are summed up in the log, a step without delay or expect sends as fast as it can
+const seqReportInterval = 500 * time.Millisecond
+
+func (r *seqRunner) run() {
+	defer r.finished()
+	var lastReport time.Time
+	quiet, quietBytes := 0, 0
+	reportSent := func(name string, n int) {
+		if time.Since(lastReport) < seqReportInterval {
+			quiet++
+			quietBytes += n
+			return
+		}
+		msg := fmt.Sprintf("%s sent %d bytes", name, n)
+		if quiet > 0 {
+			msg += fmt.Sprintf(", %d sends with %d bytes before", quiet, quietBytes)
+		}
+		r.report(msg)
+		lastReport, quiet, quietBytes = time.Now(), 0, 0
+	}
+	defer func() {
+		if quiet > 0 {
+			r.report(fmt.Sprintf("%d more sends with %d bytes", quiet, quietBytes))
+		}
+	}()
+	for round := 1; ; round++ {
+		for index, step := range r.steps {
+			r.progress(index, len(r.steps), round)
+			for i := 1; i <= step.repeat; i++ {
+				if !r.waitTurn() || !r.sleep(step.delay) || !r.waitTurn() {
+					return
+				}
+				name := fmt.Sprintf("step %d/%d", index+1, len(r.steps))
+				if step.repeat > 1 {
+					name += fmt.Sprintf(" #%d", i)
+				}
+				r.mu.Lock()
+				r.recvBuf = nil
+				r.mu.Unlock()
+				if err := r.send(step.payload); err != nil {
+					r.report(fmt.Sprintf("%s send failed: %s", name, err))
+					return
+				}
+				reportSent(name, len(step.payload))
+				if step.expect == nil {
+					continue
+				}
+				start := time.Now()
+				matched, running := r.waitFor(step.expect, step.timeout)
+				if !running {
+					return
+				}
+				if matched {
+					r.report(fmt.Sprintf("%s matched in %s", name, time.Since(start).Round(time.Millisecond)))
+				} else {
+					r.report(fmt.Sprintf("%s timed out after %s", name, step.timeout))
+				}
+			}
+		}
+		r.progress(len(r.steps), len(r.steps), round)
+		if !r.loop {
+			return
+		}
+	}
+}
+
+func (app *NetAssistantApp) onBtnSeqRun() {
+	if app.seqRunner != nil {
+		return
+	}
+	start, end := app.tbSeqScript.GetBounds()
+	script, _ := app.tbSeqScript.GetText(start, end, true)
+	steps, err := parseSequence(script, app.cbSeqHex.GetActive())
+	if err != nil {
+		app.labelStatus.SetMarkup(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		return
+	}
+	paced := app.newPacedRunner("[SEQ]", func() {
+		app.seqRunner = nil
+		app.btnSeqRun.SetSensitive(true)
+		app.btnSeqPause.SetLabel(getI18nText(IT_PAUSE))
+		app.btnSeqPause.SetSensitive(false)
+		app.btnSeqStop.SetSensitive(false)
+	})
+	if paced == nil {
+		return
+	}
+	runner := newSeqRunner(paced, steps, app.cbSeqLoop.GetActive())
+	var update guiUpdate
+	runner.progress = func(step, total, round int) {
+		update.set(func() {
+			app.pbSeqProgress.SetFraction(float64(step) / float64(total))
+			app.pbSeqProgress.SetText(fmt.Sprintf("%d/%d  #%d", step, total, round))
+		})
+	}
+
+	app.seqRunner = runner
+	app.btnSeqRun.SetSensitive(false)
+	app.btnSeqPause.SetSensitive(true)
+	app.btnSeqStop.SetSensitive(true)
+	go runner.run()
+}
+
+func (app *NetAssistantApp) onBtnSeqPause() {
+	if app.seqRunner == nil {
+		return
+	}
+	toggleRunnerPause(app.seqRunner.pacedRunner, app.btnSeqPause)
+}
+
+func (app *NetAssistantApp) onBtnSeqStop() {
+	if app.seqRunner != nil {
+		app.seqRunner.stop()
+	}
+}
+
+func (app *NetAssistantApp) createSequencePage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 10)
+	box.SetBorderWidth(10)
+
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	scroller.SetSizeRequest(220, 160)
+	tvScript, _ := gtk.TextViewNew()
+	tvScript.SetMonospace(true)
+	app.tbSeqScript, _ = tvScript.GetBuffer()
+	app.tbSeqScript.SetText(sequenceExample)
+	scroller.Add(tvScript)
+
+	app.cbSeqHex, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_HEX_PAYLOAD))
+	app.cbSeqLoop, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_LOOP))
+	app.pbSeqProgress, _ = gtk.ProgressBarNew()
+	app.pbSeqProgress.SetShowText(true)
+	app.pbSeqProgress.SetText("")
+
+	btnBox, _ := gtk.BoxNew(gtk.ORIENTATION_HORIZONTAL, 5)
+	app.btnSeqRun, _ = gtk.ButtonNewWithLabel(getI18nText(IT_RUN))
+	app.btnSeqRun.Connect("clicked", app.onBtnSeqRun)
+	app.btnSeqPause, _ = gtk.ButtonNewWithLabel(getI18nText(IT_PAUSE))
+	app.btnSeqPause.Connect("clicked", app.onBtnSeqPause)
+	app.btnSeqPause.SetSensitive(false)
+	app.btnSeqStop, _ = gtk.ButtonNewWithLabel(getI18nText(IT_STOP))
+	app.btnSeqStop.Connect("clicked", app.onBtnSeqStop)
+	app.btnSeqStop.SetSensitive(false)
+	btnBox.PackStart(app.btnSeqRun, true, true, 0)
+	btnBox.PackStart(app.btnSeqPause, true, true, 0)
+	btnBox.PackStart(app.btnSeqStop, true, true, 0)
+
+	box.PackStart(scroller, true, true, 0)
+	box.PackStart(app.cbSeqHex, false, false, 0)
+	box.PackStart(app.cbSeqLoop, false, false, 0)
+	box.PackStart(app.pbSeqProgress, false, false, 0)
+	box.PackStart(btnBox, false, false, 0)
+	return box
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+)
+
+// socksTunnel is one connection proxied by the SOCKS5 server mode, its counters are shown with every record
+type socksTunnel struct {
+	id    int
+	up    int64 // bytes from the client to the target
+	down  int64 // bytes from the target to the client
+	start time.Time
+}
+
+// tag counts n bytes and describes them, >> marks client to target and << target to client
+func (t *socksTunnel) tag(up bool, target string, n int) string {
+	marker := "<<"
+	if up {
+		marker = ">>"
+		atomic.AddInt64(&t.up, int64(n))
+	} else {
+		atomic.AddInt64(&t.down, int64(n))
+	}
+	return fmt.Sprintf("#%d %s %s, up %dB down %dB", t.id, marker, target, atomic.LoadInt64(&t.up), atomic.LoadInt64(&t.down))
+}
+
+func (t *socksTunnel) String() string {
+	return fmt.Sprintf("#%d closed after %s, up %dB down %dB", t.id, time.Since(t.start).Round(time.Millisecond),
+		atomic.LoadInt64(&t.up), atomic.LoadInt64(&t.down))
+}
+
+// socksServer tracks the listener and the open sockets of the SOCKS5 server mode
+type socksServer struct {
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[io.Closer]bool
+	nextID   int
+}
+
+func newSocksServer(listener net.Listener) *socksServer {
+	return &socksServer{listener: listener, conns: map[io.Closer]bool{}}
+}
+
+func (s *socksServer) track(c io.Closer) {
+	s.mu.Lock()
+	s.conns[c] = true
+	s.mu.Unlock()
+}
+
+func (s *socksServer) untrack(c io.Closer) {
+	s.mu.Lock()
+	delete(s.conns, c)
+	s.mu.Unlock()
+	c.Close()
+}
+
+func (s *socksServer) newTunnel() *socksTunnel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	return &socksTunnel{id: s.nextID, start: time.Now()}
+}
+
+func (s *socksServer) close() {
+	s.listener.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+	s.conns = map[io.Closer]bool{}
+}
+
+// socks5Accept runs the method selection and reads the request, any user name and password is accepted
+func socks5Accept(conn net.Conn) (cmd byte, addr, user string, err error) {
+	buf := make([]byte, 256)
+	if _, err = io.ReadFull(conn, buf[:2]); err != nil {
+		return
+	}
+	if buf[0] != socks5Version {
+		return 0, "", "", fmt.Errorf("not a SOCKS5 client, version %d", buf[0])
+	}
+	methods := make([]byte, buf[1])
+	if _, err = io.ReadFull(conn, methods); err != nil {
+		return
+	}
+	method := byte(socksAuthRefused)
+	for _, m := range methods {
+		if m == socksAuthNone || m == socksAuthPassword && method != socksAuthNone {
+			method = m
+		}
+	}
+	if _, err = conn.Write([]byte{socks5Version, method}); err != nil {
+		return
+	}
+	switch method {
+	case socksAuthRefused:
+		return 0, "", "", errors.New("no supported authentication method")
+	case socksAuthPassword:
+		// RFC 1929: version, user name and password, each with a length byte
+		if _, err = io.ReadFull(conn, buf[:2]); err != nil {
+			return
+		}
+		userLen := int(buf[1])
+		if _, err = io.ReadFull(conn, buf[:userLen+1]); err != nil {
+			return
+		}
+		user = string(buf[:userLen])
+		if _, err = io.ReadFull(conn, buf[:buf[userLen]]); err != nil {
+			return
+		}
+		if _, err = conn.Write([]byte{1, 0}); err != nil {
+			return
+		}
+	}
+	if _, err = io.ReadFull(conn, buf[:3]); err != nil {
+		return
+	}
+	cmd = buf[1]
+	addr, err = socksReadAddr(conn)
+	return
+}
+
+// socks5Reply answers the request, bound is the address used by the server
+func socks5Reply(conn net.Conn, code byte, bound net.Addr) error {
+	addr := "0.0.0.0:0"
+	if bound != nil {
+		addr = bound.String()
+	}
+	reply, err := socksAppendAddr([]byte{socks5Version, code, 0}, addr)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(reply)
+	return err
+}
+
+// socksReplyCode maps a dial error to the SOCKS5 reply
+func socksReplyCode(err error) byte {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr):
+		return 4
+	case strings.Contains(err.Error(), "refused"):
+		return 5
+	case strings.Contains(err.Error(), "network is unreachable"):
+		return 3
+	case strings.Contains(err.Error(), "unreachable"), strings.Contains(err.Error(), "timeout"):
+		return 4
+	}
+	return 1
+}
+
+// serveSocks accepts SOCKS5 clients until the listener is closed
+func (app *NetAssistantApp) serveSocks(server *socksServer) {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			log.Error("accept err:", err)
+			return
+		}
+		server.track(conn)
+		go func() {
+			defer server.untrack(conn)
+			app.socksSession(server, conn)
+		}()
+	}
+}
+
+func (app *NetAssistantApp) socksReport(msg string) {
+	glib.IdleAdd(func() {
+		app.appendRecvLog("[SOCKS] " + msg)
+	})
+}
+
+func (app *NetAssistantApp) socksSession(server *socksServer, conn net.Conn) {
+	peer := conn.RemoteAddr().String()
+	conn.SetDeadline(time.Now().Add(proxyTimeout))
+	cmd, addr, user, err := socks5Accept(conn)
+	if err != nil {
+		app.socksReport(fmt.Sprintf("%s rejected: %s", peer, err))
+		return
+	}
+	tunnel := server.newTunnel()
+	who := peer
+	if user != "" {
+		who += " user " + user
+	}
+	switch cmd {
+	case socksConnect:
+		app.socksReport(fmt.Sprintf("#%d %s CONNECT %s", tunnel.id, who, addr))
+		target, err := net.DialTimeout("tcp", addr, proxyTimeout)
+		if err != nil {
+			socks5Reply(conn, socksReplyCode(err), nil)
+			app.socksReport(fmt.Sprintf("#%d %s", tunnel.id, err))
+			return
+		}
+		server.track(target)
+		defer server.untrack(target)
+		if err := socks5Reply(conn, 0, target.LocalAddr()); err != nil {
+			return
+		}
+		conn.SetDeadline(time.Time{})
+		app.socksRelay(tunnel, conn, target, addr)
+	case socksAssociate:
+		app.socksReport(fmt.Sprintf("#%d %s UDP ASSOCIATE", tunnel.id, who))
+		host, _, _ := net.SplitHostPort(conn.LocalAddr().String())
+		udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP(host)})
+		if err != nil {
+			socks5Reply(conn, 1, nil)
+			app.socksReport(fmt.Sprintf("#%d %s", tunnel.id, err))
+			return
+		}
+		server.track(udpConn)
+		defer server.untrack(udpConn)
+		if err := socks5Reply(conn, 0, udpConn.LocalAddr()); err != nil {
+			return
+		}
+		conn.SetDeadline(time.Time{})
+		go func() {
+			// the association ends with the control connection
+			io.Copy(io.Discard, conn)
+			udpConn.Close()
+		}()
+		app.socksUDPRelay(tunnel, conn.RemoteAddr().(*net.TCPAddr).IP, udpConn)
+	default:
+		socks5Reply(conn, 7, nil)
+		app.socksReport(fmt.Sprintf("#%d %s command %d not supported", tunnel.id, who, cmd))
+		return
+	}
+	app.socksReport(tunnel.String())
+}
+
+// socksRelay copies both directions and shows the data, a direction that ends is half closed
+func (app *NetAssistantApp) socksRelay(tunnel *socksTunnel, client, target net.Conn, addr string) {
+	done := make(chan struct{})
+	copyData := func(dst, src net.Conn, up bool) {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := src.Read(buf)
+			if n > 0 {
+				app.receive(src, nil, buf[:n], tunnel.tag(up, addr, n))
+				if _, err := dst.Write(buf[:n]); err != nil {
+					break
+				}
+			}
+			if err == io.EOF {
+				if tcp, ok := dst.(*net.TCPConn); ok {
+					tcp.CloseWrite()
+					return
+				}
+			}
+			if err != nil {
+				break
+			}
+		}
+		client.Close()
+		target.Close()
+	}
+	go func() {
+		copyData(target, client, true)
+		close(done)
+	}()
+	copyData(client, target, false)
+	<-done
+}
+
+// socksUDPRelay forwards the datagrams of one association, the first datagram from the client IP fixes the client port
+func (app *NetAssistantApp) socksUDPRelay(tunnel *socksTunnel, clientIP net.IP, udpConn *net.UDPConn) {
+	var client *net.UDPAddr
+	buf := make([]byte, 65536)
+	for {
+		n, from, err := udpConn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if client == nil && from.IP.Equal(clientIP) {
+			client = from
+		}
+		if client != nil && from.IP.Equal(client.IP) && from.Port == client.Port {
+			if n < 4 || buf[2] != 0 {
+				continue // fragments are not supported
+			}
+			dst, size, err := socksParseAddr(buf[3:n])
+			if err != nil {
+				continue
+			}
+			dstAddr, err := net.ResolveUDPAddr("udp", dst)
+			if err != nil {
+				app.socksReport(fmt.Sprintf("#%d %s", tunnel.id, err))
+				continue
+			}
+			payload := buf[3+size : n]
+			app.receive(udpConn, from, payload, tunnel.tag(true, "udp "+dst, len(payload)))
+			udpConn.WriteToUDP(payload, dstAddr)
+			continue
+		}
+		if client == nil {
+			continue
+		}
+		reply, err := socksAppendAddr([]byte{0, 0, 0}, from.String())
+		if err != nil {
+			continue
+		}
+		app.receive(udpConn, from, buf[:n], tunnel.tag(false, "udp "+from.String(), n))
+		udpConn.WriteToUDP(append(reply, buf[:n]...), client)
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+// telnet commands, RFC 854
+const (
+	telnetSE   = 240
+	telnetNOP  = 241
+	telnetGA   = 249
+	telnetSB   = 250
+	telnetWILL = 251
+	telnetWONT = 252
+	telnetDO   = 253
+	telnetDONT = 254
+	telnetIAC  = 255
+)
+
+// telnet options
+const (
+	telnetOptEcho     = 1
+	telnetOptSGA      = 3
+	telnetOptTermType = 24
+	telnetOptNAWS     = 31
+)
+
+// TERMINAL-TYPE subnegotiation, RFC 1091
+const (
+	telnetTermIs   = 0
+	telnetTermSend = 1
+)
+
+// subnegotiations above this size are dropped
+const telnetMaxSB = 1024
+
+var telnetCommandNames = map[byte]string{
+	telnetSE: "SE", telnetNOP: "NOP", 242: "DM", 243: "BRK", 244: "IP", 245: "AO", 246: "AYT",
+	247: "EC", 248: "EL", telnetGA: "GA", telnetSB: "SB",
+	telnetWILL: "WILL", telnetWONT: "WONT", telnetDO: "DO", telnetDONT: "DONT",
+}
+
+var telnetOptionNames = map[byte]string{
+	0: "BINARY", telnetOptEcho: "ECHO", telnetOptSGA: "SGA", 5: "STATUS", 6: "TIMING-MARK",
+	telnetOptTermType: "TERMINAL-TYPE", telnetOptNAWS: "NAWS", 32: "TERMINAL-SPEED",
+	33: "LFLOW", 34: "LINEMODE", 35: "XDISPLOC", 36: "ENVIRON", 39: "NEW-ENVIRON", 42: "CHARSET",
+}
+
+func telnetCommandName(cmd byte) string {
+	if name, ok := telnetCommandNames[cmd]; ok {
+		return name
+	}
+	return strconv.Itoa(int(cmd))
+}
+
+func telnetOptionName(opt byte) string {
+	if name, ok := telnetOptionNames[opt]; ok {
+		return name
+	}
+	return strconv.Itoa(int(opt))
+}
+
+// parseTelnetOptions reads a comma separated list of option names or numbers
+func parseTelnetOptions(text string) (map[byte]bool, error) {
+	options := map[byte]bool{}
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		found := false
+		for opt, name := range telnetOptionNames {
+			if strings.EqualFold(item, name) {
+				options[opt], found = true, true
+			}
+		}
+		if !found {
+			opt, err := strconv.ParseUint(item, 0, 8)
+			if err != nil {
+				return nil, fmt.Errorf("unknown telnet option %q", item)
+			}
+			options[byte(opt)] = true
+		}
+	}
+	return options, nil
+}
+
+// telnetOptions are the answers to the negotiation of the peer
+type telnetOptions struct {
+	will     map[byte]bool // options we enable when asked with DO
+	do       map[byte]bool // options we let the peer enable when it offers WILL
+	termType string
+	width    int
+	height   int
+}
+
+// telnetConn strips telnet commands from the stream it reads and answers the option negotiation
+type telnetConn struct {
+	net.Conn
+	opts   telnetOptions
+	wmu    sync.Mutex
+	state  int
+	verb   byte
+	sb     []byte
+	local  map[byte]bool // options enabled on our side
+	remote map[byte]bool // options enabled on the peer side
+
+	report func(msg string)
+}
+
+// states of the command parser
+const (
+	telnetStateData = iota
+	telnetStateIAC
+	telnetStateOption
+	telnetStateSB
+	telnetStateSBIAC
+)
+
+func newTelnetConn(conn net.Conn, opts telnetOptions, report func(msg string)) *telnetConn {
+	return &telnetConn{Conn: conn, opts: opts, local: map[byte]bool{}, remote: map[byte]bool{}, report: report}
+}
+
+// Read returns the data bytes of the stream, commands are answered on the way
+func (c *telnetConn) Read(p []byte) (int, error) {
+	for {
+		n, err := c.Conn.Read(p)
+		n = c.filter(p[:n])
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// filter processes buf in place and returns the number of data bytes left at its start
+func (c *telnetConn) filter(buf []byte) int {
+	out := 0
+	for _, b := range buf {
+		switch c.state {
+		case telnetStateData:
+			if b == telnetIAC {
+				c.state = telnetStateIAC
+			} else {
+				buf[out] = b
+				out++
+			}
+		case telnetStateIAC:
+			c.state = telnetStateData
+			switch b {
+			case telnetIAC:
+				buf[out] = b
+				out++
+			case telnetWILL, telnetWONT, telnetDO, telnetDONT:
+				c.verb, c.state = b, telnetStateOption
+			case telnetSB:
+				c.sb, c.state = c.sb[:0], telnetStateSB
+			default:
+				c.report("recv " + telnetCommandName(b))
+			}
+		case telnetStateOption:
+			c.state = telnetStateData
+			c.negotiate(c.verb, b)
+		case telnetStateSB:
+			if b == telnetIAC {
+				c.state = telnetStateSBIAC
+			} else if len(c.sb) < telnetMaxSB {
+				c.sb = append(c.sb, b)
+			}
+		case telnetStateSBIAC:
+			switch b {
+			case telnetSE:
+				c.state = telnetStateData
+				c.subnegotiation(c.sb)
+			case telnetIAC:
+				c.state = telnetStateSB
+				if len(c.sb) < telnetMaxSB {
+					c.sb = append(c.sb, b)
+				}
+			default:
+				c.state = telnetStateData
+				c.report("recv unterminated SB " + hexString(c.sb))
+			}
+		}
+	}
+	return out
+}
+
+// negotiate answers a request only when it changes the state of the option, RFC 854 loop prevention
+func (c *telnetConn) negotiate(verb, opt byte) {
+	c.report(fmt.Sprintf("recv %s %s", telnetCommandName(verb), telnetOptionName(opt)))
+	switch verb {
+	case telnetDO:
+		if !c.opts.will[opt] {
+			c.send(telnetWONT, opt)
+		} else if !c.local[opt] {
+			c.local[opt] = true
+			c.send(telnetWILL, opt)
+			if opt == telnetOptNAWS {
+				c.sendWindowSize()
+			}
+		}
+	case telnetDONT:
+		if c.local[opt] {
+			c.local[opt] = false
+			c.send(telnetWONT, opt)
+		}
+	case telnetWILL:
+		if !c.opts.do[opt] {
+			c.send(telnetDONT, opt)
+		} else if !c.remote[opt] {
+			c.remote[opt] = true
+			c.send(telnetDO, opt)
+		}
+	case telnetWONT:
+		if c.remote[opt] {
+			c.remote[opt] = false
+			c.send(telnetDONT, opt)
+		}
+	}
+}
+
+func (c *telnetConn) subnegotiation(sb []byte) {
+	if len(sb) == 0 {
+		c.report("recv empty SB")
+		return
+	}
+	opt := sb[0]
+	if opt == telnetOptTermType && len(sb) == 2 && sb[1] == telnetTermSend {
+		c.report("recv SB TERMINAL-TYPE SEND")
+		if c.local[telnetOptTermType] {
+			c.sendSB(telnetOptTermType, append([]byte{telnetTermIs}, c.opts.termType...))
+			c.report("sent SB TERMINAL-TYPE IS " + c.opts.termType)
+		}
+		return
+	}
+	c.report(fmt.Sprintf("recv SB %s %s", telnetOptionName(opt), hexString(sb[1:])))
+}
+
+func (c *telnetConn) sendWindowSize() {
+	size := binary.BigEndian.AppendUint16(nil, uint16(c.opts.width))
+	size = binary.BigEndian.AppendUint16(size, uint16(c.opts.height))
+	c.sendSB(telnetOptNAWS, size)
+	c.report(fmt.Sprintf("sent SB NAWS %dx%d", c.opts.width, c.opts.height))
+}
+
+func (c *telnetConn) send(verb, opt byte) {
+	c.report(fmt.Sprintf("sent %s %s", telnetCommandName(verb), telnetOptionName(opt)))
+	c.writeRaw([]byte{telnetIAC, verb, opt})
+}
+
+func (c *telnetConn) sendSB(opt byte, data []byte) {
+	frame := append([]byte{telnetIAC, telnetSB, opt}, telnetEscape(data)...)
+	c.writeRaw(append(frame, telnetIAC, telnetSE))
+}
+
+func (c *telnetConn) writeRaw(data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_, err := c.Conn.Write(data)
+	return err
+}
+
+// telnetEscape doubles the IAC bytes of data
+func telnetEscape(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{telnetIAC}, []byte{telnetIAC, telnetIAC})
+}
+
+// Write sends p as data, IAC bytes are escaped
+func (c *telnetConn) Write(p []byte) (int, error) {
+	if err := c.writeRaw(telnetEscape(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// telnetSettings reads the negotiation answers from the Telnet page
+func (app *NetAssistantApp) telnetSettings() (telnetOptions, error) {
+	opts := telnetOptions{termType: "VT100", width: 80, height: 24}
+	strWill, _ := app.entryTelnetWill.GetText()
+	strDo, _ := app.entryTelnetDo.GetText()
+	var err error
+	if opts.will, err = parseTelnetOptions(strWill); err != nil {
+		return opts, err
+	}
+	if opts.do, err = parseTelnetOptions(strDo); err != nil {
+		return opts, err
+	}
+	if termType, _ := app.entryTelnetTerm.GetText(); strings.TrimSpace(termType) != "" {
+		opts.termType = strings.TrimSpace(termType)
+	}
+	for _, size := range []struct {
+		entry *gtk.Entry
+		value *int
+	}{{app.entryTelnetWidth, &opts.width}, {app.entryTelnetHeight, &opts.height}} {
+		text, _ := size.entry.GetText()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		n, err := parseNumber(text)
+		if err != nil || n < 0 || n > 65535 {
+			return opts, fmt.Errorf("invalid window size %q", strings.TrimSpace(text))
+		}
+		*size.value = int(n)
+	}
+	return opts, nil
+}
+
+// newTelnetClient wraps the TCP client connection when the telnet mode is on
+func (app *NetAssistantApp) newTelnetClient(conn net.Conn) (net.Conn, error) {
+	opts, err := app.telnetSettings()
+	if err != nil {
+		return nil, err
+	}
+	return newTelnetConn(conn, opts, func(msg string) {
+		glib.IdleAdd(func() {
+			app.tbTelnetLog.Insert(app.tbTelnetLog.GetEndIter(), msg+"\n")
+		})
+	}), nil
+}
+
+func (app *NetAssistantApp) createTelnetPage() *gtk.Box {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	box.SetBorderWidth(10)
+
+	app.cbTelnet, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_TELNET_MODE))
+	labelWill, _ := gtk.LabelNew(getI18nText(IT_TELNET_WILL))
+	labelWill.SetXAlign(0)
+	app.entryTelnetWill, _ = gtk.EntryNew()
+	app.entryTelnetWill.SetText("TERMINAL-TYPE, NAWS, SGA")
+	labelDo, _ := gtk.LabelNew(getI18nText(IT_TELNET_DO))
+	labelDo.SetXAlign(0)
+	app.entryTelnetDo, _ = gtk.EntryNew()
+	app.entryTelnetDo.SetText("ECHO, SGA")
+	app.entryTelnetTerm, _ = gtk.EntryNew()
+	app.entryTelnetTerm.SetPlaceholderText(getI18nText(IT_TELNET_TERM))
+	app.entryTelnetWidth, _ = gtk.EntryNew()
+	app.entryTelnetWidth.SetPlaceholderText(getI18nText(IT_TELNET_WIDTH))
+	app.entryTelnetHeight, _ = gtk.EntryNew()
+	app.entryTelnetHeight.SetPlaceholderText(getI18nText(IT_TELNET_HEIGHT))
+
+	logBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	logBox.SetBorderWidth(5)
+	logScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	logScroller.SetSizeRequest(220, 200)
+	tvLog, _ := gtk.TextViewNew()
+	tvLog.SetMonospace(true)
+	tvLog.SetEditable(false)
+	app.tbTelnetLog, _ = tvLog.GetBuffer()
+	logScroller.Add(tvLog)
+	btnClear, _ := gtk.ButtonNewWithLabel(getI18nText(IT_CLEAR))
+	btnClear.Connect("clicked", func() {
+		app.tbTelnetLog.SetText("")
+	})
+	logBox.PackStart(logScroller, true, true, 0)
+	logBox.PackStart(btnClear, false, false, 0)
+	logFrame, _ := gtk.FrameNew(getI18nText(IT_TELNET_LOG))
+	logFrame.Add(logBox)
+
+	box.PackStart(app.cbTelnet, false, false, 0)
+	box.PackStart(labelWill, false, false, 0)
+	box.PackStart(app.entryTelnetWill, false, false, 0)
+	box.PackStart(labelDo, false, false, 0)
+	box.PackStart(app.entryTelnetDo, false, false, 0)
+	box.PackStart(app.entryTelnetTerm, false, false, 0)
+	box.PackStart(app.entryTelnetWidth, false, false, 0)
+	box.PackStart(app.entryTelnetHeight, false, false, 0)
+	box.PackStart(logFrame, true, true, 0)
+	return box
+}
@@ -0,0 +1,911 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+const templateExample = `# type name | option=value | ...
+# types: u8 i8 u16be u16le i16be i16le u32be u32le i32be i32le u64be u64le i64be i64le
+#        f32be f32le f64be f64le float double bytes[n] string[n] cstring
+# n is a number, a field name with optional +N or -N, * for the rest of the data or *-N
+# options: const=value enum=1:READ,2:WRITE bits=name:width,... calc=crc16modbus|crc16ccitt|crc32|sum8|xor8 from=field
+# sections: if field ==|!=|<|>|<=|>=|& value ... else ... end
+u8 header | const=0xAA
+u8 cmd | enum=1:READ,2:WRITE,3:ACK
+u8 len
+if cmd == WRITE
+u16be addr
+end
+bytes[len] payload
+u16le crc | calc=crc16modbus
+`
+
+// field kinds of a template
+const (
+	kindUint = iota
+	kindInt
+	kindFloat
+	kindBytes
+	kindString
+	kindCString // zero terminated
+)
+
+type enumItem struct {
+	value int64
+	name  string
+}
+
+type bitItem struct {
+	name  string
+	width int
+}
+
+// lengthRef is the size of a bytes or string field: a number, a field value plus delta, or the rest minus delta
+type lengthRef struct {
+	fixed int
+	field string
+	rest  bool
+	delta int
+}
+
+type tmplField struct {
+	typ      string
+	name     string
+	kind     int
+	size     int // bytes of numeric fields
+	little   bool
+	length   lengthRef
+	hasConst bool
+	constVal string
+	enum     []enumItem
+	bits     []bitItem
+	calc     string
+	from     string // first field covered by calc, empty for the start of the message
+}
+
+type tmplCond struct {
+	field string
+	op    string
+	value string
+}
+
+// tmplNode is a field or a conditional section
+type tmplNode struct {
+	field *tmplField
+	cond  *tmplCond
+	then  []tmplNode
+	els   []tmplNode
+}
+
+// structTemplate describes a binary message layout
+type structTemplate struct {
+	nodes  []tmplNode
+	fields []*tmplField // all fields in template order, including those of conditional sections
+}
+
+var numericTypes = map[string]struct {
+	kind int
+	size int
+}{
+	"u8": {kindUint, 1}, "i8": {kindInt, 1},
+	"u16": {kindUint, 2}, "i16": {kindInt, 2},
+	"u32": {kindUint, 4}, "i32": {kindInt, 4},
+	"u64": {kindUint, 8}, "i64": {kindInt, 8},
+	"f32": {kindFloat, 4}, "f64": {kindFloat, 8},
+}
+
+var calcSizes = map[string]int{"crc16modbus": 2, "crc16ccitt": 2, "crc32": 4, "sum8": 1, "xor8": 1}
+
+func parseNumber(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return int64(v), nil
+}
+
+func parseLengthRef(s string) (lengthRef, error) {
+	ref := lengthRef{}
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+		ref.fixed = n
+		return ref, nil
+	}
+	base, delta := s, 0
+	if i := strings.LastIndexAny(s, "+-"); i > 0 {
+		d, err := strconv.Atoi(strings.TrimSpace(s[i:]))
+		if err != nil {
+			return ref, fmt.Errorf("invalid length %q", s)
+		}
+		base, delta = strings.TrimSpace(s[:i]), d
+	}
+	if base == "*" {
+		ref.rest = true
+	} else {
+		ref.field = base
+	}
+	ref.delta = delta
+	return ref, nil
+}
+
+func parseFieldType(f *tmplField, typ string) error {
+	switch typ {
+	case "float":
+		typ = "f32be"
+	case "double":
+		typ = "f64be"
+	case "cstring":
+		f.kind = kindCString
+		return nil
+	}
+	if open := strings.Index(typ, "["); open > 0 && strings.HasSuffix(typ, "]") {
+		switch typ[:open] {
+		case "bytes":
+			f.kind = kindBytes
+		case "string":
+			f.kind = kindString
+		default:
+			return fmt.Errorf("unknown type %q", typ)
+		}
+		var err error
+		f.length, err = parseLengthRef(typ[open+1 : len(typ)-1])
+		return err
+	}
+	base := strings.TrimSuffix(strings.TrimSuffix(typ, "be"), "le")
+	info, ok := numericTypes[base]
+	if !ok || (info.size > 1 && base == typ) || (info.size == 1 && base != typ) {
+		return fmt.Errorf("unknown type %q", typ)
+	}
+	f.kind, f.size, f.little = info.kind, info.size, strings.HasSuffix(typ, "le")
+	return nil
+}
+
+func parseTemplateField(line string) (*tmplField, error) {
+	parts := strings.Split(line, "|")
+	words := strings.Fields(parts[0])
+	if len(words) != 2 {
+		return nil, fmt.Errorf("expected type and name, got %q", strings.TrimSpace(parts[0]))
+	}
+	f := &tmplField{typ: words[0], name: words[1]}
+	if err := parseFieldType(f, words[0]); err != nil {
+		return nil, err
+	}
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid option %q", part)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "const":
+			f.hasConst, f.constVal = true, value
+		case "enum":
+			for _, item := range strings.Split(value, ",") {
+				pair := strings.SplitN(item, ":", 2)
+				if len(pair) != 2 {
+					return nil, fmt.Errorf("invalid enum item %q", item)
+				}
+				v, err := parseNumber(pair[0])
+				if err != nil {
+					return nil, err
+				}
+				f.enum = append(f.enum, enumItem{v, strings.TrimSpace(pair[1])})
+			}
+		case "bits":
+			total := 0
+			for _, item := range strings.Split(value, ",") {
+				pair := strings.SplitN(item, ":", 2)
+				width := 0
+				if len(pair) == 2 {
+					width, _ = strconv.Atoi(strings.TrimSpace(pair[1]))
+				}
+				if width < 1 {
+					return nil, fmt.Errorf("invalid bit field %q", item)
+				}
+				f.bits = append(f.bits, bitItem{strings.TrimSpace(pair[0]), width})
+				total += width
+			}
+			if f.kind != kindUint || total > 8*f.size {
+				return nil, fmt.Errorf("bit fields need an unsigned type of at least %d bits", total)
+			}
+		case "calc":
+			size, ok := calcSizes[value]
+			if !ok {
+				return nil, fmt.Errorf("unknown calc %q", value)
+			}
+			if f.kind != kindUint || f.size < size {
+				return nil, fmt.Errorf("calc %s needs an unsigned type of %d bytes", value, size)
+			}
+			f.calc = value
+		case "from":
+			f.from = value
+		default:
+			return nil, fmt.Errorf("unknown option %q", key)
+		}
+	}
+	return f, nil
+}
+
+// parseTemplate parses one field or section keyword per line, blank lines and lines starting with # are skipped
+func parseTemplate(text string) (*structTemplate, error) {
+	t := &structTemplate{}
+	type frame struct {
+		node   *tmplNode
+		inElse bool
+		line   int
+	}
+	root := &tmplNode{}
+	stack := []*frame{{node: root}}
+	add := func(node tmplNode) {
+		top := stack[len(stack)-1]
+		if top.inElse {
+			top.node.els = append(top.node.els, node)
+		} else {
+			top.node.then = append(top.node.then, node)
+		}
+	}
+	for lineNo, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(strings.TrimRight(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words := strings.Fields(line)
+		switch words[0] {
+		case "if":
+			if len(words) != 4 {
+				return nil, fmt.Errorf("line %d: expected if field op value", lineNo+1)
+			}
+			switch words[2] {
+			case "==", "!=", "<", ">", "<=", ">=", "&":
+			default:
+				return nil, fmt.Errorf("line %d: unknown operator %q", lineNo+1, words[2])
+			}
+			stack = append(stack, &frame{node: &tmplNode{cond: &tmplCond{words[1], words[2], words[3]}}, line: lineNo + 1})
+		case "else":
+			top := stack[len(stack)-1]
+			if len(stack) == 1 || top.inElse {
+				return nil, fmt.Errorf("line %d: else without if", lineNo+1)
+			}
+			top.inElse = true
+		case "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("line %d: end without if", lineNo+1)
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			add(*top.node)
+		default:
+			f, err := parseTemplateField(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", lineNo+1, err)
+			}
+			if f.from != "" && !hasField(t.fields, f.from) {
+				return nil, fmt.Errorf("line %d: from=%s is not a field before %s", lineNo+1, f.from, f.name)
+			}
+			add(tmplNode{field: f})
+			t.fields = append(t.fields, f)
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("line %d: if without end", stack[len(stack)-1].line)
+	}
+	if len(t.fields) == 0 {
+		return nil, errors.New("template is empty")
+	}
+	t.nodes = root.then
+	return t, nil
+}
+
+// enumValue resolves an enum name of the field, otherwise s is a number
+func (f *tmplField) enumValue(s string) (int64, error) {
+	for _, item := range f.enum {
+		if item.name == s {
+			return item.value, nil
+		}
+	}
+	return parseNumber(s)
+}
+
+func (f *tmplField) enumName(v int64) string {
+	for _, item := range f.enum {
+		if item.value == v {
+			return item.name
+		}
+	}
+	return ""
+}
+
+func hasField(fields []*tmplField, name string) bool {
+	for _, f := range fields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldByName finds a field, bit fields are found through the field that holds them
+func (t *structTemplate) fieldByName(name string) *tmplField {
+	for _, f := range t.fields {
+		if f.name == name {
+			return f
+		}
+		for _, bit := range f.bits {
+			if bit.name == name {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// test evaluates a condition against the values seen so far, unknown fields are false
+func (t *structTemplate) test(c *tmplCond, env map[string]int64) bool {
+	v, ok := env[c.field]
+	if !ok {
+		return false
+	}
+	want, err := parseNumber(c.value)
+	if f := t.fieldByName(c.field); f != nil && f.name == c.field {
+		want, err = f.enumValue(c.value)
+	}
+	if err != nil {
+		return false
+	}
+	switch c.op {
+	case "==":
+		return v == want
+	case "!=":
+		return v != want
+	case "<":
+		return v < want
+	case ">":
+		return v > want
+	case "<=":
+		return v <= want
+	case ">=":
+		return v >= want
+	}
+	return v&want != 0
+}
+
+// calcChecksum computes the calc algorithm of a field
+func calcChecksum(algo string, data []byte) uint64 {
+	switch algo {
+	case "crc16modbus":
+		crc := uint16(0xFFFF)
+		for _, b := range data {
+			crc ^= uint16(b)
+			for i := 0; i < 8; i++ {
+				if crc&1 != 0 {
+					crc = crc>>1 ^ 0xA001
+				} else {
+					crc >>= 1
+				}
+			}
+		}
+		return uint64(crc)
+	case "crc16ccitt":
+		crc := uint16(0xFFFF)
+		for _, b := range data {
+			crc ^= uint16(b) << 8
+			for i := 0; i < 8; i++ {
+				if crc&0x8000 != 0 {
+					crc = crc<<1 ^ 0x1021
+				} else {
+					crc <<= 1
+				}
+			}
+		}
+		return uint64(crc)
+	case "crc32":
+		return uint64(crc32.ChecksumIEEE(data))
+	case "sum8":
+		var sum byte
+		for _, b := range data {
+			sum += b
+		}
+		return uint64(sum)
+	}
+	var x byte
+	for _, b := range data {
+		x ^= b
+	}
+	return uint64(x)
+}
+
+func readUint(b []byte, little bool) uint64 {
+	var v uint64
+	for i := range b {
+		if little {
+			v |= uint64(b[i]) << (8 * i)
+		} else {
+			v = v<<8 | uint64(b[i])
+		}
+	}
+	return v
+}
+
+func putUint(b []byte, v uint64, little bool) {
+	for i := range b {
+		if little {
+			b[i] = byte(v >> (8 * i))
+		} else {
+			b[len(b)-1-i] = byte(v >> (8 * i))
+		}
+	}
+}
+
+// tmplRow is one decoded field
+type tmplRow struct {
+	offset int
+	name   string
+	typ    string
+	raw    []byte
+	value  string
+	note   string
+}
+
+// templateDecoder walks the template over received data
+type templateDecoder struct {
+	t       *structTemplate
+	data    []byte
+	pos     int
+	env     map[string]int64
+	offsets map[string]int
+	rows    []tmplRow
+}
+
+func (d *templateDecoder) length(f *tmplField) (int, error) {
+	ref := f.length
+	n := ref.fixed
+	if ref.rest {
+		n = len(d.data) - d.pos + ref.delta
+	} else if ref.field != "" {
+		v, ok := d.env[ref.field]
+		if !ok {
+			return 0, fmt.Errorf("%s: unknown length field %s", f.name, ref.field)
+		}
+		n = int(v) + ref.delta
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("%s: negative length %d", f.name, n)
+	}
+	return n, nil
+}
+
+func (d *templateDecoder) field(f *tmplField) error {
+	start := d.pos
+	d.offsets[f.name] = start
+	row := tmplRow{offset: start, name: f.name, typ: f.typ}
+	var size int
+	switch f.kind {
+	case kindBytes, kindString:
+		n, err := d.length(f)
+		if err != nil {
+			return err
+		}
+		size = n
+	case kindCString:
+		end := strings.IndexByte(string(d.data[start:]), 0)
+		if end < 0 {
+			return fmt.Errorf("%s: missing terminating zero", f.name)
+		}
+		size = end + 1
+	default:
+		size = f.size
+	}
+	if size > len(d.data)-start {
+		return fmt.Errorf("%s: needs %d bytes, %d left", f.name, size, len(d.data)-start)
+	}
+	raw := d.data[start : start+size]
+	d.pos += size
+	row.raw = raw
+	switch f.kind {
+	case kindBytes:
+		row.value = fmt.Sprintf("(%d bytes)", size)
+		d.env[f.name] = int64(size)
+	case kindString, kindCString:
+		row.value = strconv.Quote(strings.TrimRight(string(raw), "\x00"))
+		d.env[f.name] = int64(size)
+	case kindFloat:
+		bits := readUint(raw, f.little)
+		if f.size == 4 {
+			row.value = strconv.FormatFloat(float64(math.Float32frombits(uint32(bits))), 'g', -1, 32)
+		} else {
+			row.value = strconv.FormatFloat(math.Float64frombits(bits), 'g', -1, 64)
+		}
+	default:
+		u := readUint(raw, f.little)
+		v := int64(u)
+		if f.kind == kindInt {
+			shift := 64 - 8*f.size
+			v = int64(u<<shift) >> shift
+		}
+		d.env[f.name] = v
+		row.value = strconv.FormatInt(v, 10)
+		if f.kind == kindUint {
+			row.value = strconv.FormatUint(u, 10)
+		}
+		if name := f.enumName(v); name != "" {
+			row.value = fmt.Sprintf("%s (%s)", name, row.value)
+		}
+		if f.hasConst {
+			if want, err := f.enumValue(f.constVal); err == nil && want != v {
+				row.note = fmt.Sprintf("expected %s", f.constVal)
+			}
+		}
+		if f.calc != "" {
+			from, ok := d.offsets[f.from]
+			if !ok {
+				return fmt.Errorf("%s: from field %s is not in the message", f.name, f.from)
+			}
+			want := calcChecksum(f.calc, d.data[from:start])
+			if want == u {
+				row.note = "ok"
+			} else {
+				row.note = fmt.Sprintf("bad, expected 0x%0*X", 2*f.size, want)
+			}
+		}
+	}
+	d.rows = append(d.rows, row)
+	shift := 8 * f.size
+	for _, bit := range f.bits {
+		shift -= bit.width
+		v := int64(readUint(raw, f.little) >> uint(shift) & (1<<uint(bit.width) - 1))
+		d.env[bit.name] = v
+		d.rows = append(d.rows, tmplRow{offset: -1, name: "." + bit.name, typ: fmt.Sprintf("bits:%d", bit.width), value: strconv.FormatInt(v, 10)})
+	}
+	return nil
+}
+
+func (d *templateDecoder) walk(nodes []tmplNode) error {
+	for _, node := range nodes {
+		if node.field != nil {
+			if err := d.field(node.field); err != nil {
+				return err
+			}
+			continue
+		}
+		branch := node.els
+		if d.t.test(node.cond, d.env) {
+			branch = node.then
+		}
+		if err := d.walk(branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decode parses data into a field table, the fields decoded before an error are kept
+func (t *structTemplate) decode(data []byte) (string, error) {
+	d := &templateDecoder{t: t, data: data, env: map[string]int64{}, offsets: map[string]int{"": 0}}
+	err := d.walk(t.nodes)
+	var sb strings.Builder
+	for _, row := range d.rows {
+		offset := "    "
+		if row.offset >= 0 {
+			offset = fmt.Sprintf("%04X", row.offset)
+		}
+		raw := hexString(row.raw)
+		if len(row.raw) > 16 {
+			raw = hexString(row.raw[:16]) + " ..."
+		}
+		line := fmt.Sprintf("%s  %-12s %-10s %-24s %s", offset, row.name, row.typ, raw, row.value)
+		if row.note != "" {
+			line += "  [" + row.note + "]"
+		}
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	if err == nil && d.pos < len(data) {
+		sb.WriteString(fmt.Sprintf("%04X  %d trailing bytes\n", d.pos, len(data)-d.pos))
+	}
+	if err != nil {
+		sb.WriteString("(" + err.Error() + ")\n")
+	}
+	return sb.String(), nil
+}
+
+// templateBuilder encodes form values into a message
+type templateBuilder struct {
+	t       *structTemplate
+	values  map[string]string
+	env     map[string]int64
+	out     []byte
+	offsets map[string]int
+}
+
+// numeric returns the value of a numeric field from the form, its constant or a computed length
+func (b *templateBuilder) numeric(f *tmplField) (int64, error) {
+	if len(f.bits) > 0 {
+		var v int64
+		for _, bit := range f.bits {
+			n, err := parseNumber(defaultString(b.values[bit.name], "0"))
+			if err != nil {
+				return 0, fmt.Errorf("%s: %s", bit.name, err)
+			}
+			v = v<<uint(bit.width) | n&(1<<uint(bit.width)-1)
+			b.env[bit.name] = n
+		}
+		used := 0
+		for _, bit := range f.bits {
+			used += bit.width
+		}
+		return v << uint(8*f.size-used), nil
+	}
+	s := strings.TrimSpace(b.values[f.name])
+	if s == "" {
+		if v, ok := b.env[f.name]; ok {
+			return v, nil
+		}
+		s = defaultString(f.constVal, "0")
+	}
+	v, err := f.enumValue(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %s", f.name, err)
+	}
+	return v, nil
+}
+
+func defaultString(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+// blob returns the bytes of a bytes or string field, bytes are entered as hex and strings accept escapes
+func (b *templateBuilder) blob(f *tmplField) ([]byte, error) {
+	data, err := parsePayload(b.values[f.name], f.kind == kindBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", f.name, err)
+	}
+	if f.kind == kindCString {
+		return append(data, 0), nil
+	}
+	if f.length.field == "" && !f.length.rest {
+		if len(data) > f.length.fixed {
+			return nil, fmt.Errorf("%s: longer than %d bytes", f.name, f.length.fixed)
+		}
+		data = append(data, make([]byte, f.length.fixed-len(data))...)
+	}
+	return data, nil
+}
+
+func (b *templateBuilder) field(f *tmplField) error {
+	b.offsets[f.name] = len(b.out)
+	switch f.kind {
+	case kindBytes, kindString, kindCString:
+		data, err := b.blob(f)
+		if err != nil {
+			return err
+		}
+		if ref := f.length.field; ref != "" && int64(len(data)) != b.env[ref]+int64(f.length.delta) {
+			return fmt.Errorf("%s: length %d does not match %s", f.name, len(data), ref)
+		}
+		b.out = append(b.out, data...)
+		return nil
+	case kindFloat:
+		v, err := strconv.ParseFloat(defaultString(b.values[f.name], defaultString(f.constVal, "0")), 64)
+		if err != nil {
+			return fmt.Errorf("%s: invalid number", f.name)
+		}
+		raw := make([]byte, f.size)
+		if f.size == 4 {
+			putUint(raw, uint64(math.Float32bits(float32(v))), f.little)
+		} else {
+			putUint(raw, math.Float64bits(v), f.little)
+		}
+		b.out = append(b.out, raw...)
+		return nil
+	}
+	var u uint64
+	if f.calc != "" {
+		from, ok := b.offsets[f.from]
+		if !ok {
+			return fmt.Errorf("%s: from field %s is not in the message", f.name, f.from)
+		}
+		u = calcChecksum(f.calc, b.out[from:])
+	} else {
+		v, err := b.numeric(f)
+		if err != nil {
+			return err
+		}
+		b.env[f.name] = v
+		u = uint64(v)
+	}
+	raw := make([]byte, f.size)
+	putUint(raw, u, f.little)
+	b.out = append(b.out, raw...)
+	return nil
+}
+
+func (b *templateBuilder) walk(nodes []tmplNode) error {
+	for _, node := range nodes {
+		if node.field != nil {
+			if err := b.field(node.field); err != nil {
+				return err
+			}
+			continue
+		}
+		branch := node.els
+		if b.t.test(node.cond, b.env) {
+			branch = node.then
+		}
+		if err := b.walk(branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// build encodes the form values, empty length fields are filled in from the data they describe
+func (t *structTemplate) build(values map[string]string) ([]byte, error) {
+	b := &templateBuilder{t: t, values: values, env: map[string]int64{}, offsets: map[string]int{"": 0}}
+	for _, f := range t.fields {
+		ref := f.length.field
+		if ref == "" || strings.TrimSpace(values[ref]) != "" {
+			continue
+		}
+		data, err := b.blob(f)
+		if err != nil {
+			return nil, err
+		}
+		b.env[ref] = int64(len(data) - f.length.delta)
+	}
+	if err := b.walk(t.nodes); err != nil {
+		return nil, err
+	}
+	return b.out, nil
+}
+
+func (app *NetAssistantApp) onBtnTemplate() {
+	dialog, _ := gtk.DialogNew()
+	dialog.SetTitle(getI18nText(IT_TEMPLATE))
+	dialog.SetTransientFor(app.appWindow)
+	dialog.SetModal(true)
+	dialog.SetDefaultSize(560, 360)
+	dialog.AddButton("Cancel", gtk.RESPONSE_CANCEL)
+	dialog.AddButton("OK", gtk.RESPONSE_OK)
+	content, _ := dialog.GetContentArea()
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	tv, _ := gtk.TextViewNew()
+	tv.SetMonospace(true)
+	buff, _ := tv.GetBuffer()
+	if app.templateText == "" {
+		buff.SetText(templateExample)
+	} else {
+		buff.SetText(app.templateText)
+	}
+	scroller.Add(tv)
+	btnOpen, _ := gtk.ButtonNewWithLabel(getI18nText(IT_OPEN))
+	btnOpen.Connect("clicked", func() {
+		chooser, _ := gtk.FileChooserNativeDialogNew(getI18nText(IT_OPEN), app.appWindow, gtk.FILE_CHOOSER_ACTION_OPEN, "Open", "Cancel")
+		res := chooser.Run()
+		fileName := chooser.FileChooser.GetFilename()
+		chooser.Destroy()
+		if res != int(gtk.RESPONSE_ACCEPT) {
+			return
+		}
+		data, err := os.ReadFile(fileName)
+		if err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+			return
+		}
+		buff.SetText(string(data))
+	})
+	content.PackStart(scroller, true, true, 0)
+	content.PackStart(btnOpen, false, false, 0)
+	dialog.ShowAll()
+	if dialog.Run() == gtk.RESPONSE_OK {
+		start, end := buff.GetBounds()
+		app.templateText, _ = buff.GetText(start, end, true)
+		t, err := parseTemplate(app.templateText)
+		if err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		} else {
+			app.template = t
+			if app.combRecvDecoder.GetActive() == decoderTemplate {
+				app.onDecoderChanged()
+			} else {
+				app.combRecvDecoder.SetActive(decoderTemplate)
+			}
+		}
+	}
+	dialog.Destroy()
+}
+
+// onBtnTemplateForm builds a message from a form of the template fields and puts it in the send area as hex
+func (app *NetAssistantApp) onBtnTemplateForm() {
+	if app.template == nil {
+		app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, getI18nText(IT_NO_TEMPLATE)))
+		return
+	}
+	if app.templateValues == nil {
+		app.templateValues = map[string]string{}
+	}
+	dialog, _ := gtk.DialogNew()
+	dialog.SetTitle(getI18nText(IT_TEMPLATE_FORM))
+	dialog.SetTransientFor(app.appWindow)
+	dialog.SetModal(true)
+	dialog.SetDefaultSize(420, 360)
+	dialog.AddButton("Cancel", gtk.RESPONSE_CANCEL)
+	dialog.AddButton("OK", gtk.RESPONSE_OK)
+	content, _ := dialog.GetContentArea()
+	grid, _ := gtk.GridNew()
+	grid.SetRowSpacing(5)
+	grid.SetColumnSpacing(10)
+	grid.SetBorderWidth(10)
+	entries := map[string]*gtk.Entry{}
+	row := 0
+	addRow := func(name, typ, hint string, editable bool) {
+		label, _ := gtk.LabelNew(name)
+		label.SetXAlign(0)
+		labelType, _ := gtk.LabelNew(typ)
+		labelType.SetXAlign(0)
+		entry, _ := gtk.EntryNew()
+		entry.SetHExpand(true)
+		entry.SetPlaceholderText(hint)
+		entry.SetText(app.templateValues[name])
+		entry.SetSensitive(editable)
+		if editable {
+			entries[name] = entry
+		}
+		grid.Attach(label, 0, row, 1, 1)
+		grid.Attach(labelType, 1, row, 1, 1)
+		grid.Attach(entry, 2, row, 1, 1)
+		row++
+	}
+	for _, f := range app.template.fields {
+		switch {
+		case f.calc != "":
+			addRow(f.name, f.typ, f.calc, false)
+		case len(f.bits) > 0:
+			for _, bit := range f.bits {
+				addRow(bit.name, fmt.Sprintf("%s:%d", f.name, bit.width), "0", true)
+			}
+		case f.kind == kindBytes:
+			addRow(f.name, f.typ, "hex", true)
+		case len(f.enum) > 0:
+			names := []string{}
+			for _, item := range f.enum {
+				names = append(names, item.name)
+			}
+			addRow(f.name, f.typ, strings.Join(names, " | "), true)
+		default:
+			addRow(f.name, f.typ, f.constVal, true)
+		}
+	}
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	scroller.Add(grid)
+	content.PackStart(scroller, true, true, 0)
+	dialog.ShowAll()
+	if dialog.Run() == gtk.RESPONSE_OK {
+		for name, entry := range entries {
+			app.templateValues[name], _ = entry.GetText()
+		}
+		data, err := app.template.build(app.templateValues)
+		if err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		} else {
+			app.cbSendByHex.SetActive(true)
+			buff, _ := app.tvDataSend.GetBuffer()
+			buff.SetText(hexString(data))
+		}
+	}
+	dialog.Destroy()
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gotk3/gotk3/glib"
+	"github.com/gotk3/gotk3/gtk"
+)
+
+const wsDialTimeout = 10 * time.Second
+
+// wsSettings is read on the gui thread when connecting, the server answers every handshake with it
+type wsSettings struct {
+	path      string
+	tls       bool
+	insecure  bool
+	protocols []string
+	header    http.Header
+}
+
+// parseWsHeaders reads one "Name: value" header per line
+func parseWsHeaders(text string) (http.Header, error) {
+	headers, err := parseHeaderLines(text)
+	header := http.Header{}
+	for _, h := range headers {
+		header.Add(h.name, h.value)
+	}
+	return header, err
+}
+
+func (app *NetAssistantApp) wsSettings() (wsSettings, error) {
+	settings := wsSettings{
+		tls:      app.cbWsTLS.GetActive(),
+		insecure: app.cbWsInsecure.GetActive(),
+	}
+	settings.path, _ = app.entryWsPath.GetText()
+	settings.path = strings.TrimSpace(settings.path)
+	if settings.path == "" {
+		settings.path = "/"
+	}
+	protocols, _ := app.entryWsProtocols.GetText()
+	for _, protocol := range strings.Split(protocols, ",") {
+		if protocol = strings.TrimSpace(protocol); protocol != "" {
+			settings.protocols = append(settings.protocols, protocol)
+		}
+	}
+	start, end := app.tbWsHeaders.GetBounds()
+	text, _ := app.tbWsHeaders.GetText(start, end, true)
+	header, err := parseWsHeaders(text)
+	settings.header = header
+	return settings, err
+}
+
+// wsOpcode is the frame type used by the send box
+func (app *NetAssistantApp) wsOpcode() byte {
+	if app.combWsFrameType.GetActive() == 0 {
+		return wsText
+	}
+	return wsBinary
+}
+
+// dialWebSocket connects the WebSocket client mode
+func (app *NetAssistantApp) dialWebSocket(addr string) (*wsConn, error) {
+	settings, err := app.wsSettings()
+	if err != nil {
+		return nil, err
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: wsDialTimeout}
+	var conn net.Conn
+	if settings.tls {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: host, InsecureSkipVerify: settings.insecure})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(wsDialTimeout))
+	ws, err := wsClientHandshake(conn, addr, settings.path, settings.protocols, settings.header)
+	conn.SetDeadline(time.Time{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	ws.setOpcode(app.wsOpcode())
+	scheme := "ws"
+	if settings.tls {
+		scheme = "wss"
+	}
+	msg := fmt.Sprintf("[WS] connected to %s://%s%s", scheme, addr, settings.path)
+	if ws.protocol != "" {
+		msg += ", subprotocol " + ws.protocol
+	}
+	app.appendRecvLog(msg)
+	return ws, nil
+}
+
+// serveWebSocket accepts WebSocket clients on the listener until it is closed
+func (app *NetAssistantApp) serveWebSocket(listen net.Listener, settings wsSettings) {
+	for {
+		conn, err := listen.Accept()
+		if err != nil {
+			log.Error("accept err:", err)
+			return
+		}
+		go func() {
+			conn.SetDeadline(time.Now().Add(wsDialTimeout))
+			ws, req, err := wsServerHandshake(conn, settings.protocols, settings.header)
+			conn.SetDeadline(time.Time{})
+			ss := conn.RemoteAddr().String()
+			if err != nil {
+				conn.Close()
+				glib.IdleAdd(func() {
+					app.appendRecvLog(fmt.Sprintf("[WS] %s rejected: %s", ss, err))
+				})
+				return
+			}
+			msg := fmt.Sprintf("[WS] %s upgraded %s", ss, req.URL.RequestURI())
+			if ws.protocol != "" {
+				msg += ", subprotocol " + ws.protocol
+			}
+			glib.IdleAdd(func() {
+				app.labelStatus.SetMarkup(fmt.Sprintf(`<span foreground="green">new connection:%s </span>`, ss))
+				app.appendRecvLog(msg)
+				ws.setOpcode(app.wsOpcode())
+				app.connList = append(app.connList, ws)
+			})
+			app.wsHandler(ws)
+		}()
+	}
+}
+
+// wsHandler reads frames one by one, data frames go to the receive area tagged with opcode and length
+func (app *NetAssistantApp) wsHandler(ws *wsConn) {
+	defer ws.Conn.Close()
+	ss := ws.RemoteAddr().String()
+	report := func(msg string) {
+		glib.IdleAdd(func() {
+			app.appendRecvLog(fmt.Sprintf("[WS] %s %s", ss, msg))
+		})
+	}
+	var err error
+	for err == nil {
+		var fin bool
+		var opcode byte
+		var payload []byte
+		fin, opcode, payload, err = ws.readFrame()
+		if err != nil {
+			break
+		}
+		tag := wsFrameTag(fin, opcode, len(payload))
+		switch opcode {
+		case wsContinuation, wsText, wsBinary:
+			app.receive(ws, nil, payload, tag)
+		case wsPing:
+			report(fmt.Sprintf("%s %q", tag, payload))
+			if atomic.LoadInt32(&app.wsAutoPong) != 0 {
+				err = ws.writeFrame(wsPong, payload)
+			}
+		case wsPong:
+			report(fmt.Sprintf("%s %q", tag, payload))
+		case wsClose:
+			report(tag + " " + wsCloseStatus(payload))
+			if !ws.isClosing() {
+				ws.writeFrame(wsClose, payload[:min(2, len(payload))])
+			}
+			err = errors.New("closed by peer: " + wsCloseStatus(payload))
+		default:
+			report(tag)
+			ws.writeClose(1002, "unknown opcode")
+			err = errors.New("unknown opcode")
+		}
+	}
+	log.Info("websocket connection closed:", err)
+	tips := fmt.Sprintf(`<span foreground="red">connection closed: %s </span>`, glib.MarkupEscapeText(ss+" "+err.Error()))
+	glib.IdleAdd(func() {
+		app.labelStatus.SetMarkup(tips)
+		for index, connItem := range app.connList {
+			if connItem == net.Conn(ws) {
+				app.connList = append(app.connList[:index], app.connList[index+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// wsConns lists the open WebSocket connections
+func (app *NetAssistantApp) wsConns() []*wsConn {
+	var conns []*wsConn
+	for _, conn := range app.connList {
+		if ws, ok := conn.(*wsConn); ok {
+			conns = append(conns, ws)
+		}
+	}
+	return conns
+}
+
+func (app *NetAssistantApp) onWsFrameTypeChanged() {
+	for _, ws := range app.wsConns() {
+		ws.setOpcode(app.wsOpcode())
+	}
+}
+
+func (app *NetAssistantApp) onBtnWsPing() {
+	conns := app.wsConns()
+	if len(conns) == 0 {
+		app.labelStatus.SetText(getI18nText(IT_NO_CONN))
+		return
+	}
+	payload, _ := app.entryWsPing.GetText()
+	if len(payload) > 125 {
+		app.updateStatus(`<span foreground="red">ping payload is limited to 125 bytes</span>`)
+		return
+	}
+	for _, ws := range conns {
+		if err := ws.writeFrame(wsPing, []byte(payload)); err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		}
+	}
+}
+
+// onBtnWsClose starts the closing handshake, the connection is dropped when the peer answers
+func (app *NetAssistantApp) onBtnWsClose() {
+	conns := app.wsConns()
+	if len(conns) == 0 {
+		app.labelStatus.SetText(getI18nText(IT_NO_CONN))
+		return
+	}
+	code := int64(1000)
+	strCode, _ := app.entryWsCloseCode.GetText()
+	if strings.TrimSpace(strCode) != "" {
+		var err error
+		code, err = parseNumber(strCode)
+		if err != nil || !wsCloseCodeSendable(int(code)) {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">invalid close code %q</span>`, glib.MarkupEscapeText(strings.TrimSpace(strCode))))
+			return
+		}
+	}
+	reason, _ := app.entryWsCloseReason.GetText()
+	if len(reason) > 123 {
+		app.updateStatus(`<span foreground="red">close reason is limited to 123 bytes</span>`)
+		return
+	}
+	for _, ws := range conns {
+		if err := ws.writeClose(int(code), reason); err != nil {
+			app.updateStatus(fmt.Sprintf(`<span foreground="red">%s</span>`, glib.MarkupEscapeText(err.Error())))
+		}
+	}
+}
+
+func (app *NetAssistantApp) createWebSocketPage() *gtk.ScrolledWindow {
+	box, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	box.SetBorderWidth(10)
+
+	app.entryWsPath, _ = gtk.EntryNew()
+	app.entryWsPath.SetPlaceholderText(getI18nText(IT_WS_PATH))
+	app.cbWsTLS, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_WS_TLS))
+	app.cbWsInsecure, _ = gtk.CheckButtonNewWithLabel(getI18nText(IT_TLS_INSECURE))
+	app.entryWsProtocols, _ = gtk.EntryNew()
+	app.entryWsProtocols.SetPlaceholderText(getI18nText(IT_WS_PROTOCOLS))
+	labelHeaders, _ := gtk.LabelNew(getI18nText(IT_WS_HEADERS))
+	labelHeaders.SetXAlign(0)
+	headerScroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	headerScroller.SetSizeRequest(220, 80)
+	tvHeaders, _ := gtk.TextViewNew()
+	tvHeaders.SetMonospace(true)
+	app.tbWsHeaders, _ = tvHeaders.GetBuffer()
+	headerScroller.Add(tvHeaders)
+
+	app.combWsFrameType, _ = gtk.ComboBoxTextNew()
+	app.combWsFrameType.AppendText(getI18nText(IT_WS_TEXT))
+	app.combWsFrameType.AppendText(getI18nText(IT_WS_BINARY))
+	app.combWsFrameType.SetActive(0)
+	app.combWsFrameType.Connect("changed", app.onWsFrameTypeChanged)
+
+	pingBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	pingBox.SetBorderWidth(5)
+	cbAutoPong, _ := gtk.CheckButtonNewWithLabel(getI18nText(IT_WS_AUTO_PONG))
+	cbAutoPong.Connect("toggled", func() {
+		var autoPong int32
+		if cbAutoPong.GetActive() {
+			autoPong = 1
+		}
+		atomic.StoreInt32(&app.wsAutoPong, autoPong)
+	})
+	cbAutoPong.SetActive(true)
+	app.entryWsPing, _ = gtk.EntryNew()
+	app.entryWsPing.SetPlaceholderText(getI18nText(IT_MQTT_PAYLOAD))
+	btnPing, _ := gtk.ButtonNewWithLabel(getI18nText(IT_WS_SEND_PING))
+	btnPing.Connect("clicked", app.onBtnWsPing)
+	pingBox.PackStart(cbAutoPong, false, false, 0)
+	pingBox.PackStart(app.entryWsPing, false, false, 0)
+	pingBox.PackStart(btnPing, false, false, 0)
+	pingFrame, _ := gtk.FrameNew("Ping / Pong")
+	pingFrame.Add(pingBox)
+
+	closeBox, _ := gtk.BoxNew(gtk.ORIENTATION_VERTICAL, 5)
+	closeBox.SetBorderWidth(5)
+	app.entryWsCloseCode, _ = gtk.EntryNew()
+	app.entryWsCloseCode.SetPlaceholderText(getI18nText(IT_WS_CLOSE_CODE))
+	app.entryWsCloseReason, _ = gtk.EntryNew()
+	app.entryWsCloseReason.SetPlaceholderText(getI18nText(IT_WS_CLOSE_REASON))
+	btnClose, _ := gtk.ButtonNewWithLabel(getI18nText(IT_WS_SEND_CLOSE))
+	btnClose.Connect("clicked", app.onBtnWsClose)
+	closeBox.PackStart(app.entryWsCloseCode, false, false, 0)
+	closeBox.PackStart(app.entryWsCloseReason, false, false, 0)
+	closeBox.PackStart(btnClose, false, false, 0)
+	closeFrame, _ := gtk.FrameNew(getI18nText(IT_WS_CLOSE))
+	closeFrame.Add(closeBox)
+
+	box.PackStart(app.entryWsPath, false, false, 0)
+	box.PackStart(app.cbWsTLS, false, false, 0)
+	box.PackStart(app.cbWsInsecure, false, false, 0)
+	box.PackStart(app.entryWsProtocols, false, false, 0)
+	box.PackStart(labelHeaders, false, false, 0)
+	box.PackStart(headerScroller, false, false, 0)
+	box.PackStart(app.combWsFrameType, false, false, 0)
+	box.PackStart(pingFrame, false, false, 0)
+	box.PackStart(closeFrame, false, false, 0)
+
+	scroller, _ := gtk.ScrolledWindowNew(nil, nil)
+	scroller.SetPolicy(gtk.POLICY_NEVER, gtk.POLICY_AUTOMATIC)
+	scroller.Add(box)
+	return scroller
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// websocket opcodes, RFC 6455 section 5.2
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// frames above this size are refused instead of buffered
+const wsMaxFrame = 16 << 20
+
+var wsOpcodeNames = map[byte]string{
+	wsContinuation: "CONTINUATION",
+	wsText:         "TEXT",
+	wsBinary:       "BINARY",
+	wsClose:        "CLOSE",
+	wsPing:         "PING",
+	wsPong:         "PONG",
+}
+
+// close status codes, RFC 6455 section 7.4.1
+var wsCloseCodes = map[int]string{
+	1000: "normal closure",
+	1001: "going away",
+	1002: "protocol error",
+	1003: "unsupported data",
+	1005: "no status received",
+	1006: "abnormal closure",
+	1007: "invalid frame payload data",
+	1008: "policy violation",
+	1009: "message too big",
+	1010: "mandatory extension",
+	1011: "internal server error",
+	1015: "TLS handshake",
+}
+
+// wsCloseCodeSendable reports whether code may be sent in a close frame, RFC 6455 section 7.4,
+// 0 sends a close frame without a status
+func wsCloseCodeSendable(code int) bool {
+	switch {
+	case code == 0, code >= 1000 && code <= 1003, code >= 1007 && code <= 1014, code >= 3000 && code <= 4999:
+		return true
+	}
+	return false
+}
+
+// wsFrameTag describes a received frame for the receive area
+func wsFrameTag(fin bool, opcode byte, size int) string {
+	name, ok := wsOpcodeNames[opcode]
+	if !ok {
+		name = fmt.Sprintf("OPCODE 0x%X", opcode)
+	}
+	tag := fmt.Sprintf("%s %dB", name, size)
+	if !fin {
+		tag += " fragment"
+	}
+	return tag
+}
+
+// wsCloseStatus formats the payload of a close frame
+func wsCloseStatus(payload []byte) string {
+	if len(payload) < 2 {
+		return "1005 " + wsCloseCodes[1005]
+	}
+	code := int(binary.BigEndian.Uint16(payload))
+	status := fmt.Sprintf("%d %s", code, wsCloseCodes[code])
+	if len(payload) > 2 {
+		status += fmt.Sprintf(" %q", payload[2:])
+	}
+	return strings.TrimSpace(status)
+}
+
+func wsAcceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// wsConn carries a byte stream in websocket messages, control frames are answered while reading
+type wsConn struct {
+	net.Conn
+	reader   *bufio.Reader
+	client   bool // client frames are masked
+	protocol string
+	wmu      sync.Mutex
+	opcode   byte // frame type of Write
+	closed   bool // a close frame was sent
+	readBuf  []byte
+}
+
+// wsHeaderValues splits a comma separated header such as Sec-WebSocket-Protocol
+func wsHeaderValues(header http.Header, name string) []string {
+	var values []string
+	for _, value := range header.Values(name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// wsServerHandshake answers the upgrade request of a client, the first offered protocol found in protocols is selected
+func wsServerHandshake(conn net.Conn, protocols []string, header http.Header) (*wsConn, *http.Request, error) {
+	reader := bufio.NewReader(conn)
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("websocket handshake: %s", err)
+	}
+	key := req.Header.Get("Sec-WebSocket-Key")
+	upgrade := false
+	for _, value := range wsHeaderValues(req.Header, "Upgrade") {
+		upgrade = upgrade || strings.EqualFold(value, "websocket")
+	}
+	if req.Method != http.MethodGet || !upgrade || key == "" || req.Header.Get("Sec-WebSocket-Version") != "13" {
+		io.WriteString(conn, "HTTP/1.1 400 Bad Request\r\nSec-WebSocket-Version: 13\r\nContent-Length: 0\r\n\r\n")
+		return nil, req, errors.New("websocket handshake: not a websocket upgrade request")
+	}
+	ws := &wsConn{Conn: conn, reader: reader, opcode: wsBinary}
+	for _, offered := range wsHeaderValues(req.Header, "Sec-WebSocket-Protocol") {
+		for _, protocol := range protocols {
+			if ws.protocol == "" && offered == protocol {
+				ws.protocol = protocol
+			}
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	fmt.Fprintf(&sb, "Sec-WebSocket-Accept: %s\r\n", wsAcceptKey(key))
+	if ws.protocol != "" {
+		fmt.Fprintf(&sb, "Sec-WebSocket-Protocol: %s\r\n", ws.protocol)
+	}
+	for name, values := range header {
+		for _, value := range values {
+			fmt.Fprintf(&sb, "%s: %s\r\n", name, value)
+		}
+	}
+	sb.WriteString("\r\n")
+	if _, err := io.WriteString(conn, sb.String()); err != nil {
+		return nil, req, err
+	}
+	return ws, req, nil
+}
+
+// wsClientHandshake upgrades conn to a websocket, header adds custom fields to the request
+func wsClientHandshake(conn net.Conn, host, path string, protocols []string, header http.Header) (*wsConn, error) {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+	if path == "" {
+		path = "/"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n", path, host)
+	fmt.Fprintf(&sb, "Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n", key)
+	if len(protocols) > 0 {
+		fmt.Fprintf(&sb, "Sec-WebSocket-Protocol: %s\r\n", strings.Join(protocols, ", "))
+	}
+	for name, values := range header {
+		for _, value := range values {
+			fmt.Fprintf(&sb, "%s: %s\r\n", name, value)
+		}
+	}
+	sb.WriteString("\r\n")
+	if _, err := io.WriteString(conn, sb.String()); err != nil {
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		return nil, fmt.Errorf("websocket handshake: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket handshake: %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != wsAcceptKey(key) {
+		return nil, errors.New("websocket handshake: bad Sec-WebSocket-Accept")
+	}
+	return &wsConn{Conn: conn, reader: reader, client: true, opcode: wsBinary, protocol: resp.Header.Get("Sec-WebSocket-Protocol")}, nil
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	mask := byte(0)
+	if c.client {
+		mask = 0x80
+	}
+	switch size := len(payload); {
+	case size < 126:
+		frame = append(frame, mask|byte(size))
+	case size <= 0xFFFF:
+		frame = binary.BigEndian.AppendUint16(append(frame, mask|126), uint16(size))
+	default:
+		frame = binary.BigEndian.AppendUint64(append(frame, mask|127), uint64(size))
+	}
+	if c.client {
+		key := make([]byte, 4)
+		rand.Read(key)
+		frame = append(frame, key...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		for i := range payload {
+			frame[start+i] ^= key[i%4]
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return errors.New("websocket: close frame already sent")
+	}
+	c.closed = opcode == wsClose
+	_, err := c.Conn.Write(frame)
+	return err
+}
+
+// writeClose starts the closing handshake, code 0 sends a close frame without status
+func (c *wsConn) writeClose(code int, reason string) error {
+	var payload []byte
+	if code > 0 {
+		payload = append(binary.BigEndian.AppendUint16(nil, uint16(code)), reason...)
+	}
+	return c.writeFrame(wsClose, payload)
+}
+
+func (c *wsConn) isClosing() bool {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	return c.closed
+}
+
+func (c *wsConn) setOpcode(opcode byte) {
+	c.wmu.Lock()
+	c.opcode = opcode
+	c.wmu.Unlock()
+}
+
+// Close sends a going away close frame unless the closing handshake already started
+func (c *wsConn) Close() error {
+	if !c.isClosing() {
+		c.Conn.SetWriteDeadline(time.Now().Add(time.Second))
+		c.writeClose(1001, "")
+	}
+	return c.Conn.Close()
+}
+
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(c.reader, head[:]); err != nil {
+		return
+	}
+	fin, opcode = head[0]&0x80 != 0, head[0]&0x0F
+	size := uint64(head[1] & 0x7F)
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.reader, ext[:]); err != nil {
+			return
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.reader, ext[:]); err != nil {
+			return
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+	if size > wsMaxFrame {
+		err = fmt.Errorf("websocket frame of %d bytes is too large", size)
+		return
+	}
+	var key [4]byte
+	masked := head[1]&0x80 != 0
+	if masked {
+		if _, err = io.ReadFull(c.reader, key[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(c.reader, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+	return
+}
+
+// Read returns the payload of data frames
+func (c *wsConn) Read(p []byte) (int, error) {
+	for len(c.readBuf) == 0 {
+		_, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		switch opcode {
+		case wsContinuation, wsText, wsBinary:
+			c.readBuf = payload
+		case wsPing:
+			c.writeFrame(wsPong, payload)
+		case wsClose:
+			if !c.isClosing() {
+				c.writeFrame(wsClose, payload[:min(2, len(payload))])
+			}
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+// Write sends p as one message of the current frame type
+func (c *wsConn) Write(p []byte) (int, error) {
+	c.wmu.Lock()
+	opcode := c.opcode
+	c.wmu.Unlock()
+	if err := c.writeFrame(opcode, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}